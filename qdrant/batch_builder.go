@@ -0,0 +1,311 @@
+// This file contains BatchBuilder, which chains point update operations and executes them with UpdateBatch,
+// splitting them into several requests when they would exceed the request size or strict mode limits.
+//
+// USAGE:
+//
+//	results, err := client.NewBatchBuilder("my_collection").
+//		Upsert(points...).
+//		SetPayload(qdrant.NewPointsSelector(qdrant.NewIDNum(1)), qdrant.NewValueMap(map[string]any{"seen": true})).
+//		Delete(qdrant.NewPointsSelectorFilter(filter)).
+//		Execute(ctx)
+
+package qdrant
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// Default maximum size of a request, the default max_request_size_mb of the Qdrant server.
+	defaultMaxRequestBytes = 32 << 20
+	// Upper bound of the bytes added by nesting a list of points in an operation:
+	// the growth of the length prefixes of the list and of the operation.
+	nestingOverhead = 2 * binary.MaxVarintLen32
+)
+
+// BatchBuilder chains point update operations on a collection, and executes them in order with UpdateBatch.
+// Obtain one with Client.NewBatchBuilder.
+//
+// When the operations would exceed the maximum request size or the upsert_max_batchsize of the strict mode
+// of the collection, they are split into several UpdateBatch requests, sent one after the other.
+// Upsert and UpdateVectors operations with too many points are also split into several operations.
+// The operations are not applied atomically: if a request fails, the previous ones are already applied.
+type BatchBuilder struct {
+	client          *Client
+	collectionName  string
+	operations      []*PointsUpdateOperation
+	wait            *bool
+	ordering        *WriteOrdering
+	maxRequestBytes int
+}
+
+// NewBatchBuilder returns an empty BatchBuilder for the points of a collection.
+func (c *Client) NewBatchBuilder(collectionName string) *BatchBuilder {
+	maxRequestBytes := defaultMaxRequestBytes
+	if c.maxRequestSize > 0 {
+		maxRequestBytes = c.maxRequestSize
+	}
+	return &BatchBuilder{
+		client:          c,
+		collectionName:  collectionName,
+		maxRequestBytes: maxRequestBytes,
+	}
+}
+
+// Add adds an operation built with one of the NewPointsUpdate functions.
+func (b *BatchBuilder) Add(operation *PointsUpdateOperation) *BatchBuilder {
+	b.operations = append(b.operations, operation)
+	return b
+}
+
+// Upsert adds an operation inserting or replacing points.
+func (b *BatchBuilder) Upsert(points ...*PointStruct) *BatchBuilder {
+	return b.Add(NewPointsUpdateUpsert(&PointsUpdateOperation_PointStructList{Points: points}))
+}
+
+// Delete adds an operation deleting the selected points.
+func (b *BatchBuilder) Delete(selector *PointsSelector) *BatchBuilder {
+	return b.Add(NewPointsUpdateDeletePoints(&PointsUpdateOperation_DeletePoints{Points: selector}))
+}
+
+// SetPayload adds an operation setting payload keys of the selected points, keeping the other keys.
+func (b *BatchBuilder) SetPayload(selector *PointsSelector, payload map[string]*Value) *BatchBuilder {
+	return b.Add(NewPointsUpdateSetPayload(&PointsUpdateOperation_SetPayload{
+		Payload:        payload,
+		PointsSelector: selector,
+	}))
+}
+
+// DeletePayload adds an operation deleting payload keys of the selected points.
+func (b *BatchBuilder) DeletePayload(selector *PointsSelector, keys ...string) *BatchBuilder {
+	return b.Add(NewPointsUpdateDeletePayload(&PointsUpdateOperation_DeletePayload{
+		Keys:           keys,
+		PointsSelector: selector,
+	}))
+}
+
+// ClearPayload adds an operation removing the whole payload of the selected points.
+func (b *BatchBuilder) ClearPayload(selector *PointsSelector) *BatchBuilder {
+	return b.Add(NewPointsUpdateClearPayload(&PointsUpdateOperation_ClearPayload{Points: selector}))
+}
+
+// UpdateVectors adds an operation updating vectors of points, keeping their other vectors.
+func (b *BatchBuilder) UpdateVectors(points ...*PointVectors) *BatchBuilder {
+	return b.Add(NewPointsUpdateUpdateVectors(&PointsUpdateOperation_UpdateVectors{Points: points}))
+}
+
+// WithWait sets whether each request waits for its operations to be applied.
+func (b *BatchBuilder) WithWait(wait bool) *BatchBuilder {
+	b.wait = &wait
+	return b
+}
+
+// WithOrdering sets the write ordering of the requests.
+func (b *BatchBuilder) WithOrdering(ordering *WriteOrdering) *BatchBuilder {
+	b.ordering = ordering
+	return b
+}
+
+// WithMaxRequestSize sets the maximum size in bytes of a request, e.g. to match the max_request_size_mb
+// of the server. Defaults to Config.MaxRequestSize if set, and to 32 MiB otherwise.
+// An operation larger than the maximum that cannot be split, such as a single large point, is sent alone.
+func (b *BatchBuilder) WithMaxRequestSize(bytes int) *BatchBuilder {
+	b.maxRequestBytes = bytes
+	return b
+}
+
+// Len returns the number of operations added.
+func (b *BatchBuilder) Len() int {
+	return len(b.operations)
+}
+
+// Executes the operations in order, in as few UpdateBatch requests as the limits allow.
+//
+// Parameters:
+//   - ctx: The context for the request.
+//
+// Returns:
+//   - []*UpdateResult: The result of each added operation, in order.
+//     The result of an operation that was split is the result of its last part.
+//     If a request fails, the results of the operations that were not applied are nil.
+//   - error: An error if fetching the collection info or a request fails.
+func (b *BatchBuilder) Execute(ctx context.Context) ([]*UpdateResult, error) {
+	results := make([]*UpdateResult, len(b.operations))
+	if len(b.operations) == 0 {
+		return results, nil
+	}
+	var maxPoints uint64
+	if hasPointsOperation(b.operations) {
+		schema, err := b.client.getCollectionSchema(ctx, b.collectionName)
+		if err != nil {
+			return results, newQdrantErr(err, "BatchBuilder", b.collectionName)
+		}
+		if schema.strictMode.GetEnabled() {
+			maxPoints = schema.strictMode.GetUpsertMaxBatchsize()
+		}
+	}
+	base := &UpdateBatchPoints{
+		CollectionName: b.collectionName,
+		Wait:           b.wait,
+		Ordering:       b.ordering,
+	}
+	splitter := &batchSplitter{
+		maxBytes:  b.maxRequestBytes - proto.Size(base),
+		maxPoints: maxPoints,
+	}
+	for _, request := range splitter.split(b.operations) {
+		operations := make([]*PointsUpdateOperation, len(request))
+		for i, part := range request {
+			operations[i] = part.operation
+		}
+		batch := proto.CloneOf(base)
+		batch.Operations = operations
+		batchResults, err := b.client.UpdateBatch(ctx, batch)
+		if err != nil {
+			return results, newQdrantErr(err, "BatchBuilder", b.collectionName,
+				fmt.Sprintf("operations %d to %d", request[0].source, request[len(request)-1].source))
+		}
+		for i, result := range batchResults {
+			if i < len(request) {
+				results[request[i].source] = result
+			}
+		}
+	}
+	return results, nil
+}
+
+// Internal method.
+func hasPointsOperation(operations []*PointsUpdateOperation) bool {
+	for _, operation := range operations {
+		if operation.GetUpsert() != nil || operation.GetUpdateVectors() != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Internal type holding an operation, or a part of a split operation, and the index of the operation it comes from.
+type batchPart struct {
+	source    int
+	operation *PointsUpdateOperation
+	// Size of the operation in a request, including its tag and length prefix.
+	size   int
+	points uint64
+}
+
+// Internal type splitting operations into requests.
+type batchSplitter struct {
+	// Maximum size of the operations of a request.
+	maxBytes int
+	// Maximum number of upserted and updated points of a request. 0 for no limit.
+	maxPoints uint64
+}
+
+// Internal method.
+// Returns the operations grouped into requests, in order.
+func (s *batchSplitter) split(operations []*PointsUpdateOperation) [][]*batchPart {
+	var requests [][]*batchPart
+	var current []*batchPart
+	var size int
+	var points uint64
+	for i, operation := range operations {
+		for _, part := range s.splitOperation(i, operation) {
+			if len(current) > 0 && (size+part.size > s.maxBytes || s.maxPoints > 0 && points+part.points > s.maxPoints) {
+				requests = append(requests, current)
+				current, size, points = nil, 0, 0
+			}
+			current = append(current, part)
+			size += part.size
+			points += part.points
+		}
+	}
+	if len(current) > 0 {
+		requests = append(requests, current)
+	}
+	return requests
+}
+
+// Internal method.
+// Splits an Upsert or UpdateVectors operation whose points exceed the limits into several operations.
+// Other operations are returned as is.
+func (s *batchSplitter) splitOperation(source int, operation *PointsUpdateOperation) []*batchPart {
+	var points []proto.Message
+	var withPoints func(points []proto.Message) *PointsUpdateOperation
+	switch {
+	case operation.GetUpsert() != nil:
+		upsert := operation.GetUpsert()
+		points = toMessages(upsert.GetPoints())
+		withPoints = func(part []proto.Message) *PointsUpdateOperation {
+			list := proto.CloneOf(&PointsUpdateOperation_PointStructList{
+				ShardKeySelector: upsert.GetShardKeySelector(),
+				UpdateFilter:     upsert.UpdateFilter,
+				UpdateMode:       upsert.UpdateMode,
+			})
+			list.Points = fromMessages[*PointStruct](part)
+			return NewPointsUpdateUpsert(list)
+		}
+	case operation.GetUpdateVectors() != nil:
+		update := operation.GetUpdateVectors()
+		points = toMessages(update.GetPoints())
+		withPoints = func(part []proto.Message) *PointsUpdateOperation {
+			list := proto.CloneOf(&PointsUpdateOperation_UpdateVectors{
+				ShardKeySelector: update.GetShardKeySelector(),
+				UpdateFilter:     update.UpdateFilter,
+			})
+			list.Points = fromMessages[*PointVectors](part)
+			return NewPointsUpdateUpdateVectors(list)
+		}
+	default:
+		return []*batchPart{{source: source, operation: operation, size: operationSize(operation)}}
+	}
+	size := operationSize(operation)
+	if size <= s.maxBytes && (s.maxPoints == 0 || uint64(len(points)) <= s.maxPoints) {
+		return []*batchPart{{source: source, operation: operation, size: size, points: uint64(len(points))}}
+	}
+	emptySize := operationSize(withPoints(nil)) + nestingOverhead
+	var parts []*batchPart
+	start, partSize := 0, emptySize
+	for i, point := range points {
+		pointSize := protowire.SizeTag(1) + protowire.SizeBytes(proto.Size(point))
+		count := uint64(i - start)
+		if i > start && (partSize+pointSize > s.maxBytes || s.maxPoints > 0 && count >= s.maxPoints) {
+			parts = append(parts, &batchPart{
+				source: source, operation: withPoints(points[start:i]), size: partSize, points: count,
+			})
+			start, partSize = i, emptySize
+		}
+		partSize += pointSize
+	}
+	return append(parts, &batchPart{
+		source: source, operation: withPoints(points[start:]), size: partSize, points: uint64(len(points) - start),
+	})
+}
+
+// Internal method.
+// Returns the size of an operation in the operations of a request.
+func operationSize(operation *PointsUpdateOperation) int {
+	return protowire.SizeTag(1) + protowire.SizeBytes(proto.Size(operation))
+}
+
+// Internal method.
+func toMessages[T proto.Message](values []T) []proto.Message {
+	messages := make([]proto.Message, len(values))
+	for i, value := range values {
+		messages[i] = value
+	}
+	return messages
+}
+
+// Internal method.
+func fromMessages[T proto.Message](messages []proto.Message) []T {
+	values := make([]T, len(messages))
+	for i, message := range messages {
+		values[i], _ = message.(T)
+	}
+	return values
+}
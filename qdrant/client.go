@@ -1,8 +1,11 @@
 package qdrant
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"maps"
+	"slices"
 	"sync"
 	"sync/atomic"
 
@@ -16,6 +19,14 @@ type Client struct {
 	clients   []*GrpcClient
 	next      uint32
 	closeOnce sync.Once
+	tracker   *inflightTracker
+	schemas   *collectionSchemaCache
+	// Whether to validate the vectors of upserted and updated points before sending them.
+	validateVectors bool
+	// Whether to normalize the vectors sent for the Cosine vectors of a collection.
+	normalizeVectors bool
+	// Size in bytes above which requests are split. 0 to disable splitting.
+	maxRequestSize int
 }
 
 // NewClient creates a new Qdrant client.
@@ -26,12 +37,19 @@ func NewClient(config *Config) (*Client, error) {
 	// Ensure config is not modified for the caller by cloning.
 	cfgCopy := *config
 	cfgCopy.Headers = maps.Clone(config.Headers)
+	cfgCopy.RequestOptions = slices.Clone(config.RequestOptions)
 	if cfgCopy.PoolSize == 0 {
 		cfgCopy.PoolSize = 3
 	}
 	// Create the client, with an inner connection pool of go grpc clients
 	client := &Client{
 		clients: make([]*GrpcClient, 0, cfgCopy.PoolSize),
+		tracker: newInflightTracker(),
+		schemas: newCollectionSchemaCache(cfgCopy.getPayloadSchemaCacheTTL()),
+
+		validateVectors:  cfgCopy.ValidateVectors,
+		normalizeVectors: cfgCopy.NormalizeCosineVectors,
+		maxRequestSize:   cfgCopy.MaxRequestSize,
 	}
 	// Iterate over the pool size to create the individual client.
 	for i := range cfgCopy.PoolSize {
@@ -39,7 +57,7 @@ func NewClient(config *Config) (*Client, error) {
 			// In case of a pool, we only want to check compatibility once.
 			cfgCopy.SkipCompatibilityCheck = true
 		}
-		grpcClient, err := NewGrpcClient(&cfgCopy)
+		grpcClient, err := newGrpcClient(&cfgCopy, client.tracker.unaryInterceptor())
 		if err != nil {
 			// Close already opened clients before returning an error
 			client.Close()
@@ -103,10 +121,12 @@ func (c *Client) GetConnection() *grpc.ClientConn {
 	return c.get().Conn()
 }
 
-// Close tears down all underlying connections.
+// Close tears down all underlying connections immediately, cancelling in-flight requests.
+// Use Shutdown to wait for in-flight requests to finish first.
 func (c *Client) Close() error {
 	var lastErr error
 	c.closeOnce.Do(func() {
+		c.tracker.close()
 		for _, client := range c.clients {
 			if err := client.Close(); err != nil {
 				lastErr = err
@@ -116,6 +136,30 @@ func (c *Client) Close() error {
 	return lastErr
 }
 
+// Shutdown gracefully closes the client.
+// It stops accepting new requests, which fail with ErrClientClosed, and waits for
+// in-flight requests and ScrollIterators to finish or for ctx to be done,
+// whichever happens first. The underlying connections are closed afterwards.
+//
+// A ScrollIterator is considered in flight until it returns io.EOF, fails, or is closed.
+//
+// Returns:
+//   - int: The number of requests and iterators that were still in flight when ctx was done,
+//     and have been aborted by closing the connections.
+//   - error: ctx.Err() if ctx was done before draining, joined with any error from closing the connections.
+func (c *Client) Shutdown(ctx context.Context) (int, error) {
+	drained := c.tracker.close()
+	var aborted int
+	var ctxErr error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		aborted = c.tracker.outstanding()
+		ctxErr = ctx.Err()
+	}
+	return aborted, errors.Join(ctxErr, c.Close())
+}
+
 // Creates a pointer to a value of any type.
 func PtrOf[T any](t T) *T {
 	return &t
@@ -0,0 +1,146 @@
+// This file contains conditional writes of single points, built on the update_filter and update_mode
+// of UpsertPoints, so that concurrent writers cannot silently overwrite each other.
+//
+// USAGE:
+//
+//	point := &qdrant.PointStruct{
+//		Id:      qdrant.NewIDNum(1),
+//		Vectors: qdrant.NewVectors(0.1, 0.2),
+//		Payload: qdrant.NewValueMap(map[string]any{"status": "done"}),
+//	}
+//	applied, err := client.CompareAndSwap(ctx, "my_collection", point, "version", 3)
+//	if err == nil && !applied {
+//		// Another writer updated the point first: get it again and retry.
+//	}
+
+package qdrant
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Replaces a point only if the integer version stored in its payload under versionField is expectedVersion.
+// The version of the written point is set to expectedVersion + 1, so that concurrent writers
+// reading the same version cannot both apply their write.
+// Use InsertIfAbsent to create the point with an initial version.
+//
+// Parameters:
+//   - ctx: The context for the request.
+//   - collectionName: The name of the collection.
+//   - point: The new point. It is not modified.
+//   - versionField: The payload key of the version.
+//   - expectedVersion: The version the point must have for the write to apply.
+//
+// Returns:
+//   - bool: Whether the write applied, checked by reading the point afterwards.
+//     False if the point does not exist or has another version.
+//   - error: An error if the operation fails.
+func (c *Client) CompareAndSwap(ctx context.Context, collectionName string, point *PointStruct, versionField string,
+	expectedVersion int64,
+) (bool, error) {
+	payload := maps.Clone(point.GetPayload())
+	if payload == nil {
+		payload = make(map[string]*Value)
+	}
+	payload[versionField] = NewValueInt(expectedVersion + 1)
+	swapped := &PointStruct{
+		Id:      point.GetId(),
+		Vectors: point.GetVectors(),
+		Payload: payload,
+	}
+	filter := &Filter{Must: []*Condition{NewMatchInt(versionField, expectedVersion)}}
+	applied, err := c.conditionalUpsert(ctx, collectionName, swapped, filter, UpdateMode_UpdateOnly)
+	if err != nil {
+		return false, newQdrantErr(err, "CompareAndSwap", collectionName)
+	}
+	return applied, nil
+}
+
+// Inserts a point only if no point with its ID exists.
+//
+// Parameters:
+//   - ctx: The context for the request.
+//   - collectionName: The name of the collection.
+//   - point: The point to insert.
+//
+// Returns:
+//   - bool: Whether the write applied, checked by reading the point afterwards.
+//     False if a point with another payload already exists. An existing point with the same payload
+//     cannot be told apart from the inserted point, and is reported as applied.
+//   - error: An error if the operation fails.
+func (c *Client) InsertIfAbsent(ctx context.Context, collectionName string, point *PointStruct) (bool, error) {
+	applied, err := c.conditionalUpsert(ctx, collectionName, point, nil, UpdateMode_InsertOnly)
+	if err != nil {
+		return false, newQdrantErr(err, "InsertIfAbsent", collectionName)
+	}
+	return applied, nil
+}
+
+// Replaces a point only if a point with its ID exists.
+//
+// Parameters:
+//   - ctx: The context for the request.
+//   - collectionName: The name of the collection.
+//   - point: The new point.
+//
+// Returns:
+//   - bool: Whether the write applied, checked by reading the point afterwards.
+//     False if the point does not exist, or was replaced by another writer in the meantime.
+//   - error: An error if the operation fails.
+func (c *Client) UpdateIfExists(ctx context.Context, collectionName string, point *PointStruct) (bool, error) {
+	applied, err := c.conditionalUpsert(ctx, collectionName, point, nil, UpdateMode_UpdateOnly)
+	if err != nil {
+		return false, newQdrantErr(err, "UpdateIfExists", collectionName)
+	}
+	return applied, nil
+}
+
+// Internal method.
+// Upserts a single point with an update filter and mode, waiting for the write,
+// and returns whether the point has the written payload afterwards.
+func (c *Client) conditionalUpsert(ctx context.Context, collectionName string, point *PointStruct, filter *Filter,
+	mode UpdateMode,
+) (bool, error) {
+	if point.GetId().GetPointIdOptions() == nil {
+		return false, errors.New("point without ID")
+	}
+	_, err := c.Upsert(ctx, &UpsertPoints{
+		CollectionName: collectionName,
+		Wait:           PtrOf(true),
+		Points:         []*PointStruct{point},
+		UpdateFilter:   filter,
+		UpdateMode:     mode.Enum(),
+	})
+	if err != nil {
+		return false, err
+	}
+	keys := slices.Collect(maps.Keys(point.GetPayload()))
+	withPayload := NewWithPayload(false)
+	if len(keys) > 0 {
+		withPayload = NewWithPayloadInclude(keys...)
+	}
+	stored, err := c.Get(ctx, &GetPoints{
+		CollectionName:  collectionName,
+		Ids:             []*PointId{point.GetId()},
+		WithPayload:     withPayload,
+		WithVectors:     NewWithVectors(false),
+		ReadConsistency: NewReadConsistency(ReadConsistencyType_All),
+	})
+	if err != nil {
+		return false, err
+	}
+	if len(stored) == 0 {
+		return false, nil
+	}
+	for key, value := range point.GetPayload() {
+		if !proto.Equal(value, stored[0].GetPayload()[key]) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
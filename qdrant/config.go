@@ -23,6 +23,8 @@ const (
 	defaultHost                = "localhost"
 	defaultPort                = 6334
 	defaultVersionCheckTimeout = time.Minute
+	defaultDeadlineMargin      = 500 * time.Millisecond
+	defaultSchemaCacheTTL      = time.Minute
 )
 
 // Configuration options for the client.
@@ -38,6 +40,9 @@ type Config struct {
 	// TLS configuration to use for the connection.
 	// If not provided, uses default config with minimum TLS version set to 1.3
 	TLSConfig *tls.Config
+	// Mutual TLS configuration loaded from PEM files on disk, reloaded when the files change.
+	// If set, TLS is used regardless of UseTLS and TLSConfig serves as the base config.
+	MTLS *MTLSConfig
 	// Additional gRPC options to use for the connection.
 	GrpcOptions []grpc.DialOption
 	// Whether to check compatibility between server's version and client's. Defaults to false.
@@ -68,6 +73,57 @@ type Config struct {
 	VersionCheckTimeout time.Duration
 	// Headers specifies optional headers to send with every gRPC request.
 	Headers map[string]string
+	// RequestOptions specifies default options, such as read consistency or write ordering,
+	// applied to every request that supports them. See RequestOption.
+	RequestOptions []RequestOption
+	// DisableDeadlinePropagation disables deriving the server-side timeout from the context deadline.
+	// By default, requests that support a timeout and don't have one set get the time remaining
+	// until the context deadline minus DeadlineSafetyMargin, rounded down to whole seconds (at least 1 second).
+	// This stops the server from working on requests the client has already given up on.
+	// Disable it if the server enforces a strict mode max_timeout lower than the deadlines used.
+	DisableDeadlinePropagation bool
+	// DeadlineSafetyMargin is subtracted from the time remaining until the context deadline
+	// when deriving the server-side timeout.
+	// If 0, defaults to 500 milliseconds.
+	DeadlineSafetyMargin time.Duration
+	// RequestLogger enables structured logging of every request with slog.
+	// If nil, requests are not logged.
+	RequestLogger *RequestLogConfig
+	// UsageCollector accumulates the hardware and inference usage reported in the responses
+	// of every request. If nil, usage is only collected for contexts set up with WithUsageCollector.
+	UsageCollector *UsageCollector
+	// PayloadSchemaCacheTTL specifies how long the payload schema, vectors config and strict mode config
+	// of a collection fetched by ValidateFilter and ValidatePoints are cached.
+	// If 0, defaults to 1 minute. If negative, they are fetched on every validation.
+	PayloadSchemaCacheTTL time.Duration
+	// Embedders computes vectors on the client side for the Document, Image and InferenceObject inputs
+	// of every request, e.g. in upserted points, queries and prefetches, keyed by model name.
+	// The embedder registered under "" is used for the models without their own.
+	// Inputs of models without an embedder are sent as is, for server-side inference.
+	Embedders map[string]Embedder
+	// EmbeddingBatchSize specifies the maximum number of inputs passed to a single Embed call.
+	// If 0, defaults to 64.
+	EmbeddingBatchSize int
+	// ValidateVectors enables checking the vectors of the points sent with Upsert, UpdateVectors
+	// and UpdateBatch against the vectors config of the collection, before sending them.
+	// Invalid points are reported with a *VectorValidationError. See Client.ValidatePoints.
+	// Defaults to false.
+	ValidateVectors bool
+	// NormalizeCosineVectors enables normalizing the dense and multi-dense vectors sent with Upsert,
+	// UpdateVectors, UpdateBatch, Query, QueryBatch and QueryGroups for the vectors of the collection
+	// configured with the Cosine distance, as the server does, so that stored and returned vectors
+	// match local similarity computations. Vectors computed by an Embedder are not normalized.
+	// Defaults to false.
+	NormalizeCosineVectors bool
+	// MaxRequestSize enables splitting the Upsert, UpdateVectors, Get, QueryBatch and SetPayload
+	// with an ID selector requests larger than this number of bytes, e.g. to stay below the maximum
+	// message size of the server, into several requests sent one after the other.
+	// Their results are merged in order. The parts of a split update are not applied atomically:
+	// if a part fails, the previous parts are already applied.
+	// Requests are measured as sent, after the request options are applied and the inference inputs embedded.
+	// It is also the default maximum request size of the BatchBuilders of the client.
+	// Defaults to 0, no splitting.
+	MaxRequestSize int
 }
 
 // Internal method.
@@ -79,11 +135,32 @@ func (c *Config) getVersionCheckTimeout() time.Duration {
 }
 
 // Internal method.
-func (c *Config) getAddr() string {
-	host := c.Host
-	if host == "" {
-		host = defaultHost
+func (c *Config) getDeadlineSafetyMargin() time.Duration {
+	if c.DeadlineSafetyMargin > 0 {
+		return c.DeadlineSafetyMargin
+	}
+	return defaultDeadlineMargin
+}
+
+// Internal method.
+func (c *Config) getPayloadSchemaCacheTTL() time.Duration {
+	if c.PayloadSchemaCacheTTL == 0 {
+		return defaultSchemaCacheTTL
 	}
+	return max(c.PayloadSchemaCacheTTL, 0)
+}
+
+// Internal method.
+func (c *Config) getHost() string {
+	if c.Host == "" {
+		return defaultHost
+	}
+	return c.Host
+}
+
+// Internal method.
+func (c *Config) getAddr() string {
+	host := c.getHost()
 	port := c.Port
 	if port == 0 {
 		port = defaultPort
@@ -110,18 +187,34 @@ func (c *Config) getKeepAliveParams() []grpc.DialOption {
 }
 
 // Internal method.
-func (c *Config) getTransportCreds() grpc.DialOption {
+func (c *Config) getTransportCreds() (grpc.DialOption, error) {
+	if c.MTLS != nil {
+		base := c.TLSConfig
+		if c.MTLS.ServerName == "" && (base == nil || base.ServerName == "") {
+			if base != nil {
+				base = base.Clone()
+			} else {
+				base = &tls.Config{MinVersion: tls.VersionTLS13}
+			}
+			base.ServerName = c.getHost()
+		}
+		tlsConfig, err := c.MTLS.ClientTLSConfig(base)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure mutual TLS: %w", err)
+		}
+		return grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)), nil
+	}
 	if c.UseTLS {
 		if c.TLSConfig == nil {
 			return grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
 				MinVersion: tls.VersionTLS13,
-			}))
+			})), nil
 		}
-		return grpc.WithTransportCredentials(credentials.NewTLS(c.TLSConfig))
+		return grpc.WithTransportCredentials(credentials.NewTLS(c.TLSConfig)), nil
 	} else if c.APIKey != "" {
 		slog.Default().Warn("API key is being used without TLS(HTTPS). It will be transmitted in plaintext.")
 	}
-	return grpc.WithTransportCredentials(insecure.NewCredentials())
+	return grpc.WithTransportCredentials(insecure.NewCredentials()), nil
 }
 
 // Internal method.
@@ -175,6 +268,14 @@ func (c *Config) getRateLimitInterceptor() grpc.DialOption {
 	})
 }
 
+// Internal method.
+func (c *Config) getLoggingInterceptor() []grpc.DialOption {
+	if c.RequestLogger == nil {
+		return nil
+	}
+	return []grpc.DialOption{grpc.WithChainUnaryInterceptor(c.RequestLogger.loggingInterceptor())}
+}
+
 // Internal method.
 func getClientKeepAliveParams(keepAliveTime, keepAliveTimeout int) grpc.DialOption {
 	return grpc.WithKeepaliveParams(keepalive.ClientParameters{
@@ -0,0 +1,242 @@
+// This file contains client-side inference: Document, Image and InferenceObject inputs
+// are embedded locally by the Embedder registered for their model, instead of by the server.
+//
+// USAGE:
+//
+//	client, err := qdrant.NewClient(&qdrant.Config{
+//		Embedders: map[string]qdrant.Embedder{
+//			"my-model": qdrant.EmbedderFunc(func(ctx context.Context, model string,
+//				inputs []*qdrant.EmbeddingInput) ([]*qdrant.Vector, error) {
+//				...
+//			}),
+//		},
+//	})
+//	points, err := client.Query(ctx, &qdrant.QueryPoints{
+//		CollectionName: "my_collection",
+//		Query: qdrant.NewQueryNearest(qdrant.NewVectorInputDocument(&qdrant.Document{
+//			Text:  "hello",
+//			Model: "my-model",
+//		})),
+//	})
+
+package qdrant
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const defaultEmbeddingBatchSize = 64
+
+// Embedder computes vectors from inference inputs on the client side.
+// Register embedders per model with Config.Embedders.
+type Embedder interface {
+	// Embed returns a dense, sparse or multi-dense vector for each input, in the same order.
+	// All the inputs of a call are for the given model.
+	Embed(ctx context.Context, model string, inputs []*EmbeddingInput) ([]*Vector, error)
+}
+
+// EmbedderFunc adapts a function to the Embedder interface.
+type EmbedderFunc func(ctx context.Context, model string, inputs []*EmbeddingInput) ([]*Vector, error)
+
+// Embed calls f.
+func (f EmbedderFunc) Embed(ctx context.Context, model string, inputs []*EmbeddingInput) ([]*Vector, error) {
+	return f(ctx, model, inputs)
+}
+
+// EmbeddingInput is an input to embed. Exactly one of its fields is set.
+type EmbeddingInput struct {
+	Document *Document
+	Image    *Image
+	Object   *InferenceObject
+}
+
+// GetModel returns the model of the input.
+func (i *EmbeddingInput) GetModel() string {
+	switch {
+	case i.Document != nil:
+		return i.Document.GetModel()
+	case i.Image != nil:
+		return i.Image.GetModel()
+	}
+	return i.Object.GetModel()
+}
+
+// Internal type pointing at an inference input in a request, to replace it with its vector.
+// Parent is either a *Vector or a *VectorInput.
+type embeddingSite struct {
+	parent proto.Message
+	input  *EmbeddingInput
+}
+
+// Internal method.
+func (c *Config) getEmbedder(model string) Embedder {
+	if embedder, ok := c.Embedders[model]; ok {
+		return embedder
+	}
+	return c.Embedders[""]
+}
+
+// Internal method.
+func (c *Config) getEmbeddingBatchSize() int {
+	if c.EmbeddingBatchSize > 0 {
+		return c.EmbeddingBatchSize
+	}
+	return defaultEmbeddingBatchSize
+}
+
+// Internal method.
+func (c *Config) getEmbeddingInterceptor() grpc.DialOption {
+	return grpc.WithChainUnaryInterceptor(func(
+		ctx context.Context,
+		method string,
+		req,
+		reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption) error {
+		msg, ok := req.(proto.Message)
+		if !ok || len(c.Embedders) == 0 {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		embedded, err := c.embedRequest(ctx, msg)
+		if err != nil {
+			return err
+		}
+		return invoker(ctx, method, embedded, reply, cc, opts...)
+	})
+}
+
+// Internal method.
+// Returns a copy of msg in which the inference inputs with a registered embedder are replaced
+// with their vectors, or msg itself if there are none, so that the caller's request is not modified.
+func (c *Config) embedRequest(ctx context.Context, msg proto.Message) (proto.Message, error) {
+	found := false
+	c.findEmbeddingSites(msg.ProtoReflect(), func(embeddingSite) { found = true })
+	if !found {
+		return msg, nil
+	}
+	embedded := proto.Clone(msg)
+	byModel := make(map[string][]embeddingSite)
+	var models []string
+	c.findEmbeddingSites(embedded.ProtoReflect(), func(site embeddingSite) {
+		model := site.input.GetModel()
+		if _, ok := byModel[model]; !ok {
+			models = append(models, model)
+		}
+		byModel[model] = append(byModel[model], site)
+	})
+	batchSize := c.getEmbeddingBatchSize()
+	for _, model := range models {
+		sites := byModel[model]
+		embedder := c.getEmbedder(model)
+		for start := 0; start < len(sites); start += batchSize {
+			batch := sites[start:min(start+batchSize, len(sites))]
+			if err := embedBatch(ctx, embedder, model, batch); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return embedded, nil
+}
+
+// Internal method.
+func embedBatch(ctx context.Context, embedder Embedder, model string, sites []embeddingSite) error {
+	inputs := make([]*EmbeddingInput, len(sites))
+	for i, site := range sites {
+		inputs[i] = site.input
+	}
+	vectors, err := embedder.Embed(ctx, model, inputs)
+	if err != nil {
+		return fmt.Errorf("failed to embed inputs with model %q: %w", model, err)
+	}
+	if len(vectors) != len(inputs) {
+		return fmt.Errorf("embedder for model %q returned %d vectors for %d inputs", model, len(vectors), len(inputs))
+	}
+	for i, site := range sites {
+		if err := site.replace(vectors[i]); err != nil {
+			return fmt.Errorf("embedder for model %q: %w", model, err)
+		}
+	}
+	return nil
+}
+
+// Internal method.
+func (s embeddingSite) replace(vector *Vector) error {
+	switch parent := s.parent.(type) {
+	case *Vector:
+		switch vector.GetVector().(type) {
+		case *Vector_Dense, *Vector_Sparse, *Vector_MultiDense:
+			parent.Vector = vector.GetVector()
+			return nil
+		}
+	case *VectorInput:
+		switch v := vector.GetVector().(type) {
+		case *Vector_Dense:
+			parent.Variant = &VectorInput_Dense{Dense: v.Dense}
+			return nil
+		case *Vector_Sparse:
+			parent.Variant = &VectorInput_Sparse{Sparse: v.Sparse}
+			return nil
+		case *Vector_MultiDense:
+			parent.Variant = &VectorInput_MultiDense{MultiDense: v.MultiDense}
+			return nil
+		}
+	}
+	return fmt.Errorf("expected a dense, sparse or multi-dense vector, got %T", vector.GetVector())
+}
+
+// Internal method.
+// Calls visit for every inference input in msg that has a registered embedder.
+func (c *Config) findEmbeddingSites(msg protoreflect.Message, visit func(embeddingSite)) {
+	var input *EmbeddingInput
+	switch m := msg.Interface().(type) {
+	case *Vector:
+		input = newEmbeddingInput(m.GetDocument(), m.GetImage(), m.GetObject())
+	case *VectorInput:
+		input = newEmbeddingInput(m.GetDocument(), m.GetImage(), m.GetObject())
+	case *Value, *Filter:
+		// Payloads and filters never contain vectors.
+		return
+	}
+	if input != nil {
+		if c.getEmbedder(input.GetModel()) != nil {
+			visit(embeddingSite{parent: msg.Interface(), input: input})
+		}
+		return
+	}
+	msg.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.Message() == nil:
+		case fd.IsList():
+			list := v.List()
+			for i := range list.Len() {
+				c.findEmbeddingSites(list.Get(i).Message(), visit)
+			}
+		case fd.IsMap():
+			if fd.MapValue().Message() == nil {
+				return true
+			}
+			v.Map().Range(func(_ protoreflect.MapKey, value protoreflect.Value) bool {
+				c.findEmbeddingSites(value.Message(), visit)
+				return true
+			})
+		default:
+			c.findEmbeddingSites(v.Message(), visit)
+		}
+		return true
+	})
+}
+
+// Internal method.
+// Returns nil if no input is set.
+func newEmbeddingInput(document *Document, image *Image, object *InferenceObject) *EmbeddingInput {
+	if document == nil && image == nil && object == nil {
+		return nil
+	}
+	return &EmbeddingInput{Document: document, Image: image, Object: object}
+}
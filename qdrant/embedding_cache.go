@@ -0,0 +1,228 @@
+// This file contains a cache of the vectors computed by an Embedder,
+// so that repeated queries and re-ingested documents are not embedded again.
+//
+// USAGE:
+//
+//	cached := qdrant.NewCachedEmbedder(embedder, qdrant.NewLRUEmbeddingStore(10000, time.Hour))
+//	client, err := qdrant.NewClient(&qdrant.Config{
+//		Embedders: map[string]qdrant.Embedder{"my-model": cached},
+//	})
+//	...
+//	stats := cached.Stats()
+
+package qdrant
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EmbeddingStore stores the vectors cached by a CachedEmbedder.
+// Implementations must be safe for concurrent use, and may be backed by an external cache.
+// The stored vectors must not be modified.
+type EmbeddingStore interface {
+	// Get returns the vector stored under key, or false if there is none.
+	Get(ctx context.Context, key string) (*Vector, bool, error)
+	// Set stores vector under key.
+	Set(ctx context.Context, key string, vector *Vector) error
+}
+
+// EmbeddingCacheStats are the counters of a CachedEmbedder.
+type EmbeddingCacheStats struct {
+	// Number of inputs whose vector was found in the store.
+	Hits uint64
+	// Number of inputs that had to be embedded.
+	Misses uint64
+	// Number of failed store operations. Failed lookups are counted as misses.
+	StoreErrors uint64
+}
+
+// HitRatio returns the ratio of hits among all lookups, or 0 if there were none.
+func (s EmbeddingCacheStats) HitRatio() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// CachedEmbedder is an Embedder that caches the vectors computed by another Embedder,
+// keyed by model and input, including its options. Obtain one with NewCachedEmbedder.
+type CachedEmbedder struct {
+	embedder    Embedder
+	store       EmbeddingStore
+	hits        atomic.Uint64
+	misses      atomic.Uint64
+	storeErrors atomic.Uint64
+}
+
+// NewCachedEmbedder creates an Embedder caching the vectors computed by embedder in store.
+func NewCachedEmbedder(embedder Embedder, store EmbeddingStore) *CachedEmbedder {
+	return &CachedEmbedder{
+		embedder: embedder,
+		store:    store,
+	}
+}
+
+// Embed returns the cached vectors of the inputs, and embeds the others with the underlying embedder
+// in a single call. Identical inputs are embedded once.
+// Store errors are not returned: the inputs are embedded as if they were not cached.
+func (e *CachedEmbedder) Embed(ctx context.Context, model string, inputs []*EmbeddingInput) ([]*Vector, error) {
+	vectors := make([]*Vector, len(inputs))
+	// Indices of the inputs to embed, per cache key.
+	missing := make(map[string][]int)
+	var missingKeys []string
+	var missingInputs []*EmbeddingInput
+	for i, input := range inputs {
+		key, err := embeddingCacheKey(model, input)
+		if err != nil {
+			return nil, err
+		}
+		if indices, ok := missing[key]; ok {
+			missing[key] = append(indices, i)
+			e.hits.Add(1)
+			continue
+		}
+		vector, ok, err := e.store.Get(ctx, key)
+		if err != nil {
+			e.storeErrors.Add(1)
+		}
+		if ok && err == nil {
+			e.hits.Add(1)
+			vectors[i] = vector
+			continue
+		}
+		e.misses.Add(1)
+		missing[key] = []int{i}
+		missingKeys = append(missingKeys, key)
+		missingInputs = append(missingInputs, input)
+	}
+	if len(missingInputs) == 0 {
+		return vectors, nil
+	}
+	embedded, err := e.embedder.Embed(ctx, model, missingInputs)
+	if err != nil {
+		return nil, err
+	}
+	if len(embedded) != len(missingInputs) {
+		return nil, fmt.Errorf("embedder returned %d vectors for %d inputs", len(embedded), len(missingInputs))
+	}
+	for i, key := range missingKeys {
+		for _, index := range missing[key] {
+			vectors[index] = embedded[i]
+		}
+		if err := e.store.Set(ctx, key, embedded[i]); err != nil {
+			e.storeErrors.Add(1)
+		}
+	}
+	return vectors, nil
+}
+
+// Stats returns the counters of the cache since it was created.
+func (e *CachedEmbedder) Stats() EmbeddingCacheStats {
+	return EmbeddingCacheStats{
+		Hits:        e.hits.Load(),
+		Misses:      e.misses.Load(),
+		StoreErrors: e.storeErrors.Load(),
+	}
+}
+
+// Internal method.
+// Returns a hash of the model and the canonical encoding of the input, the one of the content IDs.
+func embeddingCacheKey(model string, input *EmbeddingInput) (string, error) {
+	var vector *Vector
+	switch {
+	case input.Document != nil:
+		vector = NewVectorDocument(input.Document)
+	case input.Image != nil:
+		vector = NewVectorImage(input.Image)
+	default:
+		vector = NewVectorObject(input.Object)
+	}
+	var e contentEncoder
+	e.writeString(model)
+	if err := e.writeVector(vector); err != nil {
+		return "", fmt.Errorf("failed to encode the embedding input: %w", err)
+	}
+	hash := sha256.Sum256(e.buf.Bytes())
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// LRUEmbeddingStore is an in-memory EmbeddingStore evicting the least recently used vectors
+// once its capacity is reached, and expiring vectors after a TTL.
+// Obtain one with NewLRUEmbeddingStore.
+type LRUEmbeddingStore struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	// Most recently used entries first.
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type lruEmbeddingEntry struct {
+	key       string
+	vector    *Vector
+	expiresAt time.Time
+}
+
+// NewLRUEmbeddingStore creates a store holding up to capacity vectors, each for up to ttl.
+// If ttl is 0, vectors do not expire.
+func NewLRUEmbeddingStore(capacity int, ttl time.Duration) *LRUEmbeddingStore {
+	return &LRUEmbeddingStore{
+		capacity: max(capacity, 1),
+		ttl:      ttl,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Get returns the vector stored under key, unless it expired.
+func (s *LRUEmbeddingStore) Get(_ context.Context, key string) (*Vector, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	element, ok := s.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	entry, _ := element.Value.(*lruEmbeddingEntry)
+	if s.ttl > 0 && time.Now().After(entry.expiresAt) {
+		s.order.Remove(element)
+		delete(s.entries, key)
+		return nil, false, nil
+	}
+	s.order.MoveToFront(element)
+	return entry.vector, true, nil
+}
+
+// Set stores vector under key, evicting the least recently used vector if the store is full.
+func (s *LRUEmbeddingStore) Set(_ context.Context, key string, vector *Vector) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry := &lruEmbeddingEntry{key: key, vector: vector, expiresAt: time.Now().Add(s.ttl)}
+	if element, ok := s.entries[key]; ok {
+		element.Value = entry
+		s.order.MoveToFront(element)
+		return nil
+	}
+	s.entries[key] = s.order.PushFront(entry)
+	if s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		evicted, _ := oldest.Value.(*lruEmbeddingEntry)
+		delete(s.entries, evicted.key)
+	}
+	return nil
+}
+
+// Len returns the number of vectors in the store, including expired ones not evicted yet.
+func (s *LRUEmbeddingStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
@@ -1,10 +1,14 @@
 package qdrant
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 )
 
+// ErrClientClosed is returned for requests made after the Client has been closed or shut down.
+var ErrClientClosed = errors.New("client is closed")
+
 //nolint:revive // The linter says qdrant.QdrantError stutters, but it's an apt name.
 type QdrantError struct {
 	operationName string
@@ -0,0 +1,287 @@
+// This file contains the formatting of filters into the textual syntax read by ParseFilter.
+
+package qdrant
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Precedence of a formatted expression, used to add the parentheses needed to parse it back.
+const (
+	filterPrecOr = iota + 1
+	filterPrecAnd
+	filterPrecUnary
+)
+
+// FormatFilter formats a filter in the syntax read by ParseFilter.
+// A nil or empty filter is formatted as an empty string.
+//
+// Must clauses are joined with AND, should clauses with OR and must_not clauses are negated with NOT.
+// Formatting and parsing back a filter returns an equivalent filter, but not necessarily an identical one,
+// e.g. a range with several bounds is formatted as several comparisons.
+//
+// Returns an error if the filter contains conditions that have no textual syntax,
+// such as geo bounding boxes, geo polygons, values count or min_should.
+func FormatFilter(filter *Filter) (string, error) {
+	formatted, _, err := formatFilterExpr(filter)
+	return formatted, err
+}
+
+// Internal method.
+func wrapFilterExpr(expr string, prec, minPrec int) string {
+	if prec < minPrec {
+		return "(" + expr + ")"
+	}
+	return expr
+}
+
+// Internal method.
+// Returns the formatted filter and its precedence.
+func formatFilterExpr(filter *Filter) (string, int, error) {
+	if filter.GetMinShould() != nil {
+		return "", 0, errors.New("cannot format min_should")
+	}
+	type part struct {
+		expr string
+		prec int
+	}
+	var parts []part
+	must := filter.GetMust()
+	should := filter.GetShould()
+	if len(should) == 1 {
+		// A single should clause must be satisfied, same as a must clause.
+		must = append(must[:len(must):len(must)], should[0])
+		should = nil
+	}
+	for _, condition := range must {
+		expr, prec, err := formatConditionExpr(condition)
+		if err != nil {
+			return "", 0, err
+		}
+		parts = append(parts, part{expr, prec})
+	}
+	if len(should) > 0 {
+		exprs := make([]string, len(should))
+		for i, condition := range should {
+			expr, prec, err := formatConditionExpr(condition)
+			if err != nil {
+				return "", 0, err
+			}
+			exprs[i] = wrapFilterExpr(expr, prec, filterPrecUnary)
+		}
+		parts = append(parts, part{strings.Join(exprs, " OR "), filterPrecOr})
+	}
+	for _, condition := range filter.GetMustNot() {
+		expr, prec, err := formatConditionExpr(condition)
+		if err != nil {
+			return "", 0, err
+		}
+		parts = append(parts, part{"NOT " + wrapFilterExpr(expr, prec, filterPrecUnary), filterPrecUnary})
+	}
+	switch len(parts) {
+	case 0:
+		return "", filterPrecUnary, nil
+	case 1:
+		return parts[0].expr, parts[0].prec, nil
+	}
+	exprs := make([]string, len(parts))
+	for i, p := range parts {
+		exprs[i] = wrapFilterExpr(p.expr, p.prec, filterPrecAnd)
+	}
+	return strings.Join(exprs, " AND "), filterPrecAnd, nil
+}
+
+// Internal method.
+func formatConditionExpr(condition *Condition) (string, int, error) {
+	switch c := condition.GetConditionOneOf().(type) {
+	case *Condition_Field:
+		return formatFieldConditionExpr(c.Field)
+	case *Condition_IsNull:
+		field, err := formatFilterField(c.IsNull.GetKey())
+		return field + " IS NULL", filterPrecUnary, err
+	case *Condition_IsEmpty:
+		field, err := formatFilterField(c.IsEmpty.GetKey())
+		return field + " IS EMPTY", filterPrecUnary, err
+	case *Condition_HasId:
+		ids := make([]string, len(c.HasId.GetHasId()))
+		for i, id := range c.HasId.GetHasId() {
+			switch id.GetPointIdOptions().(type) {
+			case *PointId_Num:
+				ids[i] = strconv.FormatUint(id.GetNum(), 10)
+			default:
+				ids[i] = strconv.Quote(id.GetUuid())
+			}
+		}
+		return "HAS_ID (" + strings.Join(ids, ", ") + ")", filterPrecUnary, nil
+	case *Condition_HasVector:
+		return "HAS_VECTOR " + strconv.Quote(c.HasVector.GetHasVector()), filterPrecUnary, nil
+	case *Condition_Nested:
+		field, err := formatFilterField(c.Nested.GetKey())
+		if err != nil {
+			return "", 0, err
+		}
+		inner, _, err := formatFilterExpr(c.Nested.GetFilter())
+		if err != nil {
+			return "", 0, err
+		}
+		if inner == "" {
+			return "", 0, fmt.Errorf("cannot format nested condition on %q with an empty filter", c.Nested.GetKey())
+		}
+		return "NESTED " + field + " (" + inner + ")", filterPrecUnary, nil
+	case *Condition_Filter:
+		expr, prec, err := formatFilterExpr(c.Filter)
+		if err != nil {
+			return "", 0, err
+		}
+		if expr == "" {
+			return "", 0, errors.New("cannot format an empty filter as a condition")
+		}
+		return expr, prec, nil
+	}
+	return "", 0, fmt.Errorf("cannot format condition %T", condition.GetConditionOneOf())
+}
+
+// Internal method.
+// A field condition with several criteria set is formatted as their conjunction.
+func formatFieldConditionExpr(condition *FieldCondition) (string, int, error) {
+	field, err := formatFilterField(condition.GetKey())
+	if err != nil {
+		return "", 0, err
+	}
+	var exprs []string
+	if condition.GetMatch() != nil {
+		expr, err := formatMatchExpr(field, condition.GetMatch())
+		if err != nil {
+			return "", 0, err
+		}
+		exprs = append(exprs, expr)
+	}
+	if r := condition.GetRange(); r != nil {
+		exprs = append(exprs, formatRangeExprs(field, r.Lt, r.Lte, r.Gt, r.Gte, formatFilterNumber)...)
+	}
+	if r := condition.GetDatetimeRange(); r != nil {
+		exprs = append(exprs, formatRangeExprs(field, r.Lt, r.Lte, r.Gt, r.Gte, formatFilterDatetime)...)
+	}
+	if geo := condition.GetGeoRadius(); geo != nil {
+		exprs = append(exprs, fmt.Sprintf("%s NEAR (%s, %s, %s)", field,
+			formatFilterFloat(geo.GetCenter().GetLat()), formatFilterFloat(geo.GetCenter().GetLon()),
+			strconv.FormatFloat(float64(geo.GetRadius()), 'g', -1, 32)))
+	}
+	switch {
+	case condition.GetGeoBoundingBox() != nil:
+		return "", 0, fmt.Errorf("cannot format geo bounding box condition on %q", condition.GetKey())
+	case condition.GetGeoPolygon() != nil:
+		return "", 0, fmt.Errorf("cannot format geo polygon condition on %q", condition.GetKey())
+	case condition.GetValuesCount() != nil:
+		return "", 0, fmt.Errorf("cannot format values count condition on %q", condition.GetKey())
+	case condition.IsEmpty != nil || condition.IsNull != nil:
+		return "", 0, fmt.Errorf("cannot format is_empty or is_null field criteria on %q", condition.GetKey())
+	case len(exprs) == 0:
+		return "", 0, fmt.Errorf("cannot format field condition on %q without criteria", condition.GetKey())
+	case len(exprs) == 1:
+		return exprs[0], filterPrecUnary, nil
+	}
+	return strings.Join(exprs, " AND "), filterPrecAnd, nil
+}
+
+// Internal method.
+func formatMatchExpr(field string, match *Match) (string, error) {
+	switch m := match.GetMatchValue().(type) {
+	case *Match_Keyword:
+		return field + " = " + strconv.Quote(m.Keyword), nil
+	case *Match_Integer:
+		return field + " = " + strconv.FormatInt(m.Integer, 10), nil
+	case *Match_Boolean:
+		return field + " = " + strconv.FormatBool(m.Boolean), nil
+	case *Match_Text:
+		return field + " TEXT " + strconv.Quote(m.Text), nil
+	case *Match_Phrase:
+		return field + " PHRASE " + strconv.Quote(m.Phrase), nil
+	case *Match_TextAny:
+		return field + " TEXT_ANY " + strconv.Quote(m.TextAny), nil
+	case *Match_Keywords:
+		return field + " IN " + formatFilterStrings(m.Keywords.GetStrings()), nil
+	case *Match_Integers:
+		return field + " IN " + formatFilterInts(m.Integers.GetIntegers()), nil
+	case *Match_ExceptKeywords:
+		return field + " EXCEPT " + formatFilterStrings(m.ExceptKeywords.GetStrings()), nil
+	case *Match_ExceptIntegers:
+		return field + " EXCEPT " + formatFilterInts(m.ExceptIntegers.GetIntegers()), nil
+	}
+	return "", fmt.Errorf("cannot format match %T on %q", match.GetMatchValue(), field)
+}
+
+// Internal method.
+// A range with only inclusive lower and upper bounds is formatted with BETWEEN.
+func formatRangeExprs[T comparable](field string, lt, lte, gt, gte T, format func(T) string) []string {
+	var unset T
+	if lt == unset && gt == unset && lte != unset && gte != unset {
+		return []string{field + " BETWEEN " + format(gte) + " AND " + format(lte)}
+	}
+	var exprs []string
+	for _, bound := range []struct {
+		op    string
+		value T
+	}{{">", gt}, {">=", gte}, {"<", lt}, {"<=", lte}} {
+		if bound.value != unset {
+			exprs = append(exprs, field+" "+bound.op+" "+format(bound.value))
+		}
+	}
+	return exprs
+}
+
+// Internal method.
+func formatFilterFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// Internal method.
+func formatFilterNumber(value *float64) string {
+	return formatFilterFloat(*value)
+}
+
+// Internal method.
+func formatFilterDatetime(value *timestamppb.Timestamp) string {
+	return strconv.Quote(value.AsTime().UTC().Format(time.RFC3339Nano))
+}
+
+// Internal method.
+func formatFilterStrings(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = strconv.Quote(v)
+	}
+	return "(" + strings.Join(quoted, ", ") + ")"
+}
+
+// Internal method.
+func formatFilterInts(values []int64) string {
+	formatted := make([]string, len(values))
+	for i, v := range values {
+		formatted[i] = strconv.FormatInt(v, 10)
+	}
+	return "(" + strings.Join(formatted, ", ") + ")"
+}
+
+// Internal method.
+// Quotes the field name with backticks if it is not a valid bare field name.
+func formatFilterField(field string) (string, error) {
+	if strings.ContainsRune(field, '`') {
+		return "", fmt.Errorf("cannot format field name %q containing a backtick", field)
+	}
+	if field == "" || isFilterKeyword(field) {
+		return "`" + field + "`", nil
+	}
+	for i, r := range field {
+		if (i == 0 && !isFilterIdentStart(r)) || !isFilterIdentPart(r) {
+			return "`" + field + "`", nil
+		}
+	}
+	return field, nil
+}
@@ -0,0 +1,821 @@
+// This file contains a parser for filters written in a textual syntax.
+// The conditions are built with the constructors in conditions.go.
+// https://qdrant.tech/documentation/concepts/filtering/
+//
+// USAGE:
+//
+//	filter, err := qdrant.ParseFilter(`city = "Berlin" AND price BETWEEN 10 AND 20 AND NOT tags IN ("a", "b")`)
+
+package qdrant
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// FilterSyntaxError is returned by ParseFilter when the expression is malformed.
+type FilterSyntaxError struct {
+	// Byte offset of the error in the expression.
+	Offset int
+	// Line of the error, starting at 1.
+	Line int
+	// Column of the error in characters, starting at 1.
+	Column int
+	// Description of the error.
+	Msg string
+}
+
+// Error returns the error as string.
+func (e *FilterSyntaxError) Error() string {
+	return fmt.Sprintf("filter syntax error at line %d, column %d: %s", e.Line, e.Column, e.Msg)
+}
+
+// ParseFilter parses a textual filter expression into a *Filter.
+// An empty expression returns an empty filter, which matches all points.
+//
+// Conditions can be combined with AND, OR and NOT, and grouped with parentheses.
+// NOT binds tighter than AND, which binds tighter than OR. Keywords are case-insensitive.
+//
+//	city = "Berlin"                        NewMatch (strings), NewMatchInt (integers), NewMatchBool (true, false)
+//	city != "Berlin"                       NOT city = "Berlin"
+//	tags IN ("a", "b")                     NewMatchKeywords, NewMatchInts
+//	tags NOT IN ("a", "b")                 NOT tags IN ("a", "b")
+//	tags EXCEPT ("a", "b")                 NewMatchExcept, NewMatchExceptInts
+//	price > 10, price <= 20.5              NewRange
+//	price BETWEEN 10 AND 20                NewRange with gte and lte
+//	created_at > "2024-01-01T00:00:00Z"    NewDatetimeRange, for RFC 3339 datetimes and dates
+//	discount IS NULL, tags IS NOT EMPTY    NewIsNull, NewIsEmpty
+//	description TEXT "good cheap"          NewMatchText, and PHRASE and TEXT_ANY for NewMatchPhrase and NewMatchTextAny
+//	location NEAR (52.52, 13.40, 1000)     NewGeoRadius, with latitude, longitude and radius in meters
+//	HAS_ID (1, "5c56c793-69f3-4fbf-87e6-c4bf54c28c26")  NewHasID
+//	HAS_VECTOR "image"                     NewHasVector
+//	NESTED diet (food = "meat" AND liked = true)        NewNestedFilter
+//
+// Field names may contain letters, digits, "_", "." and "[]", e.g. country.cities[].population.
+// Other field names, including the ones that are keywords, must be quoted with backticks: `first-name`.
+// Strings are double-quoted and support the escape sequences of Go string literals.
+//
+// Syntax errors are reported as a *FilterSyntaxError.
+func ParseFilter(expr string) (*Filter, error) {
+	tokens, err := lexFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{input: expr, tokens: tokens}
+	if p.peek().kind == filterTokenEOF {
+		return &Filter{}, nil
+	}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != filterTokenEOF {
+		return nil, p.errorAt(tok.offset, "unexpected %s", tok)
+	}
+	return node.toFilter(), nil
+}
+
+type filterTokenKind int
+
+const (
+	filterTokenEOF filterTokenKind = iota
+	filterTokenIdent
+	filterTokenQuotedIdent
+	filterTokenString
+	filterTokenNumber
+	filterTokenOperator
+	filterTokenLParen
+	filterTokenRParen
+	filterTokenComma
+)
+
+type filterToken struct {
+	kind   filterTokenKind
+	text   string
+	offset int
+}
+
+// String describes the token in error messages.
+func (t filterToken) String() string {
+	if t.kind == filterTokenEOF {
+		return "end of input"
+	}
+	return strconv.Quote(t.text)
+}
+
+// Internal method.
+// Splits expr into tokens, terminated by an EOF token.
+func lexFilter(expr string) ([]filterToken, error) {
+	var tokens []filterToken
+	for i := 0; i < len(expr); {
+		r, size := utf8.DecodeRuneInString(expr[i:])
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+			continue
+		case r == '(':
+			tokens = append(tokens, filterToken{filterTokenLParen, "(", start})
+			i++
+			continue
+		case r == ')':
+			tokens = append(tokens, filterToken{filterTokenRParen, ")", start})
+			i++
+			continue
+		case r == ',':
+			tokens = append(tokens, filterToken{filterTokenComma, ",", start})
+			i++
+			continue
+		case r == '=' || r == '<' || r == '>' || r == '!':
+			i++
+			if i < len(expr) && expr[i] == '=' {
+				i++
+			}
+			op := expr[start:i]
+			switch op {
+			case "!":
+				return nil, newFilterSyntaxError(expr, start, `unexpected "!", did you mean "!="?`)
+			case "==":
+				return nil, newFilterSyntaxError(expr, start, `unexpected "==", did you mean "="?`)
+			}
+			tokens = append(tokens, filterToken{filterTokenOperator, op, start})
+			continue
+		case r == '"':
+			i++
+			for i < len(expr) && expr[i] != '"' {
+				if expr[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			if i >= len(expr) {
+				return nil, newFilterSyntaxError(expr, start, "unterminated string")
+			}
+			i++
+			value, err := strconv.Unquote(expr[start:i])
+			if err != nil {
+				return nil, newFilterSyntaxError(expr, start, "invalid string %s", expr[start:i])
+			}
+			tokens = append(tokens, filterToken{filterTokenString, value, start})
+			continue
+		case r == '`':
+			end := strings.IndexByte(expr[i+1:], '`')
+			if end < 0 {
+				return nil, newFilterSyntaxError(expr, start, "unterminated quoted field name")
+			}
+			i += end + 2
+			tokens = append(tokens, filterToken{filterTokenQuotedIdent, expr[start+1 : i-1], start})
+			continue
+		case r == '-' || r == '+' || r == '.' || r >= '0' && r <= '9':
+			i = scanFilterNumber(expr, i)
+			tokens = append(tokens, filterToken{filterTokenNumber, expr[start:i], start})
+			continue
+		case isFilterIdentStart(r):
+			i += size
+			for i < len(expr) {
+				r, size = utf8.DecodeRuneInString(expr[i:])
+				if !isFilterIdentPart(r) {
+					break
+				}
+				i += size
+			}
+			tokens = append(tokens, filterToken{filterTokenIdent, expr[start:i], start})
+			continue
+		}
+		return nil, newFilterSyntaxError(expr, start, "unexpected character %q", r)
+	}
+	return append(tokens, filterToken{filterTokenEOF, "", len(expr)}), nil
+}
+
+// Internal method.
+// Returns the end of the number starting at i. The number is validated by the parser.
+func scanFilterNumber(expr string, i int) int {
+	if expr[i] == '-' || expr[i] == '+' {
+		i++
+	}
+	for i < len(expr) {
+		c := expr[i]
+		isExponentSign := (c == '-' || c == '+') && (expr[i-1] == 'e' || expr[i-1] == 'E')
+		if !(c >= '0' && c <= '9') && c != '.' && c != 'e' && c != 'E' && !isExponentSign {
+			break
+		}
+		i++
+	}
+	return i
+}
+
+// Internal method.
+func isFilterIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+// Internal method.
+func isFilterIdentPart(r rune) bool {
+	return isFilterIdentStart(r) || unicode.IsDigit(r) || r == '.' || r == '[' || r == ']'
+}
+
+// Internal method.
+// Reports whether ident is a keyword of the filter syntax, which cannot be used as a bare field name.
+func isFilterKeyword(ident string) bool {
+	switch strings.ToUpper(ident) {
+	case "AND", "OR", "NOT", "IN", "EXCEPT", "BETWEEN", "IS", "NULL", "EMPTY", "TRUE", "FALSE",
+		"TEXT", "PHRASE", "TEXT_ANY", "NEAR", "NESTED", "HAS_ID", "HAS_VECTOR":
+		return true
+	}
+	return false
+}
+
+// Internal method.
+func newFilterSyntaxError(expr string, offset int, format string, args ...any) *FilterSyntaxError {
+	prefix := expr[:offset]
+	lineStart := strings.LastIndexByte(prefix, '\n') + 1
+	return &FilterSyntaxError{
+		Offset: offset,
+		Line:   strings.Count(prefix, "\n") + 1,
+		Column: utf8.RuneCountInString(prefix[lineStart:]) + 1,
+		Msg:    fmt.Sprintf(format, args...),
+	}
+}
+
+type filterNodeOp int
+
+const (
+	filterNodeCondition filterNodeOp = iota
+	filterNodeAnd
+	filterNodeOr
+	filterNodeNot
+)
+
+// Internal type for the parsed expression tree, converted to a *Filter once parsed.
+type filterNode struct {
+	op        filterNodeOp
+	condition *Condition
+	children  []*filterNode
+}
+
+// Internal method.
+// Conjunctions become must and must_not clauses, disjunctions become should clauses.
+func (n *filterNode) toFilter() *Filter {
+	filter := &Filter{}
+	switch n.op {
+	case filterNodeAnd:
+		for _, child := range n.children {
+			if child.op == filterNodeNot {
+				filter.MustNot = append(filter.MustNot, child.children[0].toCondition())
+			} else {
+				filter.Must = append(filter.Must, child.toCondition())
+			}
+		}
+	case filterNodeOr:
+		for _, child := range n.children {
+			filter.Should = append(filter.Should, child.toCondition())
+		}
+	case filterNodeNot:
+		filter.MustNot = []*Condition{n.children[0].toCondition()}
+	case filterNodeCondition:
+		filter.Must = []*Condition{n.condition}
+	}
+	return filter
+}
+
+// Internal method.
+func (n *filterNode) toCondition() *Condition {
+	if n.op == filterNodeCondition {
+		return n.condition
+	}
+	return NewFilterAsCondition(n.toFilter())
+}
+
+// Internal type implementing a recursive descent parser over the tokens of an expression.
+type filterParser struct {
+	input  string
+	tokens []filterToken
+	pos    int
+}
+
+// Internal method.
+func (p *filterParser) peek() filterToken {
+	return p.tokens[p.pos]
+}
+
+// Internal method.
+func (p *filterParser) next() filterToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != filterTokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// Internal method.
+func (p *filterParser) errorAt(offset int, format string, args ...any) *FilterSyntaxError {
+	return newFilterSyntaxError(p.input, offset, format, args...)
+}
+
+// Internal method.
+func isKeywordToken(tok filterToken, keyword string) bool {
+	return tok.kind == filterTokenIdent && strings.EqualFold(tok.text, keyword)
+}
+
+// Internal method.
+func (p *filterParser) expect(kind filterTokenKind, description string) (filterToken, error) {
+	tok := p.next()
+	if tok.kind != kind {
+		return tok, p.errorAt(tok.offset, "expected %s, got %s", description, tok)
+	}
+	return tok, nil
+}
+
+// Internal method.
+func (p *filterParser) expectKeyword(keyword string) error {
+	if tok := p.next(); !isKeywordToken(tok, keyword) {
+		return p.errorAt(tok.offset, "expected %s, got %s", keyword, tok)
+	}
+	return nil
+}
+
+// Internal method.
+func (p *filterParser) parseOr() (*filterNode, error) {
+	return p.parseBinary(filterNodeOr, "OR", p.parseAnd)
+}
+
+// Internal method.
+func (p *filterParser) parseAnd() (*filterNode, error) {
+	return p.parseBinary(filterNodeAnd, "AND", p.parseUnary)
+}
+
+// Internal method.
+// Parses operands separated by keyword, flattening nested nodes of the same operation.
+func (p *filterParser) parseBinary(
+	op filterNodeOp,
+	keyword string,
+	parseOperand func() (*filterNode, error),
+) (*filterNode, error) {
+	node := &filterNode{op: op}
+	for {
+		operand, err := parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		if operand.op == op {
+			node.children = append(node.children, operand.children...)
+		} else {
+			node.children = append(node.children, operand)
+		}
+		if !isKeywordToken(p.peek(), keyword) {
+			break
+		}
+		p.next()
+	}
+	if len(node.children) == 1 {
+		return node.children[0], nil
+	}
+	return node, nil
+}
+
+// Internal method.
+func (p *filterParser) parseUnary() (*filterNode, error) {
+	if !isKeywordToken(p.peek(), "NOT") {
+		return p.parsePrimary()
+	}
+	p.next()
+	operand, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return negateFilterNode(operand), nil
+}
+
+// Internal method.
+func negateFilterNode(node *filterNode) *filterNode {
+	return &filterNode{op: filterNodeNot, children: []*filterNode{node}}
+}
+
+// Internal method.
+func conditionNode(condition *Condition) *filterNode {
+	return &filterNode{op: filterNodeCondition, condition: condition}
+}
+
+// Internal method.
+func (p *filterParser) parsePrimary() (*filterNode, error) {
+	tok := p.peek()
+	switch {
+	case tok.kind == filterTokenLParen:
+		p.next()
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(filterTokenRParen, `")"`); err != nil {
+			return nil, err
+		}
+		return node, nil
+	case isKeywordToken(tok, "HAS_ID"):
+		p.next()
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		ids := make([]*PointId, len(values))
+		for i, v := range values {
+			switch {
+			case v.kind == filterValueString:
+				ids[i] = NewID(v.str)
+			case v.kind == filterValueInt && v.integer >= 0:
+				ids[i] = NewIDNum(uint64(v.integer))
+			default:
+				return nil, p.errorAt(v.offset, "point IDs must be non-negative integers or UUID strings")
+			}
+		}
+		return conditionNode(NewHasID(ids...)), nil
+	case isKeywordToken(tok, "HAS_VECTOR"):
+		p.next()
+		name, err := p.expect(filterTokenString, "a vector name")
+		if err != nil {
+			return nil, err
+		}
+		return conditionNode(NewHasVector(name.text)), nil
+	case isKeywordToken(tok, "NESTED"):
+		p.next()
+		field, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(filterTokenLParen, `"("`); err != nil {
+			return nil, err
+		}
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(filterTokenRParen, `")"`); err != nil {
+			return nil, err
+		}
+		return conditionNode(NewNestedFilter(field, inner.toFilter())), nil
+	}
+	field, err := p.parseField()
+	if err != nil {
+		return nil, err
+	}
+	return p.parseFieldCondition(field)
+}
+
+// Internal method.
+func (p *filterParser) parseField() (string, error) {
+	tok := p.next()
+	switch {
+	case tok.kind == filterTokenQuotedIdent:
+		return tok.text, nil
+	case tok.kind == filterTokenIdent && !isFilterKeyword(tok.text):
+		return tok.text, nil
+	case tok.kind == filterTokenIdent:
+		return "", p.errorAt(tok.offset, "expected a field name, got keyword %s (quote field names with backticks)", tok)
+	}
+	return "", p.errorAt(tok.offset, "expected a field name, got %s", tok)
+}
+
+// Internal method.
+// Parses the operator and operands following a field name.
+func (p *filterParser) parseFieldCondition(field string) (*filterNode, error) {
+	tok := p.next()
+	switch {
+	case tok.kind == filterTokenOperator && (tok.text == "=" || tok.text == "!="):
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		condition, err := p.matchCondition(field, value)
+		if err != nil {
+			return nil, err
+		}
+		if tok.text == "!=" {
+			return negateFilterNode(conditionNode(condition)), nil
+		}
+		return conditionNode(condition), nil
+	case tok.kind == filterTokenOperator:
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		condition, err := p.rangeCondition(field, tok.text, value)
+		if err != nil {
+			return nil, err
+		}
+		return conditionNode(condition), nil
+	case isKeywordToken(tok, "BETWEEN"):
+		return p.parseBetween(field)
+	case isKeywordToken(tok, "IN"):
+		return p.parseIn(field)
+	case isKeywordToken(tok, "EXCEPT"):
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		strs, ints, err := p.splitListValues(values)
+		if err != nil {
+			return nil, err
+		}
+		if strs != nil {
+			return conditionNode(NewMatchExcept(field, strs...)), nil
+		}
+		return conditionNode(NewMatchExceptInts(field, ints...)), nil
+	case isKeywordToken(tok, "NOT"):
+		next := p.next()
+		var node *filterNode
+		var err error
+		switch {
+		case isKeywordToken(next, "IN"):
+			node, err = p.parseIn(field)
+		case isKeywordToken(next, "BETWEEN"):
+			node, err = p.parseBetween(field)
+		default:
+			return nil, p.errorAt(next.offset, "expected IN or BETWEEN after NOT, got %s", next)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return negateFilterNode(node), nil
+	case isKeywordToken(tok, "IS"):
+		negated := isKeywordToken(p.peek(), "NOT")
+		if negated {
+			p.next()
+		}
+		var node *filterNode
+		switch next := p.next(); {
+		case isKeywordToken(next, "NULL"):
+			node = conditionNode(NewIsNull(field))
+		case isKeywordToken(next, "EMPTY"):
+			node = conditionNode(NewIsEmpty(field))
+		default:
+			return nil, p.errorAt(next.offset, "expected NULL or EMPTY, got %s", next)
+		}
+		if negated {
+			return negateFilterNode(node), nil
+		}
+		return node, nil
+	case isKeywordToken(tok, "TEXT"), isKeywordToken(tok, "PHRASE"), isKeywordToken(tok, "TEXT_ANY"):
+		text, err := p.expect(filterTokenString, "a string")
+		if err != nil {
+			return nil, err
+		}
+		switch strings.ToUpper(tok.text) {
+		case "PHRASE":
+			return conditionNode(NewMatchPhrase(field, text.text)), nil
+		case "TEXT_ANY":
+			return conditionNode(NewMatchTextAny(field, text.text)), nil
+		}
+		return conditionNode(NewMatchText(field, text.text)), nil
+	case isKeywordToken(tok, "NEAR"):
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		const geoRadiusArgs = 3
+		if len(values) != geoRadiusArgs {
+			return nil, p.errorAt(tok.offset, "NEAR expects latitude, longitude and radius, got %d values", len(values))
+		}
+		coords := make([]float64, geoRadiusArgs)
+		for i, v := range values {
+			if coords[i], err = p.numberValue(v); err != nil {
+				return nil, err
+			}
+		}
+		return conditionNode(NewGeoRadius(field, coords[0], coords[1], float32(coords[2]))), nil
+	}
+	return nil, p.errorAt(tok.offset, "expected an operator after field %q, got %s", field, tok)
+}
+
+// Internal method.
+func (p *filterParser) parseIn(field string) (*filterNode, error) {
+	values, err := p.parseList()
+	if err != nil {
+		return nil, err
+	}
+	strs, ints, err := p.splitListValues(values)
+	if err != nil {
+		return nil, err
+	}
+	if strs != nil {
+		return conditionNode(NewMatchKeywords(field, strs...)), nil
+	}
+	return conditionNode(NewMatchInts(field, ints...)), nil
+}
+
+// Internal method.
+// Parses "lower AND upper" into an inclusive range on numbers or datetimes.
+func (p *filterParser) parseBetween(field string) (*filterNode, error) {
+	lower, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectKeyword("AND"); err != nil {
+		return nil, err
+	}
+	upper, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	if lower.kind == filterValueString {
+		from, err := p.datetimeValue(lower)
+		if err != nil {
+			return nil, err
+		}
+		to, err := p.datetimeValue(upper)
+		if err != nil {
+			return nil, err
+		}
+		return conditionNode(NewDatetimeRange(field, &DatetimeRange{Gte: from, Lte: to})), nil
+	}
+	from, err := p.numberValue(lower)
+	if err != nil {
+		return nil, err
+	}
+	to, err := p.numberValue(upper)
+	if err != nil {
+		return nil, err
+	}
+	return conditionNode(NewRange(field, &Range{Gte: &from, Lte: &to})), nil
+}
+
+type filterValueKind int
+
+const (
+	filterValueString filterValueKind = iota
+	filterValueInt
+	filterValueFloat
+	filterValueBool
+)
+
+type filterValue struct {
+	kind    filterValueKind
+	str     string
+	integer int64
+	float   float64
+	boolean bool
+	offset  int
+}
+
+// Internal method.
+func (p *filterParser) parseValue() (filterValue, error) {
+	tok := p.next()
+	value := filterValue{offset: tok.offset}
+	switch {
+	case tok.kind == filterTokenString:
+		value.kind = filterValueString
+		value.str = tok.text
+	case tok.kind == filterTokenNumber:
+		if integer, err := strconv.ParseInt(tok.text, 10, 64); err == nil {
+			value.kind = filterValueInt
+			value.integer = integer
+			break
+		}
+		float, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return value, p.errorAt(tok.offset, "invalid number %s", tok)
+		}
+		value.kind = filterValueFloat
+		value.float = float
+	case isKeywordToken(tok, "TRUE"), isKeywordToken(tok, "FALSE"):
+		value.kind = filterValueBool
+		value.boolean = strings.EqualFold(tok.text, "TRUE")
+	default:
+		return value, p.errorAt(tok.offset, "expected a value, got %s", tok)
+	}
+	return value, nil
+}
+
+// Internal method.
+// Parses a parenthesized, comma-separated, non-empty list of values.
+func (p *filterParser) parseList() ([]filterValue, error) {
+	open, err := p.expect(filterTokenLParen, `"("`)
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind == filterTokenRParen {
+		return nil, p.errorAt(open.offset, "empty list")
+	}
+	var values []filterValue
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		tok := p.next()
+		if tok.kind == filterTokenRParen {
+			return values, nil
+		}
+		if tok.kind != filterTokenComma {
+			return nil, p.errorAt(tok.offset, `expected "," or ")", got %s`, tok)
+		}
+	}
+}
+
+// Internal method.
+// Returns the values as strings or as integers. Lists mixing both are rejected.
+func (p *filterParser) splitListValues(values []filterValue) ([]string, []int64, error) {
+	var strs []string
+	var ints []int64
+	for _, v := range values {
+		switch {
+		case v.kind == filterValueString && ints == nil:
+			strs = append(strs, v.str)
+		case v.kind == filterValueInt && strs == nil:
+			ints = append(ints, v.integer)
+		default:
+			return nil, nil, p.errorAt(v.offset, "list values must be all strings or all integers")
+		}
+	}
+	return strs, ints, nil
+}
+
+// Internal method.
+func (p *filterParser) matchCondition(field string, value filterValue) (*Condition, error) {
+	switch value.kind {
+	case filterValueString:
+		return NewMatch(field, value.str), nil
+	case filterValueInt:
+		return NewMatchInt(field, value.integer), nil
+	case filterValueBool:
+		return NewMatchBool(field, value.boolean), nil
+	case filterValueFloat:
+	}
+	return nil, p.errorAt(value.offset, "floats cannot be matched exactly, use a range")
+}
+
+// Internal method.
+func (p *filterParser) rangeCondition(field, op string, value filterValue) (*Condition, error) {
+	if value.kind == filterValueString {
+		datetime, err := p.datetimeValue(value)
+		if err != nil {
+			return nil, err
+		}
+		dateTimeRange := &DatetimeRange{}
+		setRangeBound(op, datetime, &dateTimeRange.Lt, &dateTimeRange.Lte, &dateTimeRange.Gt, &dateTimeRange.Gte)
+		return NewDatetimeRange(field, dateTimeRange), nil
+	}
+	number, err := p.numberValue(value)
+	if err != nil {
+		return nil, err
+	}
+	rangeVal := &Range{}
+	setRangeBound(op, &number, &rangeVal.Lt, &rangeVal.Lte, &rangeVal.Gt, &rangeVal.Gte)
+	return NewRange(field, rangeVal), nil
+}
+
+// Internal method.
+// Sets the bound of a range corresponding to a comparison operator.
+func setRangeBound[T any](op string, value *T, lt, lte, gt, gte **T) {
+	switch op {
+	case "<":
+		*lt = value
+	case "<=":
+		*lte = value
+	case ">":
+		*gt = value
+	case ">=":
+		*gte = value
+	}
+}
+
+// Internal method.
+func (p *filterParser) numberValue(value filterValue) (float64, error) {
+	switch value.kind {
+	case filterValueInt:
+		return float64(value.integer), nil
+	case filterValueFloat:
+		return value.float, nil
+	case filterValueString, filterValueBool:
+	}
+	return 0, p.errorAt(value.offset, "expected a number")
+}
+
+// Internal method.
+func (p *filterParser) datetimeValue(value filterValue) (*timestamppb.Timestamp, error) {
+	if value.kind != filterValueString {
+		return nil, p.errorAt(value.offset, "expected a datetime string")
+	}
+	datetime, ok := parseDatetime(value.str)
+	if !ok {
+		return nil, p.errorAt(value.offset, "invalid datetime %q, expected RFC 3339 format", value.str)
+	}
+	return datetime, nil
+}
+
+// Internal method.
+// Parses an RFC 3339 datetime, also accepting a space as separator, no time zone and dates.
+// The time zone defaults to UTC and the time to midnight.
+func parseDatetime(value string) (*timestamppb.Timestamp, bool) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02 15:04:05", time.DateOnly} {
+		if datetime, err := time.Parse(layout, value); err == nil {
+			return timestamppb.New(datetime), true
+		}
+	}
+	return nil, false
+}
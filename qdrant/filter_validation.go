@@ -0,0 +1,338 @@
+package qdrant
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FilterIssue describes a condition of a filter that the server would reject in strict mode,
+// or that would require a full scan of the collection.
+type FilterIssue struct {
+	// Path of the condition in the filter, e.g. "must[0].nested.must[1]".
+	Path string
+	// Payload key of the condition, if any. Keys inside nested conditions are prefixed, e.g. "diet[].food".
+	Key string
+	// Description of the issue.
+	Reason string
+}
+
+// String returns the issue as string.
+func (i FilterIssue) String() string {
+	if i.Key == "" {
+		return fmt.Sprintf("%s: %s", i.Path, i.Reason)
+	}
+	return fmt.Sprintf("%s (%q): %s", i.Path, i.Key, i.Reason)
+}
+
+// FilterValidationError is returned by ValidateFilter and lists all the issues found in a filter.
+type FilterValidationError struct {
+	Issues []FilterIssue
+}
+
+// Error returns the error as string.
+func (e *FilterValidationError) Error() string {
+	issues := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		issues[i] = issue.String()
+	}
+	return fmt.Sprintf("invalid filter: %s", strings.Join(issues, "; "))
+}
+
+// Validates a filter against the payload schema and strict mode config of a collection,
+// before it is sent with a query.
+// The collection info is cached for Config.PayloadSchemaCacheTTL.
+//
+// It reports:
+//   - conditions on payload keys without a payload index,
+//   - conditions not supported by the index type of their key, e.g. a range on a keyword index
+//     or a full-text match without a text index,
+//   - more conditions than the strict mode filter_max_conditions,
+//   - conditions with more values than the strict mode condition_max_size.
+//
+// Unindexed keys are reported even if strict mode allows unindexed filtering,
+// since such conditions require a full scan.
+//
+// Parameters:
+//   - ctx: The context for the request.
+//   - collectionName: The name of the collection to validate the filter for.
+//   - filter: The filter to validate.
+//
+// Returns:
+//   - error: A *FilterValidationError listing the issues, or an error if fetching the collection info fails.
+func (c *Client) ValidateFilter(ctx context.Context, collectionName string, filter *Filter) error {
+	schema, err := c.getCollectionSchema(ctx, collectionName)
+	if err != nil {
+		return newQdrantErr(err, "ValidateFilter", collectionName)
+	}
+	issues := schema.validate(filter)
+	if len(issues) > 0 {
+		return newQdrantErr(&FilterValidationError{Issues: issues}, "ValidateFilter", collectionName)
+	}
+	return nil
+}
+
+// InvalidateCollectionSchema removes the cached payload schema of a collection used by ValidateFilter,
+// e.g. after creating a payload index.
+func (c *Client) InvalidateCollectionSchema(collectionName string) {
+	c.schemas.invalidate(collectionName)
+}
+
+// Internal method.
+func (c *Client) getCollectionSchema(ctx context.Context, collectionName string) (*collectionSchema, error) {
+	if schema := c.schemas.get(collectionName); schema != nil {
+		return schema, nil
+	}
+	resp, err := c.GetCollectionsClient().Get(ctx, &GetCollectionInfoRequest{
+		CollectionName: collectionName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	info := resp.GetResult()
+	params := info.GetConfig().GetParams()
+	schema := &collectionSchema{
+		payloadSchema: info.GetPayloadSchema(),
+		strictMode:    info.GetConfig().GetStrictModeConfig(),
+		vectors:       params.GetVectorsConfig().GetParamsMap().GetMap(),
+		sparseVectors: params.GetSparseVectorsConfig().GetMap(),
+	}
+	if vector := params.GetVectorsConfig().GetParams(); vector != nil {
+		schema.vectors = map[string]*VectorParams{"": vector}
+	}
+	c.schemas.put(collectionName, schema)
+	return schema, nil
+}
+
+// Internal type caching the payload schema of collections.
+type collectionSchemaCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]collectionSchemaEntry
+}
+
+type collectionSchemaEntry struct {
+	schema    *collectionSchema
+	expiresAt time.Time
+}
+
+func newCollectionSchemaCache(ttl time.Duration) *collectionSchemaCache {
+	return &collectionSchemaCache{
+		ttl:     ttl,
+		entries: make(map[string]collectionSchemaEntry),
+	}
+}
+
+// Internal method.
+func (c *collectionSchemaCache) get(collectionName string) *collectionSchema {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[collectionName]
+	if !ok || time.Now().After(entry.expiresAt) {
+		delete(c.entries, collectionName)
+		return nil
+	}
+	return entry.schema
+}
+
+// Internal method.
+func (c *collectionSchemaCache) put(collectionName string, schema *collectionSchema) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[collectionName] = collectionSchemaEntry{
+		schema:    schema,
+		expiresAt: time.Now().Add(c.ttl),
+	}
+}
+
+// Internal method.
+func (c *collectionSchemaCache) invalidate(collectionName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, collectionName)
+}
+
+// Internal type holding the parts of the collection info used to validate filters and vectors.
+type collectionSchema struct {
+	payloadSchema map[string]*PayloadSchemaInfo
+	strictMode    *StrictModeConfig
+	// Dense and multi-dense vectors by name. The unnamed default vector is stored under "".
+	vectors       map[string]*VectorParams
+	sparseVectors map[string]*SparseVectorParams
+}
+
+// Internal type accumulating the issues found while walking a filter.
+type filterValidator struct {
+	schema     *collectionSchema
+	issues     []FilterIssue
+	conditions uint64
+}
+
+// Internal method.
+func (s *collectionSchema) validate(filter *Filter) []FilterIssue {
+	v := &filterValidator{schema: s}
+	v.walkFilter(filter, "", "")
+	// The getters cannot tell an unset limit from 0: the fields are read after checking strictMode is not nil.
+	if s.strictMode.GetEnabled() && s.strictMode.FilterMaxConditions != nil {
+		if limit := s.strictMode.GetFilterMaxConditions(); v.conditions > limit {
+			v.issues = append(v.issues, FilterIssue{
+				Path:   "filter",
+				Reason: fmt.Sprintf("has %d conditions, more than the filter_max_conditions of %d", v.conditions, limit),
+			})
+		}
+	}
+	return v.issues
+}
+
+// Internal method.
+func (v *filterValidator) report(path, key, format string, args ...any) {
+	v.issues = append(v.issues, FilterIssue{Path: path, Key: key, Reason: fmt.Sprintf(format, args...)})
+}
+
+// Internal method.
+// Walks the conditions of filter. Keys are prefixed with keyPrefix inside nested conditions.
+func (v *filterValidator) walkFilter(filter *Filter, path, keyPrefix string) {
+	clauses := []struct {
+		name       string
+		conditions []*Condition
+	}{
+		{"must", filter.GetMust()},
+		{"should", filter.GetShould()},
+		{"must_not", filter.GetMustNot()},
+		{"min_should", filter.GetMinShould().GetConditions()},
+	}
+	for _, clause := range clauses {
+		for i, condition := range clause.conditions {
+			v.walkCondition(condition, fmt.Sprintf("%s[%d]", restPath(path, clause.name), i), keyPrefix)
+		}
+	}
+}
+
+// Internal method.
+func (v *filterValidator) walkCondition(condition *Condition, path, keyPrefix string) {
+	switch c := condition.GetConditionOneOf().(type) {
+	case *Condition_Filter:
+		v.walkFilter(c.Filter, path, keyPrefix)
+	case *Condition_Nested:
+		v.walkFilter(c.Nested.GetFilter(), restPath(path, "nested"), keyPrefix+c.Nested.GetKey()+"[].")
+	case *Condition_Field:
+		v.conditions++
+		v.checkFieldCondition(c.Field, path, keyPrefix+c.Field.GetKey())
+	case *Condition_IsEmpty:
+		v.conditions++
+		v.requireIndex(path, keyPrefix+c.IsEmpty.GetKey(), "is_empty")
+	case *Condition_IsNull:
+		v.conditions++
+		v.requireIndex(path, keyPrefix+c.IsNull.GetKey(), "is_null")
+	case *Condition_HasId:
+		v.conditions++
+		v.checkSize(path, "", "has_id", len(c.HasId.GetHasId()))
+	case *Condition_HasVector:
+		v.conditions++
+	}
+}
+
+// Internal method.
+// Reports a missing index and returns the index of key, or nil if there is none.
+func (v *filterValidator) requireIndex(path, key, condition string) *PayloadSchemaInfo {
+	index, ok := v.schema.payloadSchema[key]
+	if !ok {
+		// Array keys, e.g. "tags[]", are indexed without the brackets.
+		index, ok = v.schema.payloadSchema[strings.TrimSuffix(key, "[]")]
+	}
+	if !ok {
+		v.report(path, key, "%s condition on a key without a payload index", condition)
+		return nil
+	}
+	return index
+}
+
+// Internal method.
+func (v *filterValidator) checkFieldCondition(field *FieldCondition, path, key string) {
+	index := v.requireIndex(path, key, "field")
+	if index == nil {
+		return
+	}
+	indexType := index.GetDataType()
+	check := func(condition string, supported ...PayloadSchemaType) {
+		for _, t := range supported {
+			if t == indexType {
+				return
+			}
+		}
+		v.report(path, key, "%s condition is not supported by a %s index", condition, strings.ToLower(indexType.String()))
+	}
+	switch m := field.GetMatch().GetMatchValue().(type) {
+	case *Match_Keyword:
+		check("keyword match", PayloadSchemaType_Keyword, PayloadSchemaType_Uuid)
+	case *Match_Keywords:
+		check("keyword match any", PayloadSchemaType_Keyword, PayloadSchemaType_Uuid)
+		v.checkSize(path, key, "match any", len(m.Keywords.GetStrings()))
+	case *Match_ExceptKeywords:
+		check("keyword match except", PayloadSchemaType_Keyword, PayloadSchemaType_Uuid)
+		v.checkSize(path, key, "match except", len(m.ExceptKeywords.GetStrings()))
+	case *Match_Integer:
+		v.checkIntegerMatch(index, path, key, check)
+	case *Match_Integers:
+		v.checkIntegerMatch(index, path, key, check)
+		v.checkSize(path, key, "match any", len(m.Integers.GetIntegers()))
+	case *Match_ExceptIntegers:
+		v.checkIntegerMatch(index, path, key, check)
+		v.checkSize(path, key, "match except", len(m.ExceptIntegers.GetIntegers()))
+	case *Match_Boolean:
+		check("boolean match", PayloadSchemaType_Bool)
+	case *Match_Text, *Match_TextAny:
+		check("full-text match", PayloadSchemaType_Text)
+	case *Match_Phrase:
+		check("phrase match", PayloadSchemaType_Text)
+		if indexType == PayloadSchemaType_Text && !index.GetParams().GetTextIndexParams().GetPhraseMatching() {
+			v.report(path, key, "phrase match on a text index without phrase_matching enabled")
+		}
+	}
+	if field.GetRange() != nil {
+		check("range", PayloadSchemaType_Integer, PayloadSchemaType_Float)
+		if params := index.GetParams().GetIntegerIndexParams(); params != nil && params.Range != nil && !params.GetRange() {
+			v.report(path, key, "range on an integer index with range disabled")
+		}
+	}
+	if field.GetDatetimeRange() != nil {
+		check("datetime range", PayloadSchemaType_Datetime)
+	}
+	if field.GetGeoBoundingBox() != nil || field.GetGeoRadius() != nil || field.GetGeoPolygon() != nil {
+		check("geo", PayloadSchemaType_Geo)
+	}
+	if polygon := field.GetGeoPolygon(); polygon != nil {
+		points := len(polygon.GetExterior().GetPoints())
+		for _, interior := range polygon.GetInteriors() {
+			points += len(interior.GetPoints())
+		}
+		v.checkSize(path, key, "geo polygon", points)
+	}
+}
+
+// Internal method.
+func (v *filterValidator) checkIntegerMatch(index *PayloadSchemaInfo, path, key string,
+	check func(condition string, supported ...PayloadSchemaType),
+) {
+	check("integer match", PayloadSchemaType_Integer)
+	// Lookup is enabled unless explicitly disabled.
+	if params := index.GetParams().GetIntegerIndexParams(); params != nil && params.Lookup != nil && !params.GetLookup() {
+		v.report(path, key, "integer match on an integer index with lookup disabled")
+	}
+}
+
+// Internal method.
+// Reports conditions with more values than the strict mode condition_max_size.
+func (v *filterValidator) checkSize(path, key, condition string, size int) {
+	strictMode := v.schema.strictMode
+	if !strictMode.GetEnabled() || strictMode.ConditionMaxSize == nil || uint64(size) <= strictMode.GetConditionMaxSize() {
+		return
+	}
+	v.report(path, key, "%s condition has %d values, more than the condition_max_size of %d", condition, size,
+		strictMode.GetConditionMaxSize())
+}
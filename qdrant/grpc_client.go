@@ -32,13 +32,31 @@ func NewDefaultGrpcClient() (*GrpcClient, error) {
 
 // Create a new gRPC client with custom configuration.
 func NewGrpcClient(config *Config) (*GrpcClient, error) {
+	return newGrpcClient(config)
+}
+
+// Internal method.
+// The given interceptors are chained before the ones derived from the config.
+func newGrpcClient(config *Config, interceptors ...grpc.UnaryClientInterceptor) (*GrpcClient, error) {
 	// We append config.GrpcOptions in the end
 	// so that user's explicit options take precedence
 	clientVersion := getClientVersion()
+	transportCreds, err := config.getTransportCreds()
+	if err != nil {
+		return nil, err
+	}
 	var grpcOptions []grpc.DialOption
 	grpcOptions = append(grpcOptions,
-		config.getTransportCreds(),
+		transportCreds,
 		config.getMetadataInterceptor(),
+		grpc.WithChainUnaryInterceptor(interceptors...),
+		config.getRequestOptionsInterceptor(),
+		config.getEmbeddingInterceptor(),
+		config.getRequestSplittingInterceptor(),
+		config.getUsageInterceptor(),
+	)
+	grpcOptions = append(grpcOptions, config.getLoggingInterceptor()...)
+	grpcOptions = append(grpcOptions,
 		config.getRateLimitInterceptor(),
 		grpc.WithUserAgent(fmt.Sprintf("go-client/%s", clientVersion)),
 	)
@@ -49,9 +67,9 @@ func NewGrpcClient(config *Config) (*GrpcClient, error) {
 	}
 	grpcOptions = append(grpcOptions, config.getKeepAliveParams()...)
 
-	config.GrpcOptions = append(grpcOptions, config.GrpcOptions...)
+	grpcOptions = append(grpcOptions, config.GrpcOptions...)
 
-	conn, err := grpc.NewClient(config.getAddr(), config.GrpcOptions...)
+	conn, err := grpc.NewClient(config.getAddr(), grpcOptions...)
 
 	if err != nil {
 		return nil, err
@@ -0,0 +1,288 @@
+// This file contains a fluent builder for hybrid queries,
+// which combine the results of several prefetches, e.g. dense and sparse, with fusion.
+// https://qdrant.tech/documentation/concepts/hybrid-queries/
+//
+// USAGE:
+//
+//	request, err := qdrant.NewHybridQuery("my_collection").
+//		Dense("dense", denseVector, 100).
+//		Sparse("bm25", indices, values, 100).
+//		FuseRRF(0).
+//		Filter(filter).
+//		Limit(10).
+//		Build()
+//	points, err := client.Query(ctx, request)
+
+package qdrant
+
+import (
+	"errors"
+	"fmt"
+)
+
+const defaultQueryLimit = 10
+
+// HybridQueryBuilder builds a QueryPoints request that fuses the results of several prefetches.
+// Obtain one with NewHybridQuery.
+type HybridQueryBuilder struct {
+	collectionName string
+	prefetches     []*PrefetchQuery
+	fusion         *Query
+	filter         *Filter
+	limit          *uint64
+	offset         *uint64
+	scoreThreshold *float32
+	withPayload    *WithPayloadSelector
+	withVectors    *WithVectorsSelector
+	errs           []error
+}
+
+// NewHybridQuery creates a builder for a hybrid query on the given collection.
+func NewHybridQuery(collectionName string) *HybridQueryBuilder {
+	return &HybridQueryBuilder{
+		collectionName: collectionName,
+	}
+}
+
+// Dense adds a prefetch searching the named dense vector, retrieving up to limit candidates.
+// An empty name uses the default vector.
+func (b *HybridQueryBuilder) Dense(using string, vector []float32, limit uint64) *HybridQueryBuilder {
+	if len(vector) == 0 {
+		b.errs = append(b.errs, fmt.Errorf("dense prefetch %q: vector is empty", using))
+	}
+	return b.addPrefetch(using, NewQueryDense(vector), limit)
+}
+
+// Sparse adds a prefetch searching the named sparse vector, retrieving up to limit candidates.
+func (b *HybridQueryBuilder) Sparse(using string, indices []uint32, values []float32, limit uint64) *HybridQueryBuilder {
+	if len(indices) != len(values) {
+		b.errs = append(b.errs, fmt.Errorf("sparse prefetch %q: got %d indices and %d values",
+			using, len(indices), len(values)))
+	}
+	if len(indices) == 0 {
+		b.errs = append(b.errs, fmt.Errorf("sparse prefetch %q: vector is empty", using))
+	}
+	return b.addPrefetch(using, NewQuerySparse(indices, values), limit)
+}
+
+// Multi adds a prefetch searching the named multi-vector, retrieving up to limit candidates.
+func (b *HybridQueryBuilder) Multi(using string, vectors [][]float32, limit uint64) *HybridQueryBuilder {
+	if len(vectors) == 0 {
+		b.errs = append(b.errs, fmt.Errorf("multi-vector prefetch %q: vector is empty", using))
+	}
+	return b.addPrefetch(using, NewQueryMulti(vectors), limit)
+}
+
+// Document adds a prefetch searching the named vector with a document embedded
+// by the inference model it specifies, retrieving up to limit candidates.
+func (b *HybridQueryBuilder) Document(using string, document *Document, limit uint64) *HybridQueryBuilder {
+	if document.GetText() == "" {
+		b.errs = append(b.errs, fmt.Errorf("document prefetch %q: text is empty", using))
+	}
+	return b.addPrefetch(using, NewQueryDocument(document), limit)
+}
+
+// Prefetch adds an arbitrary prefetch, e.g. a nested multi-stage query.
+// Its limit must be set.
+func (b *HybridQueryBuilder) Prefetch(prefetch *PrefetchQuery) *HybridQueryBuilder {
+	if prefetch == nil {
+		b.errs = append(b.errs, errors.New("prefetch is nil"))
+		return b
+	}
+	if prefetch.Limit == nil {
+		b.errs = append(b.errs, fmt.Errorf("prefetch %d: limit is not set", len(b.prefetches)))
+	}
+	b.prefetches = append(b.prefetches, prefetch)
+	return b
+}
+
+// FuseRRF fuses the prefetch results with Reciprocal Rank Fusion.
+// If k is 0, the server default is used.
+// See: https://qdrant.tech/documentation/concepts/hybrid-queries/#reciprocal-rank-fusion-rrf
+func (b *HybridQueryBuilder) FuseRRF(k uint32) *HybridQueryBuilder {
+	if k == 0 {
+		return b.setFusion(NewQueryFusion(Fusion_RRF))
+	}
+	return b.setFusion(NewQueryRRF(&Rrf{K: &k}))
+}
+
+// FuseWeightedRRF fuses the prefetch results with Reciprocal Rank Fusion,
+// weighting each prefetch, in the order they were added.
+// If k is 0, the server default is used.
+func (b *HybridQueryBuilder) FuseWeightedRRF(k uint32, weights ...float32) *HybridQueryBuilder {
+	rrf := &Rrf{Weights: weights}
+	if k > 0 {
+		rrf.K = &k
+	}
+	return b.setFusion(NewQueryRRF(rrf))
+}
+
+// FuseDBSF fuses the prefetch results with Distribution-Based Score Fusion.
+// See: https://qdrant.tech/documentation/concepts/hybrid-queries/#distribution-based-score-fusion-dbsf
+func (b *HybridQueryBuilder) FuseDBSF() *HybridQueryBuilder {
+	return b.setFusion(NewQueryFusion(Fusion_DBSF))
+}
+
+// Filter restricts the results to points matching filter.
+// The filter is applied to every prefetch, so that candidates are filtered before fusion.
+func (b *HybridQueryBuilder) Filter(filter *Filter) *HybridQueryBuilder {
+	b.filter = filter
+	return b
+}
+
+// Limit sets the maximum number of points returned. Defaults to 10.
+func (b *HybridQueryBuilder) Limit(limit uint64) *HybridQueryBuilder {
+	b.limit = &limit
+	return b
+}
+
+// Offset sets the number of points to skip.
+func (b *HybridQueryBuilder) Offset(offset uint64) *HybridQueryBuilder {
+	b.offset = &offset
+	return b
+}
+
+// ScoreThreshold only returns points with a fused score better than threshold.
+func (b *HybridQueryBuilder) ScoreThreshold(threshold float32) *HybridQueryBuilder {
+	b.scoreThreshold = &threshold
+	return b
+}
+
+// WithPayload sets which payload to return, e.g. NewWithPayload(true).
+func (b *HybridQueryBuilder) WithPayload(selector *WithPayloadSelector) *HybridQueryBuilder {
+	b.withPayload = selector
+	return b
+}
+
+// WithVectors sets which vectors to return, e.g. NewWithVectors(true).
+func (b *HybridQueryBuilder) WithVectors(selector *WithVectorsSelector) *HybridQueryBuilder {
+	b.withVectors = selector
+	return b
+}
+
+// Build validates the query and returns the QueryPoints request.
+//
+// It reports an error if:
+//   - the collection name is empty,
+//   - no prefetch was added, or a prefetch has an empty or malformed vector,
+//   - several prefetches are added without fusion, or fusion is set with less than two prefetches,
+//   - the weights of a weighted RRF do not match the number of prefetches,
+//   - a prefetch limit is smaller than the final limit plus offset, which would starve the fusion.
+func (b *HybridQueryBuilder) Build() (*QueryPoints, error) {
+	errs := append([]error(nil), b.errs...)
+	if b.collectionName == "" {
+		errs = append(errs, errors.New("collection name is empty"))
+	}
+	errs = append(errs, b.validateStructure()...)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid hybrid query: %w", errors.Join(errs...))
+	}
+
+	request := &QueryPoints{
+		CollectionName: b.collectionName,
+		Filter:         b.filter,
+		Limit:          b.limit,
+		Offset:         b.offset,
+		ScoreThreshold: b.scoreThreshold,
+		WithPayload:    b.withPayload,
+		WithVectors:    b.withVectors,
+	}
+	if b.fusion == nil {
+		// A single prefetch is sent as the query itself.
+		prefetch := b.prefetches[0]
+		request.Query = prefetch.GetQuery()
+		request.Using = prefetch.Using
+		request.Prefetch = prefetch.GetPrefetch()
+		request.Params = prefetch.GetParams()
+		request.LookupFrom = prefetch.GetLookupFrom()
+		if b.limit == nil {
+			request.Limit = prefetch.Limit
+		}
+		if b.filter == nil {
+			request.Filter = prefetch.GetFilter()
+		}
+		if b.scoreThreshold == nil {
+			request.ScoreThreshold = prefetch.ScoreThreshold
+		}
+		return request, nil
+	}
+	request.Query = b.fusion
+	request.Prefetch = make([]*PrefetchQuery, len(b.prefetches))
+	for i, prefetch := range b.prefetches {
+		if b.filter != nil && prefetch.GetFilter() == nil {
+			filtered, _ := shallowClone(prefetch.ProtoReflect()).Interface().(*PrefetchQuery)
+			filtered.Filter = b.filter
+			prefetch = filtered
+		}
+		request.Prefetch[i] = prefetch
+	}
+	return request, nil
+}
+
+// Internal method.
+func (b *HybridQueryBuilder) validateStructure() []error {
+	var errs []error
+	switch {
+	case len(b.prefetches) == 0:
+		errs = append(errs, errors.New("no prefetch added"))
+	case b.fusion == nil && len(b.prefetches) > 1:
+		errs = append(errs, fmt.Errorf("%d prefetches require a fusion method", len(b.prefetches)))
+	case b.fusion != nil && len(b.prefetches) < 2:
+		errs = append(errs, fmt.Errorf("fusion requires at least 2 prefetches, got %d", len(b.prefetches)))
+	}
+	if weights := b.fusion.GetRrf().GetWeights(); len(weights) > 0 && len(weights) != len(b.prefetches) {
+		errs = append(errs, fmt.Errorf("got %d RRF weights for %d prefetches", len(weights), len(b.prefetches)))
+	}
+	if b.fusion == nil {
+		return errs
+	}
+	required := b.getLimit() + b.getOffset()
+	for i, prefetch := range b.prefetches {
+		if prefetch.Limit != nil && prefetch.GetLimit() < required {
+			errs = append(errs, fmt.Errorf("prefetch %d (%q) limit %d is less than the query limit plus offset %d",
+				i, prefetch.GetUsing(), prefetch.GetLimit(), required))
+		}
+	}
+	return errs
+}
+
+// Internal method.
+func (b *HybridQueryBuilder) addPrefetch(using string, query *Query, limit uint64) *HybridQueryBuilder {
+	if limit == 0 {
+		b.errs = append(b.errs, fmt.Errorf("prefetch %q: limit must be greater than 0", using))
+	}
+	prefetch := &PrefetchQuery{
+		Query: query,
+		Limit: &limit,
+	}
+	if using != "" {
+		prefetch.Using = &using
+	}
+	b.prefetches = append(b.prefetches, prefetch)
+	return b
+}
+
+// Internal method.
+func (b *HybridQueryBuilder) setFusion(fusion *Query) *HybridQueryBuilder {
+	if b.fusion != nil {
+		b.errs = append(b.errs, errors.New("fusion method set more than once"))
+	}
+	b.fusion = fusion
+	return b
+}
+
+// Internal method.
+func (b *HybridQueryBuilder) getLimit() uint64 {
+	if b.limit != nil {
+		return *b.limit
+	}
+	return defaultQueryLimit
+}
+
+// Internal method.
+func (b *HybridQueryBuilder) getOffset() uint64 {
+	if b.offset != nil {
+		return *b.offset
+	}
+	return 0
+}
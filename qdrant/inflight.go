@@ -0,0 +1,97 @@
+package qdrant
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/grpc"
+)
+
+// Internal type that counts in-flight requests and scroll iterators,
+// so that Shutdown can wait for them to finish.
+type inflightTracker struct {
+	mu      sync.Mutex
+	closed  bool
+	active  int
+	drained chan struct{}
+}
+
+type trackedContextKey struct{}
+
+func newInflightTracker() *inflightTracker {
+	return &inflightTracker{
+		drained: make(chan struct{}),
+	}
+}
+
+// Internal method.
+// Registers a new unit of work. Returns false if the client is closed.
+func (t *inflightTracker) acquire() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return false
+	}
+	t.active++
+	return true
+}
+
+// Internal method.
+func (t *inflightTracker) release() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.active--
+	if t.closed && t.active == 0 {
+		close(t.drained)
+	}
+}
+
+// Internal method.
+// Stops accepting new work. Returns a channel that is closed once all
+// outstanding work has been released.
+func (t *inflightTracker) close() <-chan struct{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.closed {
+		t.closed = true
+		if t.active == 0 {
+			close(t.drained)
+		}
+	}
+	return t.drained
+}
+
+// Internal method.
+func (t *inflightTracker) outstanding() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.active
+}
+
+// Internal method.
+// Marks ctx as belonging to work that is already tracked, e.g. a ScrollIterator,
+// so that its calls are not rejected once the client starts shutting down.
+func withTracked(ctx context.Context) context.Context {
+	return context.WithValue(ctx, trackedContextKey{}, true)
+}
+
+// Internal method.
+func (t *inflightTracker) unaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		if tracked, _ := ctx.Value(trackedContextKey{}).(bool); tracked {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		if !t.acquire() {
+			return ErrClientClosed
+		}
+		defer t.release()
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
@@ -0,0 +1,227 @@
+package qdrant
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	status "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const redactedValue = "[REDACTED]"
+
+// RequestLogConfig configures structured logging of requests with slog.
+// Every logged request records the method, collection, duration, gRPC status code,
+// request size in bytes and, if available, the number of results.
+type RequestLogConfig struct {
+	// Logger to write to. Defaults to slog.Default().
+	Logger *slog.Logger
+	// Level used for successful requests. Defaults to slog.LevelDebug.
+	Level slog.Leveler
+	// Level used for failed requests. Defaults to slog.LevelError.
+	ErrorLevel slog.Leveler
+	// SampleRate is the fraction of successful requests that are logged, between 0 and 1.
+	// Failed requests are always logged.
+	// If 0, all requests are logged.
+	SampleRate float64
+	// Whether to include the request body, encoded as JSON.
+	// Vectors are omitted from the logged body.
+	IncludeRequest bool
+	// Whether to include the outgoing headers.
+	// The API key and authorization headers are redacted.
+	IncludeHeaders bool
+}
+
+// Internal method.
+func (c *RequestLogConfig) logger() *slog.Logger {
+	if c.Logger != nil {
+		return c.Logger
+	}
+	return slog.Default()
+}
+
+// Internal method.
+func (c *RequestLogConfig) level(failed bool) slog.Level {
+	if failed {
+		if c.ErrorLevel != nil {
+			return c.ErrorLevel.Level()
+		}
+		return slog.LevelError
+	}
+	if c.Level != nil {
+		return c.Level.Level()
+	}
+	return slog.LevelDebug
+}
+
+// Internal method.
+func (c *RequestLogConfig) sampled() bool {
+	if c.SampleRate <= 0 || c.SampleRate >= 1 {
+		return true
+	}
+	return rand.Float64() < c.SampleRate //nolint:gosec // Sampling does not need a secure random source.
+}
+
+// Internal method.
+func (c *RequestLogConfig) loggingInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		duration := time.Since(start)
+
+		failed := err != nil
+		logger := c.logger()
+		level := c.level(failed)
+		if !logger.Enabled(ctx, level) || (!failed && !c.sampled()) {
+			return err
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", method),
+			slog.Duration("duration", duration),
+			slog.String("code", status.Code(err).String()),
+		}
+		if msg, ok := req.(proto.Message); ok {
+			if collection := stringField(msg.ProtoReflect(), "collection_name"); collection != "" {
+				attrs = append(attrs, slog.String("collection", collection))
+			}
+			attrs = append(attrs, slog.Int("requestSize", proto.Size(msg)))
+			if c.IncludeRequest {
+				attrs = append(attrs, slog.String("request", redactedJSON(msg)))
+			}
+		}
+		if c.IncludeHeaders {
+			attrs = append(attrs, slog.Any("headers", redactedMetadata(ctx)))
+		}
+		if failed {
+			attrs = append(attrs, slog.Any("err", err))
+			logger.LogAttrs(ctx, level, "Qdrant request failed", attrs...)
+			return err
+		}
+		if msg, ok := reply.(proto.Message); ok {
+			if count, found := resultCount(msg.ProtoReflect()); found {
+				attrs = append(attrs, slog.Int("resultCount", count))
+			}
+		}
+		logger.LogAttrs(ctx, level, "Qdrant request", attrs...)
+		return nil
+	}
+}
+
+// Internal method.
+// Returns the value of a top-level string field, or "" if msg has no such field.
+func stringField(msg protoreflect.Message, name protoreflect.Name) string {
+	fd := msg.Descriptor().Fields().ByName(name)
+	if fd == nil || fd.Kind() != protoreflect.StringKind {
+		return ""
+	}
+	return msg.Get(fd).String()
+}
+
+// Internal method.
+// Returns the length of the first list in the reply, looking at the top-level fields
+// and then at the fields of the "result" message, e.g. the groups of QueryGroupsResponse.
+func resultCount(reply protoreflect.Message) (int, bool) {
+	if count, found := firstListLen(reply); found {
+		return count, true
+	}
+	fd := reply.Descriptor().Fields().ByName("result")
+	if fd == nil || fd.Message() == nil || fd.IsList() || fd.IsMap() {
+		return 0, false
+	}
+	return firstListLen(reply.Get(fd).Message())
+}
+
+// Internal method.
+func firstListLen(msg protoreflect.Message) (int, bool) {
+	fields := msg.Descriptor().Fields()
+	for i := range fields.Len() {
+		fd := fields.Get(i)
+		if fd.IsList() {
+			return msg.Get(fd).List().Len(), true
+		}
+	}
+	return 0, false
+}
+
+// Internal method.
+// Encodes msg as JSON with all vector data removed.
+func redactedJSON(msg proto.Message) string {
+	clone := proto.Clone(msg)
+	clearVectors(clone.ProtoReflect())
+	encoded, err := protojson.Marshal(clone)
+	if err != nil {
+		return redactedValue
+	}
+	return string(encoded)
+}
+
+// Internal method.
+// Recursively clears the data of all dense, sparse and multi-dense vectors in msg, and the query vectors of searches.
+func clearVectors(msg protoreflect.Message) {
+	switch msg.Descriptor().FullName() {
+	case "qdrant.DenseVector", "qdrant.SparseVector", "qdrant.MultiDenseVector", "qdrant.SparseIndices":
+		fields := msg.Descriptor().Fields()
+		for i := range fields.Len() {
+			msg.Clear(fields.Get(i))
+		}
+		return
+	case "qdrant.Vector", "qdrant.VectorOutput":
+		// Deprecated fields carrying raw vector data.
+		for _, name := range []protoreflect.Name{"data", "indices", "vectors_count"} {
+			if fd := msg.Descriptor().Fields().ByName(name); fd != nil {
+				msg.Clear(fd)
+			}
+		}
+	case "qdrant.SearchPoints", "qdrant.SearchPointGroups":
+		// Raw query vectors.
+		msg.Clear(msg.Descriptor().Fields().ByName("vector"))
+	}
+	msg.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsList() && fd.Message() != nil:
+			list := v.List()
+			for i := range list.Len() {
+				clearVectors(list.Get(i).Message())
+			}
+		case fd.IsMap() && fd.MapValue().Message() != nil:
+			v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+				clearVectors(mv.Message())
+				return true
+			})
+		case !fd.IsList() && !fd.IsMap() && fd.Message() != nil:
+			clearVectors(v.Message())
+		}
+		return true
+	})
+}
+
+// Internal method.
+// Returns the outgoing headers of ctx with sensitive values redacted.
+func redactedMetadata(ctx context.Context) map[string]string {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	headers := make(map[string]string, len(md))
+	for key, values := range md {
+		switch strings.ToLower(key) {
+		case apiKeyHeader, "authorization":
+			headers[key] = redactedValue
+		default:
+			headers[key] = strings.Join(values, ",")
+		}
+	}
+	return headers
+}
@@ -0,0 +1,172 @@
+// This file contains helpers for multi-vectors, as produced by late interaction models such as ColBERT,
+// and a local MaxSim scorer to rerank retrieved points without another round trip.
+// https://qdrant.tech/documentation/concepts/vectors/#multivectors
+
+package qdrant
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// GetData returns the vectors of the multi-vector as slices.
+// The slices share memory with the multi-vector.
+func (x *MultiDenseVector) GetData() [][]float32 {
+	vectors := make([][]float32, len(x.GetVectors()))
+	for i, vector := range x.GetVectors() {
+		vectors[i] = vector.GetData()
+	}
+	return vectors
+}
+
+// SplitMultiVector splits a flat, row-major matrix of token embeddings into a multi-vector
+// of vectors of dim dimensions, e.g. to convert the output of a late interaction model.
+// If mask is not nil, the tokens with a false mask, such as padding tokens, are dropped.
+//
+// The result can be passed to NewVectorMulti, NewQueryMulti or NewVectorInputMulti.
+func SplitMultiVector(flat []float32, dim int, mask []bool) ([][]float32, error) {
+	if dim <= 0 {
+		return nil, fmt.Errorf("invalid dimension %d", dim)
+	}
+	if len(flat)%dim != 0 {
+		return nil, fmt.Errorf("%d values are not a multiple of the dimension %d", len(flat), dim)
+	}
+	tokens := len(flat) / dim
+	if mask != nil && len(mask) != tokens {
+		return nil, fmt.Errorf("got a mask of %d tokens for %d tokens", len(mask), tokens)
+	}
+	vectors := make([][]float32, 0, tokens)
+	for i := range tokens {
+		if mask == nil || mask[i] {
+			vectors = append(vectors, flat[i*dim:(i+1)*dim:(i+1)*dim])
+		}
+	}
+	return vectors, nil
+}
+
+// ValidateMultiVector checks that a multi-vector can be stored in, or queried against,
+// a vector configured with params: params must have a multi-vector config with a supported comparator,
+// and vectors must be non-empty, finite and of the configured size.
+func ValidateMultiVector(vectors [][]float32, params *VectorParams) error {
+	config := params.GetMultivectorConfig()
+	if config == nil {
+		return errors.New("vector is not configured as a multi-vector")
+	}
+	if config.GetComparator() != MultiVectorComparator_MaxSim {
+		return fmt.Errorf("unsupported multi-vector comparator %s", config.GetComparator())
+	}
+	if len(vectors) == 0 {
+		return errors.New("multi-vector is empty")
+	}
+	for i, vector := range vectors {
+		if uint64(len(vector)) != params.GetSize() {
+			return fmt.Errorf("vector %d has %d dimensions, expected %d", i, len(vector), params.GetSize())
+		}
+		for j, value := range vector {
+			if math.IsNaN(float64(value)) || math.IsInf(float64(value), 0) {
+				return fmt.Errorf("vector %d has a non-finite value at dimension %d", i, j)
+			}
+		}
+	}
+	return nil
+}
+
+// MaxSim returns the late interaction score of a document multi-vector for a query multi-vector:
+// the sum, over the query vectors, of their highest similarity with a document vector.
+//
+// The similarity is computed with distance, like the server does.
+// For Euclid and Manhattan, it is the negated distance, so that higher scores are better.
+// Returns 0 if the document is empty. Vectors of different sizes are compared on their common prefix.
+func MaxSim(query, document [][]float32, distance Distance) float32 {
+	if len(document) == 0 {
+		return 0
+	}
+	var score float64
+	for _, q := range query {
+		best := math.Inf(-1)
+		for _, d := range document {
+			best = max(best, vectorSimilarity(q, d, distance))
+		}
+		score += best
+	}
+	return float32(score)
+}
+
+// RerankMaxSim scores retrieved points by the MaxSim of their multi-vector named using
+// for the query multi-vector, and returns them as scored points sorted by decreasing score.
+// An empty using refers to the default vector.
+// The points must have been retrieved with their vectors, e.g. with WithVectors.
+//
+// Returns an error if a point does not have the multi-vector.
+func RerankMaxSim(query [][]float32, points []*RetrievedPoint, using string, distance Distance,
+) ([]*ScoredPoint, error) {
+	scored := make([]*ScoredPoint, len(points))
+	for i, point := range points {
+		multiVector := namedVectorOutput(point.GetVectors(), using).GetMultiVector()
+		if multiVector == nil {
+			return nil, fmt.Errorf("point %s does not have the multi-vector %q", point.GetId(), using)
+		}
+		scored[i] = &ScoredPoint{
+			Id:         point.GetId(),
+			Payload:    point.GetPayload(),
+			Score:      MaxSim(query, multiVector.GetData(), distance),
+			Vectors:    point.GetVectors(),
+			ShardKey:   point.GetShardKey(),
+			OrderValue: point.GetOrderValue(),
+		}
+	}
+	slices.SortStableFunc(scored, func(a, b *ScoredPoint) int {
+		return cmp.Compare(b.GetScore(), a.GetScore())
+	})
+	return scored, nil
+}
+
+// Internal method.
+// Returns the vector named using, or the default vector if using is empty.
+func namedVectorOutput(vectors *VectorsOutput, using string) *VectorOutput {
+	if vector := vectors.GetVector(); vector != nil {
+		if using == "" {
+			return vector
+		}
+		return nil
+	}
+	return vectors.GetVectors().GetVectors()[using]
+}
+
+// Internal method.
+func vectorSimilarity(a, b []float32, distance Distance) float64 {
+	n := min(len(a), len(b))
+	a, b = a[:n], b[:n]
+	var sum, normA, normB float64
+	for i := range a {
+		x, y := float64(a[i]), float64(b[i])
+		switch distance {
+		case Distance_Euclid:
+			sum += (x - y) * (x - y)
+		case Distance_Manhattan:
+			sum += math.Abs(x - y)
+		case Distance_Cosine:
+			normA += x * x
+			normB += y * y
+			sum += x * y
+		case Distance_Dot, Distance_UnknownDistance:
+			sum += x * y
+		}
+	}
+	switch distance {
+	case Distance_Euclid:
+		return -math.Sqrt(sum)
+	case Distance_Manhattan:
+		return -sum
+	case Distance_Cosine:
+		if normA == 0 || normB == 0 {
+			return 0
+		}
+		return sum / math.Sqrt(normA*normB)
+	case Distance_Dot, Distance_UnknownDistance:
+	}
+	return sum
+}
@@ -0,0 +1,274 @@
+// This file contains ParallelScroll, which reads a collection through several scroll iterators running
+// concurrently, one per partition of the points, and merges their pages into a single sequence.
+//
+// USAGE:
+//
+//	points := client.ParallelScroll(ctx, &qdrant.ScrollPoints{
+//		CollectionName: "my_collection",
+//		Limit:          qdrant.PtrOf(uint32(1000)),
+//	}, &qdrant.ParallelScrollOptions{
+//		Partitions: qdrant.NumericIDPartitions(1_000_000, 8),
+//	})
+//	for point, err := range points {
+//		if err != nil {
+//			return err
+//		}
+//		export(point)
+//	}
+
+package qdrant
+
+import (
+	"context"
+	"errors"
+	"io"
+	"iter"
+	"slices"
+	"sync"
+)
+
+// ScrollPartition is a subset of the points of a collection read by ParallelScroll.
+// The partitions of a scroll should not overlap, or the points they share are returned several times.
+type ScrollPartition struct {
+	// Filter combined with the filter of the request. May be nil.
+	Filter *Filter
+	// Shard keys to read, replacing the ones of the request. May be nil.
+	ShardKeySelector *ShardKeySelector
+	// Numeric ID from which the partition is read. May be nil to start from the offset of the request.
+	// Must be nil if the request has an OrderBy, whose pages are not in the order of the IDs.
+	StartID *uint64
+	// Numeric ID before which the partition ends, exclusive. May be nil to read until the end,
+	// including the points with UUIDs, which come after the numeric IDs.
+	// Must be nil if the request has an OrderBy.
+	EndID *uint64
+}
+
+// ParallelScrollOptions configures ParallelScroll.
+type ParallelScrollOptions struct {
+	// Partitions to read concurrently, e.g. from NumericIDPartitions, ShardKeyPartitions or FilterPartitions.
+	// If empty, the request is read as a single partition.
+	Partitions []*ScrollPartition
+	// Number of partitions read at once. The connections of the client are used in turn.
+	// Defaults to the number of partitions.
+	Parallelism int
+	// Number of pages read ahead and not yet consumed, in addition to the page each reader holds.
+	// Bounds the memory used to Parallelism + Buffer pages of the request limit.
+	// Defaults to Parallelism.
+	Buffer int
+}
+
+// Internal method.
+func (o *ParallelScrollOptions) getPartitions() []*ScrollPartition {
+	if len(o.Partitions) == 0 {
+		return []*ScrollPartition{{}}
+	}
+	return o.Partitions
+}
+
+// Internal method.
+func (o *ParallelScrollOptions) getParallelism() int {
+	if o.Parallelism <= 0 || o.Parallelism > len(o.getPartitions()) {
+		return len(o.getPartitions())
+	}
+	return o.Parallelism
+}
+
+// Internal method.
+func (o *ParallelScrollOptions) getBuffer() int {
+	if o.Buffer <= 0 {
+		return o.getParallelism()
+	}
+	return o.Buffer
+}
+
+// NumericIDPartitions splits the numeric IDs from 0 to maxID into n ranges of equal width.
+// The last range has no end, so that the points with a larger ID or a UUID are also read.
+// Partitions of equal width read a balanced number of points when the IDs are evenly spread.
+func NumericIDPartitions(maxID uint64, n int) []*ScrollPartition {
+	if n < 1 {
+		n = 1
+	}
+	width := maxID/uint64(n) + 1
+	partitions := make([]*ScrollPartition, n)
+	for i := range partitions {
+		partitions[i] = &ScrollPartition{StartID: PtrOf(uint64(i) * width)}
+		if i < n-1 {
+			partitions[i].EndID = PtrOf(uint64(i+1) * width)
+		}
+	}
+	return partitions
+}
+
+// ShardKeyPartitions returns a partition per shard key, for collections with custom sharding.
+func ShardKeyPartitions(keys ...*ShardKey) []*ScrollPartition {
+	partitions := make([]*ScrollPartition, len(keys))
+	for i, key := range keys {
+		partitions[i] = &ScrollPartition{
+			ShardKeySelector: &ShardKeySelector{ShardKeys: []*ShardKey{key}},
+		}
+	}
+	return partitions
+}
+
+// FilterPartitions returns a partition per filter, e.g. one per tenant.
+func FilterPartitions(filters ...*Filter) []*ScrollPartition {
+	partitions := make([]*ScrollPartition, len(filters))
+	for i, filter := range filters {
+		partitions[i] = &ScrollPartition{Filter: filter}
+	}
+	return partitions
+}
+
+// Reads all points matching a request by scrolling partitions of the collection concurrently,
+// with at most Parallelism requests in flight spread over the connections of the client.
+// Unlike ScrollAll, the points of different partitions are interleaved: only the points of a partition
+// are returned in the order of their IDs.
+//
+// Parameters:
+//   - ctx: The context for the request.
+//   - request: The ScrollPoints request. Its limit is the number of points read per request.
+//   - options: How to partition the points. May be nil to read the request as a single partition.
+//
+// Returns:
+//   - iter.Seq2[*RetrievedPoint, error]: The points. If a request fails, the error is yielded last.
+//     Stopping the iteration early cancels the pending requests. If the request has an OrderBy and a
+//     partition an ID range, an error is yielded without reading any point.
+func (c *Client) ParallelScroll(ctx context.Context, request *ScrollPoints,
+	options *ParallelScrollOptions,
+) iter.Seq2[*RetrievedPoint, error] {
+	if options == nil {
+		options = &ParallelScrollOptions{}
+	}
+	return func(yield func(*RetrievedPoint, error) bool) {
+		if request.GetOrderBy() != nil && slices.ContainsFunc(options.getPartitions(), hasIDRange) {
+			yield(nil, newQdrantErr(errors.New("ID partitions cannot be read with an OrderBy"),
+				"ParallelScroll", request.GetCollectionName()))
+			return
+		}
+		var wg sync.WaitGroup
+		defer wg.Wait()
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		scroll := &parallelScroll{
+			client:  c,
+			request: request,
+			pages:   make(chan scrollPage, options.getBuffer()),
+		}
+		partitions := make(chan *ScrollPartition, len(options.getPartitions()))
+		for _, partition := range options.getPartitions() {
+			partitions <- partition
+		}
+		close(partitions)
+		for range options.getParallelism() {
+			wg.Go(func() {
+				for partition := range partitions {
+					if err := scroll.scrollPartition(ctx, partition); err != nil {
+						scroll.send(ctx, scrollPage{err: err})
+						return
+					}
+				}
+			})
+		}
+		go func() {
+			wg.Wait()
+			close(scroll.pages)
+		}()
+		for page := range scroll.pages {
+			if page.err != nil {
+				yield(nil, page.err)
+				return
+			}
+			for _, point := range page.points {
+				if !yield(point, nil) {
+					return
+				}
+			}
+		}
+		// The readers stop silently when the context is canceled.
+		if err := ctx.Err(); err != nil {
+			yield(nil, newQdrantErr(err, "ParallelScroll", request.GetCollectionName()))
+		}
+	}
+}
+
+// Internal type holding the state of a ParallelScroll call.
+type parallelScroll struct {
+	client  *Client
+	request *ScrollPoints
+	pages   chan scrollPage
+}
+
+// Internal type holding a page of points read from a partition, or the error that stopped the reading.
+type scrollPage struct {
+	points []*RetrievedPoint
+	err    error
+}
+
+// Internal method.
+// Reads a partition and sends its pages. Returns nil when the partition is read, or the context canceled.
+func (s *parallelScroll) scrollPartition(ctx context.Context, partition *ScrollPartition) error {
+	it := s.client.ScrollAll(ctx, partitionRequest(s.request, partition))
+	defer it.Close()
+	for {
+		points, err := it.Next()
+		if errors.Is(err, io.EOF) || ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		end := len(points)
+		if partition.EndID != nil {
+			for i, point := range points {
+				if point.GetId().GetUuid() != "" || point.GetId().GetNum() >= *partition.EndID {
+					end = i
+					break
+				}
+			}
+		}
+		if end > 0 && !s.send(ctx, scrollPage{points: points[:end]}) {
+			return nil
+		}
+		if end < len(points) {
+			return nil
+		}
+	}
+}
+
+// Internal method.
+// Sends a page, unless the context is canceled first. Returns whether the page was sent.
+func (s *parallelScroll) send(ctx context.Context, page scrollPage) bool {
+	select {
+	case s.pages <- page:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Internal method.
+func hasIDRange(partition *ScrollPartition) bool {
+	return partition.StartID != nil || partition.EndID != nil
+}
+
+// Internal method.
+// Returns a copy of request restricted to a partition.
+func partitionRequest(request *ScrollPoints, partition *ScrollPartition) *ScrollPoints {
+	clone, _ := shallowClone(request.ProtoReflect()).Interface().(*ScrollPoints)
+	if partition.Filter != nil {
+		clone.Filter = partition.Filter
+		if request.GetFilter() != nil {
+			clone.Filter = &Filter{Must: []*Condition{
+				NewFilterAsCondition(request.GetFilter()),
+				NewFilterAsCondition(partition.Filter),
+			}}
+		}
+	}
+	if partition.ShardKeySelector != nil {
+		clone.ShardKeySelector = partition.ShardKeySelector
+	}
+	if partition.StartID != nil {
+		clone.Offset = NewIDNum(*partition.StartID)
+	}
+	return clone
+}
@@ -0,0 +1,338 @@
+// This file contains payload edits that the SetPayload and DeletePayload operations cannot express,
+// such as JSON Merge Patch, appending to arrays, incrementing numbers and renaming keys.
+// They are applied client-side: the matching points are scrolled, their payloads edited,
+// and the changed payloads written back page by page.
+//
+// Keys are top-level keys or paths to nested objects, such as "address.city".
+// Writes made by other clients between the read and the write of a page are overwritten.
+//
+// USAGE:
+//
+//	report, err := client.RenamePayloadKey(ctx, "my_collection", nil, "colour", "color", &qdrant.PayloadEditOptions{
+//		Progress: func(report qdrant.PayloadEditReport) {
+//			log.Printf("scanned %d points, updated %d", report.Scanned, report.Updated)
+//		},
+//	})
+
+package qdrant
+
+import (
+	"context"
+	"errors"
+	"io"
+	"maps"
+	"math"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Default number of points read and written per page by the payload edits.
+const defaultPayloadEditPageSize = 100
+
+// PayloadEditOptions configures PatchPayload, AppendPayload, IncrementPayload and RenamePayloadKey.
+type PayloadEditOptions struct {
+	// Number of points read and written per page.
+	// Defaults to 100.
+	PageSize uint32
+	// Whether to wait for the writes of each page to be applied.
+	// Defaults to false.
+	Wait bool
+	// Called after each page with the counts so far, e.g. to report the progress of a migration.
+	Progress func(report PayloadEditReport)
+}
+
+// PayloadEditReport counts the points processed by a payload edit.
+type PayloadEditReport struct {
+	// Points matching the filter.
+	Scanned uint64
+	// Points whose payload was changed and written.
+	Updated uint64
+	// Points the edit does not apply to, e.g. with a non-numeric value for IncrementPayload.
+	Skipped uint64
+}
+
+// Internal method.
+func (o *PayloadEditOptions) getPageSize() uint32 {
+	if o.PageSize == 0 {
+		return defaultPayloadEditPageSize
+	}
+	return o.PageSize
+}
+
+// Applies a JSON Merge Patch (RFC 7396) to the payload of the points matching a filter:
+// keys of patch with a null value are removed, objects are merged recursively,
+// and other values replace the existing ones.
+//
+// Parameters:
+//   - ctx: The context for the request.
+//   - collectionName: The name of the collection.
+//   - filter: The points to edit. May be nil to edit all points.
+//   - patch: The merge patch.
+//   - options: How to apply the edit. May be nil.
+//
+// Returns:
+//   - *PayloadEditReport: The counts of scanned and updated points.
+//   - error: An error if the operation fails.
+func (c *Client) PatchPayload(ctx context.Context, collectionName string, filter *Filter, patch map[string]*Value,
+	options *PayloadEditOptions,
+) (*PayloadEditReport, error) {
+	return c.editPayload(ctx, "PatchPayload", collectionName, filter, options, func(payload map[string]*Value) bool {
+		mergePatchFields(payload, patch)
+		return true
+	})
+}
+
+// Appends values to the array at key in the payload of the points matching a filter.
+// A missing or null value is replaced with an array of values, and another value becomes the first element.
+//
+// Parameters:
+//   - ctx: The context for the request.
+//   - collectionName: The name of the collection.
+//   - filter: The points to edit. May be nil to edit all points.
+//   - key: The key or path of the array.
+//   - values: The values to append.
+//   - options: How to apply the edit. May be nil.
+//
+// Returns:
+//   - *PayloadEditReport: The counts of scanned and updated points.
+//   - error: An error if the operation fails.
+func (c *Client) AppendPayload(ctx context.Context, collectionName string, filter *Filter, key string, values []*Value,
+	options *PayloadEditOptions,
+) (*PayloadEditReport, error) {
+	path := strings.Split(key, ".")
+	return c.editPayload(ctx, "AppendPayload", collectionName, filter, options, func(payload map[string]*Value) bool {
+		var list []*Value
+		switch current := getPayloadPath(payload, path); current.GetKind().(type) {
+		case nil, *Value_NullValue:
+		case *Value_ListValue:
+			list = current.GetListValue().GetValues()
+		default:
+			list = []*Value{current}
+		}
+		setPayloadPath(payload, path, NewValueFromList(append(list, values...)...))
+		return true
+	})
+}
+
+// Adds delta to the number at key in the payload of the points matching a filter.
+// Integers stay integers if delta is a whole number. A missing or null value counts as 0.
+// Points with another value at key, or whose integer would overflow int64, are skipped.
+//
+// Parameters:
+//   - ctx: The context for the request.
+//   - collectionName: The name of the collection.
+//   - filter: The points to edit. May be nil to edit all points.
+//   - key: The key or path of the number.
+//   - delta: The value to add, e.g. -1 to decrement.
+//   - options: How to apply the edit. May be nil.
+//
+// Returns:
+//   - *PayloadEditReport: The counts of scanned, updated and skipped points.
+//   - error: An error if the operation fails.
+func (c *Client) IncrementPayload(ctx context.Context, collectionName string, filter *Filter, key string,
+	delta float64, options *PayloadEditOptions,
+) (*PayloadEditReport, error) {
+	path := strings.Split(key, ".")
+	integral := delta == math.Trunc(delta) && math.Abs(delta) < math.MaxInt64
+	return c.editPayload(ctx, "IncrementPayload", collectionName, filter, options, func(payload map[string]*Value) bool {
+		var value *Value
+		switch current := getPayloadPath(payload, path); current.GetKind().(type) {
+		case nil, *Value_NullValue, *Value_IntegerValue:
+			if integral {
+				sum, ok := addInt64(current.GetIntegerValue(), int64(delta))
+				if !ok {
+					return false
+				}
+				value = NewValueInt(sum)
+			} else {
+				value = NewValueDouble(float64(current.GetIntegerValue()) + delta)
+			}
+		case *Value_DoubleValue:
+			value = NewValueDouble(current.GetDoubleValue() + delta)
+		default:
+			return false
+		}
+		setPayloadPath(payload, path, value)
+		return true
+	})
+}
+
+// Moves the value at key from to key to in the payload of the points matching a filter,
+// replacing any value at to. Points without a value at from are left as is.
+//
+// Parameters:
+//   - ctx: The context for the request.
+//   - collectionName: The name of the collection.
+//   - filter: The points to edit. May be nil to edit all points.
+//   - from: The key or path to rename.
+//   - to: The new key or path.
+//   - options: How to apply the edit. May be nil.
+//
+// Returns:
+//   - *PayloadEditReport: The counts of scanned and updated points.
+//   - error: An error if the operation fails.
+func (c *Client) RenamePayloadKey(ctx context.Context, collectionName string, filter *Filter, from, to string,
+	options *PayloadEditOptions,
+) (*PayloadEditReport, error) {
+	if from == to {
+		return nil, newQdrantErr(errors.New("the keys to rename from and to are the same"), "RenamePayloadKey",
+			collectionName)
+	}
+	fromPath, toPath := strings.Split(from, "."), strings.Split(to, ".")
+	return c.editPayload(ctx, "RenamePayloadKey", collectionName, filter, options, func(payload map[string]*Value) bool {
+		value := getPayloadPath(payload, fromPath)
+		if value == nil {
+			return true
+		}
+		deletePayloadPath(payload, fromPath)
+		setPayloadPath(payload, toPath, value)
+		return true
+	})
+}
+
+// Internal method.
+// Scrolls the points matching filter, applies edit to a copy of their payloads,
+// and overwrites the payloads that changed. edit returns false to skip a point.
+func (c *Client) editPayload(ctx context.Context, operation, collectionName string, filter *Filter,
+	options *PayloadEditOptions, edit func(payload map[string]*Value) bool,
+) (*PayloadEditReport, error) {
+	if options == nil {
+		options = &PayloadEditOptions{}
+	}
+	report := &PayloadEditReport{}
+	pageSize := options.getPageSize()
+	it := c.ScrollAll(ctx, &ScrollPoints{
+		CollectionName: collectionName,
+		Filter:         filter,
+		Limit:          &pageSize,
+		WithPayload:    NewWithPayload(true),
+		WithVectors:    NewWithVectors(false),
+	})
+	defer it.Close()
+	for {
+		points, err := it.Next()
+		if errors.Is(err, io.EOF) {
+			return report, nil
+		}
+		if err != nil {
+			return report, newQdrantErr(err, operation, collectionName)
+		}
+		batch := c.NewBatchBuilder(collectionName).WithWait(options.Wait)
+		for _, point := range points {
+			report.Scanned++
+			payload := clonePayload(point.GetPayload())
+			if !edit(payload) {
+				report.Skipped++
+				continue
+			}
+			if payloadEqual(payload, point.GetPayload()) {
+				continue
+			}
+			batch.Add(NewPointsUpdateOverwritePayload(&PointsUpdateOperation_OverwritePayload{
+				Payload:        payload,
+				PointsSelector: NewPointsSelector(point.GetId()),
+			}))
+		}
+		if batch.Len() > 0 {
+			if _, err := batch.Execute(ctx); err != nil {
+				return report, newQdrantErr(err, operation, collectionName)
+			}
+			report.Updated += uint64(batch.Len())
+		}
+		if options.Progress != nil {
+			options.Progress(*report)
+		}
+	}
+}
+
+// Internal method.
+// Returns a + b, and false if the sum overflows int64.
+func addInt64(a, b int64) (int64, bool) {
+	sum := a + b
+	if (b > 0 && sum < a) || (b < 0 && sum > a) {
+		return 0, false
+	}
+	return sum, true
+}
+
+// Internal method.
+// Returns a deep copy of payload.
+func clonePayload(payload map[string]*Value) map[string]*Value {
+	clone := make(map[string]*Value, len(payload))
+	for key, value := range payload {
+		clone[key] = proto.CloneOf(value)
+	}
+	return clone
+}
+
+// Internal method.
+func payloadEqual(a, b map[string]*Value) bool {
+	return maps.EqualFunc(a, b, func(x, y *Value) bool {
+		return proto.Equal(x, y)
+	})
+}
+
+// Internal method.
+// Applies a JSON Merge Patch to the fields of an object.
+func mergePatchFields(fields, patch map[string]*Value) {
+	for key, value := range patch {
+		if _, ok := value.GetKind().(*Value_NullValue); ok || value == nil {
+			delete(fields, key)
+			continue
+		}
+		fields[key] = mergePatch(fields[key], value)
+	}
+}
+
+// Internal method.
+// Returns the result of applying a JSON Merge Patch to target.
+func mergePatch(target, patch *Value) *Value {
+	patchFields := patch.GetStructValue()
+	if patchFields == nil {
+		return patch
+	}
+	fields := target.GetStructValue().GetFields()
+	if fields == nil {
+		fields = make(map[string]*Value)
+	}
+	mergePatchFields(fields, patchFields.GetFields())
+	return NewValueFromFields(fields)
+}
+
+// Internal method.
+// Returns the value at path in payload, or nil if there is none.
+func getPayloadPath(payload map[string]*Value, path []string) *Value {
+	value := payload[path[0]]
+	for _, key := range path[1:] {
+		value = value.GetStructValue().GetFields()[key]
+	}
+	return value
+}
+
+// Internal method.
+// Sets the value at path in payload, replacing the intermediate values that are not objects with objects.
+func setPayloadPath(payload map[string]*Value, path []string, value *Value) {
+	fields := payload
+	for _, key := range path[:len(path)-1] {
+		next := fields[key].GetStructValue()
+		if next == nil {
+			next = &Struct{}
+			fields[key] = NewValueStruct(next)
+		}
+		if next.Fields == nil {
+			next.Fields = make(map[string]*Value)
+		}
+		fields = next.Fields
+	}
+	fields[path[len(path)-1]] = value
+}
+
+// Internal method.
+func deletePayloadPath(payload map[string]*Value, path []string) {
+	fields := payload
+	for _, key := range path[:len(path)-1] {
+		fields = fields[key].GetStructValue().GetFields()
+	}
+	delete(fields, path[len(path)-1])
+}
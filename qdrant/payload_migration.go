@@ -0,0 +1,417 @@
+// This file contains a runner for migrations of the payloads of a collection: the matching points are
+// scrolled without their vectors, their payloads transformed client-side, and the changes written
+// in parallel batches. The progress is checkpointed to a file, so that an interrupted migration resumes
+// where it stopped.
+//
+// USAGE:
+//
+//	report, err := client.MigratePayload(ctx, &qdrant.PayloadMigration{
+//		CollectionName: "my_collection",
+//		Transform: func(id *qdrant.PointId, payload map[string]*qdrant.Value) error {
+//			if price, ok := payload["price"]; ok {
+//				payload["price_cents"] = qdrant.NewValueInt(int64(price.GetDoubleValue() * 100))
+//				delete(payload, "price")
+//			}
+//			return nil
+//		},
+//		CheckpointFile: "price_migration.json",
+//	})
+
+package qdrant
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// Default number of write batches of a payload migration in flight at once.
+	defaultMigrationParallelism = 4
+	// Permissions of the checkpoint files of payload migrations.
+	checkpointFileMode = 0o600
+)
+
+// ErrSkipPoint can be returned by PayloadMigration.Transform to leave a point as is,
+// and count it as skipped.
+var ErrSkipPoint = errors.New("skip point")
+
+// PayloadMigrationMode is how a payload migration writes the changed payloads.
+type PayloadMigrationMode int
+
+const (
+	// Replace the whole payload with OverwritePayload.
+	PayloadMigrationOverwrite PayloadMigrationMode = iota
+	// Write only the changed keys with SetPayload, and remove the deleted keys with DeletePayload,
+	// leaving the other keys untouched in case they are written concurrently.
+	PayloadMigrationSet
+)
+
+// PayloadMigration describes a migration of the payloads of the points of a collection.
+type PayloadMigration struct {
+	// Name of the collection to migrate.
+	CollectionName string
+	// Restricts the migration to the points matching the filter. May be nil to migrate all points.
+	Filter *Filter
+	// Transforms the payload of a point in place. The payload is a copy and may be modified freely.
+	// Points whose payload does not change are not written. Return ErrSkipPoint to skip a point,
+	// and another error to stop the migration.
+	// Transform is called from a single goroutine, in the order of the point IDs.
+	// Transform must be idempotent: a failed write cancels the writes in flight, which may already be
+	// applied, and a resumed migration transforms their points again from their migrated payloads.
+	Transform func(id *PointId, payload map[string]*Value) error
+	// How to write the changed payloads.
+	// Defaults to PayloadMigrationOverwrite.
+	Mode PayloadMigrationMode
+	// Path of a file in which the progress is checkpointed after every page whose writes completed.
+	// If the file exists, the migration resumes from it, or fails if the file was written by a migration
+	// of another collection or filter. It is removed when the migration completes.
+	// If empty, the progress is not checkpointed.
+	CheckpointFile string
+	// Number of points read and written per page.
+	// Defaults to 100.
+	PageSize uint32
+	// Number of pages written in parallel.
+	// Defaults to 4.
+	Parallelism int
+	// Whether to wait for the writes of each page to be applied.
+	// Defaults to false.
+	Wait bool
+	// Called after each completed page with the counts so far, including the counts of the previous runs
+	// of a resumed migration.
+	Progress func(report PayloadEditReport)
+}
+
+// Internal type stored in the checkpoint file of a payload migration.
+type migrationCheckpoint struct {
+	// Collection and hash of the filter of the migration, so that a checkpoint is not resumed by another one.
+	Collection string `json:"collection"`
+	Filter     string `json:"filter"`
+	// Offset of the next page, as formatted by FormatPointID.
+	Offset string `json:"offset"`
+	// Counts of the completed pages.
+	Scanned uint64 `json:"scanned"`
+	Updated uint64 `json:"updated"`
+	Skipped uint64 `json:"skipped"`
+}
+
+// Internal method.
+func (m *PayloadMigration) getPageSize() uint32 {
+	if m.PageSize == 0 {
+		return defaultPayloadEditPageSize
+	}
+	return m.PageSize
+}
+
+// Internal method.
+func (m *PayloadMigration) getParallelism() int {
+	if m.Parallelism <= 0 {
+		return defaultMigrationParallelism
+	}
+	return m.Parallelism
+}
+
+// Runs a payload migration: scrolls the points matching the filter, transforms their payloads,
+// and writes the changed payloads, in parallel batches.
+//
+// Writes made by other clients between the read and the write of a page are overwritten,
+// unless they touch other keys and the mode is PayloadMigrationSet.
+//
+// Parameters:
+//   - ctx: The context for the request.
+//   - migration: The migration to run.
+//
+// Returns:
+//   - *PayloadEditReport: The counts of scanned, updated and skipped points.
+//   - error: An error if the transformation or a request fails. The pages completed before are checkpointed.
+func (c *Client) MigratePayload(ctx context.Context, migration *PayloadMigration) (*PayloadEditReport, error) {
+	collectionName := migration.CollectionName
+	checkpoint, err := loadMigrationCheckpoint(migration.CheckpointFile)
+	if err != nil {
+		return nil, newQdrantErr(err, "MigratePayload", collectionName)
+	}
+	filterHash, err := hashMigrationFilter(migration.Filter)
+	if err != nil {
+		return nil, newQdrantErr(err, "MigratePayload", collectionName)
+	}
+	if *checkpoint != (migrationCheckpoint{}) &&
+		(checkpoint.Collection != collectionName || checkpoint.Filter != filterHash) {
+		return nil, newQdrantErr(fmt.Errorf("checkpoint file %s was written by a migration of another "+
+			"collection or filter", migration.CheckpointFile), "MigratePayload", collectionName)
+	}
+	request := &ScrollPoints{
+		CollectionName: collectionName,
+		Filter:         migration.Filter,
+		Limit:          PtrOf(migration.getPageSize()),
+		WithPayload:    NewWithPayload(true),
+		WithVectors:    NewWithVectors(false),
+	}
+	if checkpoint.Offset != "" {
+		if request.Offset, err = ParsePointID(checkpoint.Offset); err != nil {
+			return nil, newQdrantErr(fmt.Errorf("invalid checkpoint: %w", err), "MigratePayload", collectionName)
+		}
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	runner := &migrationRunner{
+		migration:  migration,
+		filterHash: filterHash,
+		report: PayloadEditReport{
+			Scanned: checkpoint.Scanned,
+			Updated: checkpoint.Updated,
+			Skipped: checkpoint.Skipped,
+		},
+		pages:  make(map[int]*migrationPage),
+		cancel: cancel,
+	}
+	runner.run(ctx, c, request)
+	if runner.err != nil {
+		return &runner.report, newQdrantErr(runner.err, "MigratePayload", collectionName)
+	}
+	if migration.CheckpointFile != "" {
+		if err := os.Remove(migration.CheckpointFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return &runner.report, newQdrantErr(err, "MigratePayload", collectionName)
+		}
+	}
+	return &runner.report, nil
+}
+
+// Internal type holding the state of a MigratePayload call.
+type migrationRunner struct {
+	migration  *PayloadMigration
+	filterHash string
+	cancel     context.CancelFunc
+
+	mu     sync.Mutex
+	report PayloadEditReport
+	err    error
+	// Pages whose writes completed, by sequence number, waiting for the previous pages to complete.
+	pages map[int]*migrationPage
+	// Sequence number of the next page to checkpoint.
+	nextPage int
+}
+
+// Internal type holding the counts of a page and the offset of the page after it.
+type migrationPage struct {
+	counts PayloadEditReport
+	// Offset of the next page. Nil after the last page.
+	nextOffset *PointId
+}
+
+// Internal method.
+// Reads and transforms the pages, and writes them with at most Parallelism writes in flight.
+func (r *migrationRunner) run(ctx context.Context, client *Client, request *ScrollPoints) {
+	it := client.ScrollAll(ctx, request)
+	defer it.Close()
+	slots := make(chan struct{}, r.migration.getParallelism())
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for seq := 0; ; seq++ {
+		points, err := it.Next()
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		if err != nil {
+			r.fail(err)
+			return
+		}
+		page := &migrationPage{}
+		if !it.done {
+			page.nextOffset = it.request.GetOffset()
+		}
+		batch := client.NewBatchBuilder(request.GetCollectionName()).WithWait(r.migration.Wait)
+		if err := r.transform(points, batch, &page.counts); err != nil {
+			r.fail(err)
+			return
+		}
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			r.fail(ctx.Err())
+			return
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			if batch.Len() > 0 {
+				if _, err := batch.Execute(ctx); err != nil {
+					// The next pages cannot be checkpointed anymore: stop the writes in flight.
+					r.fail(err)
+					r.cancel()
+					return
+				}
+			}
+			r.complete(seq, page)
+		}()
+	}
+}
+
+// Internal method.
+// Transforms the payloads of a page, and adds the writes of the changed ones to batch.
+func (r *migrationRunner) transform(points []*RetrievedPoint, batch *BatchBuilder, counts *PayloadEditReport) error {
+	for _, point := range points {
+		counts.Scanned++
+		payload := clonePayload(point.GetPayload())
+		err := r.migration.Transform(point.GetId(), payload)
+		if errors.Is(err, ErrSkipPoint) {
+			counts.Skipped++
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to transform the payload of point %s: %w", FormatPointID(point.GetId()), err)
+		}
+		if payloadEqual(payload, point.GetPayload()) {
+			continue
+		}
+		counts.Updated++
+		selector := NewPointsSelector(point.GetId())
+		if r.migration.Mode == PayloadMigrationOverwrite {
+			batch.Add(NewPointsUpdateOverwritePayload(&PointsUpdateOperation_OverwritePayload{
+				Payload:        payload,
+				PointsSelector: selector,
+			}))
+			continue
+		}
+		changed, removed := diffPayload(point.GetPayload(), payload)
+		if len(changed) > 0 {
+			batch.SetPayload(selector, changed)
+		}
+		if len(removed) > 0 {
+			batch.DeletePayload(selector, removed...)
+		}
+	}
+	return nil
+}
+
+// Internal method.
+// Records the completion of a page, and checkpoints the pages completed without gaps.
+func (r *migrationRunner) complete(seq int, page *migrationPage) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pages[seq] = page
+	var last *migrationPage
+	for next, ok := r.pages[r.nextPage]; ok; next, ok = r.pages[r.nextPage] {
+		delete(r.pages, r.nextPage)
+		r.nextPage++
+		r.report.Scanned += next.counts.Scanned
+		r.report.Updated += next.counts.Updated
+		r.report.Skipped += next.counts.Skipped
+		last = next
+	}
+	if last == nil {
+		return
+	}
+	if r.migration.CheckpointFile != "" && last.nextOffset != nil {
+		err := saveMigrationCheckpoint(r.migration.CheckpointFile, &migrationCheckpoint{
+			Collection: r.migration.CollectionName,
+			Filter:     r.filterHash,
+			Offset:     FormatPointID(last.nextOffset),
+			Scanned:    r.report.Scanned,
+			Updated:    r.report.Updated,
+			Skipped:    r.report.Skipped,
+		})
+		if err != nil {
+			r.setErr(err)
+			r.cancel()
+			return
+		}
+	}
+	if r.migration.Progress != nil {
+		r.migration.Progress(r.report)
+	}
+}
+
+// Internal method.
+// Records the first error. The reads stop, and the writes in flight complete and are checkpointed.
+func (r *migrationRunner) fail(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.setErr(err)
+}
+
+// Internal method.
+// Records err if it is the first error. The caller must hold r.mu.
+func (r *migrationRunner) setErr(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+// Internal method.
+// Returns the keys of after that are new or changed, and the keys of before missing from after.
+func diffPayload(before, after map[string]*Value) (map[string]*Value, []string) {
+	changed := make(map[string]*Value)
+	for key, value := range after {
+		if !proto.Equal(value, before[key]) {
+			changed[key] = value
+		}
+	}
+	var removed []string
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	return changed, removed
+}
+
+// Internal method.
+// Returns the SHA-256 of the REST JSON of filter, which has sorted keys. A nil filter hashes as an empty one.
+func hashMigrationFilter(filter *Filter) (string, error) {
+	if filter == nil {
+		filter = &Filter{}
+	}
+	data, err := FilterToJSON(filter)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Internal method.
+// Returns an empty checkpoint if path is empty or does not exist.
+func loadMigrationCheckpoint(path string) (*migrationCheckpoint, error) {
+	checkpoint := &migrationCheckpoint{}
+	if path == "" {
+		return checkpoint, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return checkpoint, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint: %w", err)
+	}
+	return checkpoint, nil
+}
+
+// Internal method.
+// Writes the checkpoint to a temporary file renamed to path, so that an interruption
+// never leaves a partially written checkpoint.
+func saveMigrationCheckpoint(path string, checkpoint *migrationCheckpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, checkpointFileMode); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,525 @@
+// This file contains the inference of the payload schema of a collection from its points,
+// and the recommendation of payload indexes for it.
+// https://qdrant.tech/documentation/concepts/indexing/#payload-index
+//
+// USAGE:
+//
+//	report, err := client.InferPayloadSchema(ctx, "my_collection", &qdrant.PayloadSchemaOptions{
+//		SampleSize: 10000,
+//	})
+//	recommendations := report.RecommendIndexes()
+//	err = client.CreateRecommendedIndexes(ctx, "my_collection", recommendations)
+
+package qdrant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+const (
+	defaultSchemaPageSize     = 256
+	defaultMaxDistinctValues  = 1024
+	textIndexMinAverageWords  = 3
+	tenantMinPointsPerValue   = 10
+	uuidStringLength          = 36
+	geoPayloadFieldCount      = 2
+	principalMinDistinctRatio = 2
+)
+
+// PayloadValueType is the type of a payload value inferred by PayloadSchemaInferrer.
+type PayloadValueType string
+
+const (
+	// A string that is neither a UUID nor a datetime.
+	PayloadValueString PayloadValueType = "string"
+	// A string holding a UUID in its canonical hyphenated form, e.g. "550e8400-e29b-41d4-a716-446655440000".
+	PayloadValueUUID PayloadValueType = "uuid"
+	// A string holding an RFC 3339 datetime, also with a space as separator, without time zone, or a date only.
+	PayloadValueDatetime PayloadValueType = "datetime"
+	// An integer number.
+	PayloadValueInteger PayloadValueType = "integer"
+	// A floating-point number.
+	PayloadValueFloat PayloadValueType = "float"
+	// A boolean.
+	PayloadValueBool PayloadValueType = "bool"
+	// An object with only numeric "lat" and "lon" fields.
+	PayloadValueGeo PayloadValueType = "geo"
+	// Any other object, whose fields are inferred as keys of their own.
+	PayloadValueObject PayloadValueType = "object"
+)
+
+// PayloadSchemaOptions configures Client.InferPayloadSchema.
+type PayloadSchemaOptions struct {
+	// Only scan the points matching the filter. Defaults to all points.
+	Filter *Filter
+	// Maximum number of points to scan. If 0, all points are scanned.
+	SampleSize uint64
+	// Number of points fetched per scroll request. Defaults to 256.
+	PageSize uint32
+	// Maximum number of distinct values tracked per key.
+	// Above it, the cardinality of the key is only known to be at least this value. Defaults to 1024.
+	MaxDistinctValues int
+}
+
+// PayloadSchemaReport is the payload schema of a collection inferred from its points.
+type PayloadSchemaReport struct {
+	// Number of points scanned.
+	Points uint64
+	// Statistics of every key path found in the payloads, sorted by key.
+	Keys []*PayloadKeyStats
+}
+
+// PayloadKeyStats describes the values found at a payload key path.
+//
+// Keys of nested objects are joined with ".", and keys of objects in arrays are suffixed with "[]",
+// as in the payload index and filter keys, e.g. "address.city" or "items[].sku".
+type PayloadKeyStats struct {
+	// Key path of the values.
+	Key string
+	// Number of points with the key, including null values.
+	Present uint64
+	// Number of points without the key.
+	Missing uint64
+	// Number of null values.
+	Nulls uint64
+	// Number of array values. Array elements are counted as values of the key itself.
+	Arrays uint64
+	// Number of non-null values per type, counting array elements individually.
+	Types map[PayloadValueType]uint64
+	// Number of distinct values, not counting objects.
+	Distinct int
+	// Whether there are more distinct values than tracked, in which case Distinct is a lower bound.
+	DistinctCapped bool
+	// Average number of words of string values.
+	AverageWords float64
+	// Existing payload index on the key, if any. Only set by Client.InferPayloadSchema.
+	Index *PayloadSchemaInfo
+}
+
+// Nullable returns whether the key is missing or null in some points.
+func (s *PayloadKeyStats) Nullable() bool {
+	return s.Missing > 0 || s.Nulls > 0
+}
+
+// IsArray returns whether the key holds arrays in some points.
+func (s *PayloadKeyStats) IsArray() bool {
+	return s.Arrays > 0
+}
+
+// Type returns the type of all the non-null values of the key.
+// Integers mixed with floats are reported as floats, and UUIDs or datetimes mixed with other strings as strings.
+// Returns false if the key has no non-null values or values of otherwise incompatible types.
+func (s *PayloadKeyStats) Type() (PayloadValueType, bool) {
+	var types []PayloadValueType
+	for t, count := range s.Types {
+		if count > 0 {
+			types = append(types, t)
+		}
+	}
+	switch {
+	case len(types) == 1:
+		return types[0], true
+	case len(types) == 0:
+		return "", false
+	}
+	onlyOf := func(allowed ...PayloadValueType) bool {
+		for _, t := range types {
+			if !slices.Contains(allowed, t) {
+				return false
+			}
+		}
+		return true
+	}
+	switch {
+	case onlyOf(PayloadValueInteger, PayloadValueFloat):
+		return PayloadValueFloat, true
+	case onlyOf(PayloadValueString, PayloadValueUUID, PayloadValueDatetime):
+		return PayloadValueString, true
+	}
+	return "", false
+}
+
+// IndexRecommendation is a payload index suggested by PayloadSchemaReport.RecommendIndexes.
+type IndexRecommendation struct {
+	// Key path to index.
+	Key string
+	// Type of the index.
+	FieldType FieldType
+	// Parameters of the index, e.g. the is_tenant or is_principal flags.
+	Params *PayloadIndexParams
+	// Why the index type and parameters were chosen.
+	Reason string
+}
+
+// Request returns the request creating the recommended index on the collection.
+func (r *IndexRecommendation) Request(collectionName string) *CreateFieldIndexCollection {
+	return &CreateFieldIndexCollection{
+		CollectionName:   collectionName,
+		Wait:             PtrOf(true),
+		FieldName:        r.Key,
+		FieldType:        r.FieldType.Enum(),
+		FieldIndexParams: r.Params,
+	}
+}
+
+// RecommendIndexes suggests a payload index for every key holding values of a single indexable type,
+// that is not indexed yet.
+//
+// Strings are indexed as text if they average at least 3 words, and as keywords otherwise.
+// The keyword or UUID key with the lowest cardinality is flagged as tenant if it is set in every point
+// to a single value shared by at least 10 points on average.
+// The datetime key with the highest cardinality is flagged as principal if it is set in every point
+// to a single value.
+// The heuristics are only as good as the sample the report was inferred from, so review the
+// recommendations before applying them.
+func (r *PayloadSchemaReport) RecommendIndexes() []*IndexRecommendation {
+	var recommendations []*IndexRecommendation
+	var tenant, principal *IndexRecommendation
+	var tenantStats, principalStats *PayloadKeyStats
+	for _, stats := range r.Keys {
+		if stats.Index != nil {
+			continue
+		}
+		valueType, ok := stats.Type()
+		if !ok {
+			continue
+		}
+		recommendation := recommendIndex(stats, valueType)
+		if recommendation == nil {
+			continue
+		}
+		recommendations = append(recommendations, recommendation)
+		singleValued := stats.Missing == 0 && stats.Nulls == 0 && stats.Arrays == 0
+		switch {
+		case !singleValued:
+		case recommendation.FieldType == FieldType_FieldTypeKeyword || recommendation.FieldType == FieldType_FieldTypeUuid:
+			if stats.Distinct > 1 && !stats.DistinctCapped &&
+				uint64(stats.Distinct)*tenantMinPointsPerValue <= r.Points &&
+				(tenantStats == nil || stats.Distinct < tenantStats.Distinct) {
+				tenant, tenantStats = recommendation, stats
+			}
+		case recommendation.FieldType == FieldType_FieldTypeDatetime:
+			if (stats.DistinctCapped || uint64(stats.Distinct)*principalMinDistinctRatio > r.Points) &&
+				(principalStats == nil || stats.Distinct > principalStats.Distinct) {
+				principal, principalStats = recommendation, stats
+			}
+		}
+	}
+	if tenant != nil {
+		if params := tenant.Params.GetKeywordIndexParams(); params != nil {
+			params.IsTenant = PtrOf(true)
+		} else {
+			tenant.Params.GetUuidIndexParams().IsTenant = PtrOf(true)
+		}
+		tenant.Reason += fmt.Sprintf("; set in every point, with %d distinct values: tenant", tenantStats.Distinct)
+	}
+	if principal != nil {
+		principal.Params.GetDatetimeIndexParams().IsPrincipal = PtrOf(true)
+		principal.Reason += "; set in every point, with mostly distinct values: principal"
+	}
+	return recommendations
+}
+
+// Internal method.
+func recommendIndex(stats *PayloadKeyStats, valueType PayloadValueType) *IndexRecommendation {
+	recommendation := &IndexRecommendation{
+		Key:    stats.Key,
+		Reason: fmt.Sprintf("%s values", valueType),
+	}
+	switch valueType {
+	case PayloadValueString:
+		if stats.AverageWords >= textIndexMinAverageWords {
+			recommendation.FieldType = FieldType_FieldTypeText
+			recommendation.Params = NewPayloadIndexParamsText(&TextIndexParams{Tokenizer: TokenizerType_Word})
+			recommendation.Reason = fmt.Sprintf("string values of %.1f words on average", stats.AverageWords)
+		} else {
+			recommendation.FieldType = FieldType_FieldTypeKeyword
+			recommendation.Params = NewPayloadIndexParamsKeyword(&KeywordIndexParams{})
+		}
+	case PayloadValueUUID:
+		recommendation.FieldType = FieldType_FieldTypeUuid
+		recommendation.Params = NewPayloadIndexParamsUUID(&UuidIndexParams{})
+	case PayloadValueDatetime:
+		recommendation.FieldType = FieldType_FieldTypeDatetime
+		recommendation.Params = NewPayloadIndexParamsDatetime(&DatetimeIndexParams{})
+	case PayloadValueInteger:
+		recommendation.FieldType = FieldType_FieldTypeInteger
+		recommendation.Params = NewPayloadIndexParamsInt(&IntegerIndexParams{Lookup: PtrOf(true), Range: PtrOf(true)})
+	case PayloadValueFloat:
+		recommendation.FieldType = FieldType_FieldTypeFloat
+		recommendation.Params = NewPayloadIndexParamsFloat(&FloatIndexParams{})
+	case PayloadValueBool:
+		recommendation.FieldType = FieldType_FieldTypeBool
+		recommendation.Params = NewPayloadIndexParamsBool(&BoolIndexParams{})
+	case PayloadValueGeo:
+		recommendation.FieldType = FieldType_FieldTypeGeo
+		recommendation.Params = NewPayloadIndexParamsGeo(&GeoIndexParams{})
+	default:
+		return nil
+	}
+	return recommendation
+}
+
+// PayloadSchemaInferrer infers a payload schema from payloads added one at a time.
+// Obtain one with NewPayloadSchemaInferrer. Client.InferPayloadSchema uses it on scrolled points.
+type PayloadSchemaInferrer struct {
+	maxDistinctValues int
+	points            uint64
+	keys              map[string]*payloadKeyCollector
+}
+
+// Internal type accumulating the statistics of a key.
+type payloadKeyCollector struct {
+	stats    PayloadKeyStats
+	distinct map[any]struct{}
+	strings  uint64
+	words    uint64
+	// Number of the last point the key was found in, to count each point once.
+	lastPoint uint64
+}
+
+// NewPayloadSchemaInferrer creates an inferrer tracking up to maxDistinctValues distinct values per key.
+// If maxDistinctValues is 0, defaults to 1024.
+func NewPayloadSchemaInferrer(maxDistinctValues int) *PayloadSchemaInferrer {
+	if maxDistinctValues <= 0 {
+		maxDistinctValues = defaultMaxDistinctValues
+	}
+	return &PayloadSchemaInferrer{
+		maxDistinctValues: maxDistinctValues,
+		keys:              make(map[string]*payloadKeyCollector),
+	}
+}
+
+// Add adds the payload of a point.
+func (i *PayloadSchemaInferrer) Add(payload map[string]*Value) {
+	i.points++
+	i.addFields(payload, "")
+}
+
+// Report returns the schema inferred from the payloads added so far.
+func (i *PayloadSchemaInferrer) Report() *PayloadSchemaReport {
+	report := &PayloadSchemaReport{
+		Points: i.points,
+		Keys:   make([]*PayloadKeyStats, 0, len(i.keys)),
+	}
+	for _, collector := range i.keys {
+		stats := collector.stats
+		stats.Missing = i.points - stats.Present
+		stats.Types = make(map[PayloadValueType]uint64, len(collector.stats.Types))
+		for t, count := range collector.stats.Types {
+			stats.Types[t] = count
+		}
+		stats.Distinct = len(collector.distinct)
+		if collector.strings > 0 {
+			stats.AverageWords = float64(collector.words) / float64(collector.strings)
+		}
+		report.Keys = append(report.Keys, &stats)
+	}
+	slices.SortFunc(report.Keys, func(a, b *PayloadKeyStats) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return report
+}
+
+// Internal method.
+func (i *PayloadSchemaInferrer) addFields(fields map[string]*Value, prefix string) {
+	for name, value := range fields {
+		i.addValue(prefix+name, value, false)
+	}
+}
+
+// Internal method.
+func (i *PayloadSchemaInferrer) addValue(key string, value *Value, inArray bool) {
+	collector := i.keys[key]
+	if collector == nil {
+		collector = &payloadKeyCollector{
+			stats:    PayloadKeyStats{Key: key, Types: make(map[PayloadValueType]uint64)},
+			distinct: make(map[any]struct{}),
+		}
+		i.keys[key] = collector
+	}
+	if collector.lastPoint != i.points {
+		collector.lastPoint = i.points
+		collector.stats.Present++
+	}
+	switch v := value.GetKind().(type) {
+	case *Value_NullValue:
+		collector.stats.Nulls++
+	case *Value_BoolValue:
+		collector.add(PayloadValueBool, v.BoolValue, i.maxDistinctValues)
+	case *Value_IntegerValue:
+		collector.add(PayloadValueInteger, v.IntegerValue, i.maxDistinctValues)
+	case *Value_DoubleValue:
+		collector.add(PayloadValueFloat, v.DoubleValue, i.maxDistinctValues)
+	case *Value_StringValue:
+		collector.strings++
+		collector.words += uint64(len(strings.Fields(v.StringValue)))
+		collector.add(inferStringType(v.StringValue), v.StringValue, i.maxDistinctValues)
+	case *Value_ListValue:
+		if !inArray {
+			collector.stats.Arrays++
+		}
+		for _, element := range v.ListValue.GetValues() {
+			if _, ok := element.GetKind().(*Value_StructValue); ok {
+				// Keys of objects in arrays are addressed with "[]".
+				i.addValue(key+"[]", element, true)
+				continue
+			}
+			i.addValue(key, element, true)
+		}
+	case *Value_StructValue:
+		fields := v.StructValue.GetFields()
+		if isGeoPayload(fields) {
+			collector.stats.Types[PayloadValueGeo]++
+			return
+		}
+		collector.stats.Types[PayloadValueObject]++
+		i.addFields(fields, key+".")
+	}
+}
+
+// Internal method.
+func (c *payloadKeyCollector) add(valueType PayloadValueType, value any, maxDistinctValues int) {
+	c.stats.Types[valueType]++
+	if _, ok := c.distinct[value]; ok {
+		return
+	}
+	if len(c.distinct) >= maxDistinctValues {
+		c.stats.DistinctCapped = true
+		return
+	}
+	c.distinct[value] = struct{}{}
+}
+
+// Internal method.
+func inferStringType(value string) PayloadValueType {
+	if isUUIDString(value) {
+		return PayloadValueUUID
+	}
+	if _, ok := parseDatetime(value); ok {
+		return PayloadValueDatetime
+	}
+	return PayloadValueString
+}
+
+// Internal method.
+// Reports whether value is a UUID in its canonical hyphenated form.
+func isUUIDString(value string) bool {
+	if len(value) != uuidStringLength {
+		return false
+	}
+	for i, r := range value {
+		switch i {
+		case 8, 13, 18, 23: //nolint:mnd // Positions of the hyphens.
+			if r != '-' {
+				return false
+			}
+		default:
+			if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Internal method.
+// Reports whether fields are a geo point, i.e. only numeric "lat" and "lon" fields.
+func isGeoPayload(fields map[string]*Value) bool {
+	if len(fields) != geoPayloadFieldCount {
+		return false
+	}
+	for _, name := range []string{"lat", "lon"} {
+		switch fields[name].GetKind().(type) {
+		case *Value_DoubleValue, *Value_IntegerValue:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Infers the payload schema of a collection by scrolling through its points.
+// The existing payload indexes are reported in PayloadKeyStats.Index.
+//
+// Parameters:
+//   - ctx: The context for the request.
+//   - collectionName: The name of the collection.
+//   - options: Which points to scan. May be nil to scan all points.
+//
+// Returns:
+//   - *PayloadSchemaReport: The inferred schema.
+//   - error: An error if the operation fails.
+func (c *Client) InferPayloadSchema(ctx context.Context, collectionName string, options *PayloadSchemaOptions,
+) (*PayloadSchemaReport, error) {
+	if options == nil {
+		options = &PayloadSchemaOptions{}
+	}
+	info, err := c.GetCollectionInfo(ctx, collectionName)
+	if err != nil {
+		return nil, newQdrantErr(err, "InferPayloadSchema", collectionName)
+	}
+	pageSize := options.PageSize
+	if pageSize == 0 {
+		pageSize = defaultSchemaPageSize
+	}
+	if options.SampleSize > 0 && options.SampleSize < uint64(pageSize) {
+		pageSize = uint32(options.SampleSize)
+	}
+	inferrer := NewPayloadSchemaInferrer(options.MaxDistinctValues)
+	it := c.ScrollAll(ctx, &ScrollPoints{
+		CollectionName: collectionName,
+		Filter:         options.Filter,
+		Limit:          &pageSize,
+		WithPayload:    NewWithPayload(true),
+		WithVectors:    NewWithVectors(false),
+	})
+	defer it.Close()
+	for options.SampleSize == 0 || inferrer.points < options.SampleSize {
+		points, err := it.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, newQdrantErr(err, "InferPayloadSchema", collectionName)
+		}
+		for _, point := range points {
+			if options.SampleSize > 0 && inferrer.points >= options.SampleSize {
+				break
+			}
+			inferrer.Add(point.GetPayload())
+		}
+	}
+	report := inferrer.Report()
+	for _, stats := range report.Keys {
+		stats.Index = info.GetPayloadSchema()[stats.Key]
+	}
+	return report, nil
+}
+
+// Creates the payload indexes recommended by PayloadSchemaReport.RecommendIndexes, waiting for each of them.
+//
+// Parameters:
+//   - ctx: The context for the request.
+//   - collectionName: The name of the collection.
+//   - recommendations: The indexes to create.
+//
+// Returns:
+//   - error: An error if creating an index fails. The indexes before it are created.
+func (c *Client) CreateRecommendedIndexes(ctx context.Context, collectionName string,
+	recommendations []*IndexRecommendation,
+) error {
+	defer c.InvalidateCollectionSchema(collectionName)
+	for _, recommendation := range recommendations {
+		_, err := c.CreateFieldIndex(ctx, recommendation.Request(collectionName))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
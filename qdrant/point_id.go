@@ -0,0 +1,312 @@
+// This file contains helpers to derive deterministic point IDs from external keys or from the content of points,
+// so that ingesting the same source record again overwrites the same point instead of creating a duplicate.
+//
+// USAGE:
+//
+//	namespace := uuid.NewSHA1(uuid.NameSpaceURL, []byte("https://example.com/products"))
+//	points := []*qdrant.PointStruct{...}
+//	err := qdrant.AssignPointIDs(points, qdrant.KeyPointIDs(namespace, func(point *qdrant.PointStruct) (string, error) {
+//		return point.GetPayload()["sku"].GetStringValue(), nil
+//	}))
+
+package qdrant
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// NewIDFromKey creates a *PointId with the UUIDv5 of key in namespace.
+// The same namespace and key always give the same ID. Use a namespace per source of keys,
+// e.g. uuid.NewSHA1(uuid.NameSpaceURL, []byte(sourceURL)), so that equal keys of different sources do not collide.
+func NewIDFromKey(namespace uuid.UUID, key string) *PointId {
+	return NewIDUUID(uuid.NewSHA1(namespace, []byte(key)).String())
+}
+
+// NewIDFromContent creates a *PointId with the UUIDv5, in namespace, of a hash of the payload and vectors of a point.
+// Points with equal payloads and vectors get the same ID. The vectors may be nil.
+//
+// Returns an error if the payload or vectors cannot be serialized, e.g. if a string is not valid UTF-8.
+func NewIDFromContent(namespace uuid.UUID, payload map[string]*Value, vectors *Vectors) (*PointId, error) {
+	content, err := marshalContent(payload, vectors)
+	if err != nil {
+		return nil, err
+	}
+	return NewIDUUID(uuid.NewSHA1(namespace, content).String()), nil
+}
+
+// Internal method.
+// Returns a serialization of the payload and vectors of a point that is stable across calls,
+// binaries and dependency versions, so that content-derived IDs and hashes never change.
+// DO NOT CHANGE the encoding: it would change the IDs of NewIDFromContent and the hashes of SyncPoints.
+//
+// The payload is encoded as JSON with sorted keys, integers in decimal, doubles in their shortest decimal form,
+// and only the characters that JSON requires escaped. Each vector follows, in the order of the names:
+// the name and a kind byte, then, in little-endian order, the lengths as uint32 and the floats as their
+// IEEE 754 bits. Sparse vectors are sorted by index. Inference inputs are encoded as JSON objects.
+func marshalContent(payload map[string]*Value, vectors *Vectors) ([]byte, error) {
+	var e contentEncoder
+	if err := e.writeFields(payload); err != nil {
+		return nil, fmt.Errorf("failed to serialize the point content: %w", err)
+	}
+	named := vectors.GetVectors().GetVectors()
+	if vector := vectors.GetVector(); vector != nil {
+		named = map[string]*Vector{"": vector}
+	}
+	for _, name := range slices.Sorted(maps.Keys(named)) {
+		e.writeString(name)
+		if err := e.writeVector(named[name]); err != nil {
+			return nil, fmt.Errorf("failed to serialize the point content: vector %q: %w", name, err)
+		}
+	}
+	return e.buf.Bytes(), nil
+}
+
+// Kinds of the vectors in the serialization of marshalContent.
+const (
+	contentDense byte = iota + 1
+	contentSparse
+	contentMultiDense
+	contentDocument
+	contentImage
+	contentObject
+)
+
+// Internal type writing the serialization of marshalContent.
+type contentEncoder struct {
+	buf bytes.Buffer
+}
+
+// Internal method.
+func (e *contentEncoder) writeVector(vector *Vector) error {
+	if sparse := vector.sparseVector(); sparse != nil {
+		if len(sparse.GetIndices()) != len(sparse.GetValues()) {
+			return errors.New("sparse vector with different numbers of indices and values")
+		}
+		order := make([]int, len(sparse.GetIndices()))
+		for i := range order {
+			order[i] = i
+		}
+		slices.SortStableFunc(order, func(a, b int) int {
+			return cmp.Compare(sparse.GetIndices()[a], sparse.GetIndices()[b])
+		})
+		e.buf.WriteByte(contentSparse)
+		e.writeUint32(uint32(len(order))) //nolint:gosec // Vectors are far smaller than 4 billion values.
+		for _, i := range order {
+			e.writeUint32(sparse.GetIndices()[i])
+			e.writeUint32(math.Float32bits(sparse.GetValues()[i]))
+		}
+		return nil
+	}
+	if multi := vector.multiDenseVector(); multi != nil {
+		e.buf.WriteByte(contentMultiDense)
+		e.writeUint32(uint32(len(multi.GetVectors()))) //nolint:gosec // Vectors are far smaller than 4 billion values.
+		for _, dense := range multi.GetVectors() {
+			e.writeFloats(dense.GetData())
+		}
+		return nil
+	}
+	switch v := vector.GetVector().(type) {
+	case *Vector_Document:
+		e.buf.WriteByte(contentDocument)
+		return e.writeFields(map[string]*Value{
+			"text":    NewValueString(v.Document.GetText()),
+			"model":   NewValueString(v.Document.GetModel()),
+			"options": NewValueFromFields(v.Document.GetOptions()),
+		})
+	case *Vector_Image:
+		e.buf.WriteByte(contentImage)
+		return e.writeFields(map[string]*Value{
+			"image":   v.Image.GetImage(),
+			"model":   NewValueString(v.Image.GetModel()),
+			"options": NewValueFromFields(v.Image.GetOptions()),
+		})
+	case *Vector_Object:
+		e.buf.WriteByte(contentObject)
+		return e.writeFields(map[string]*Value{
+			"object":  v.Object.GetObject(),
+			"model":   NewValueString(v.Object.GetModel()),
+			"options": NewValueFromFields(v.Object.GetOptions()),
+		})
+	}
+	e.buf.WriteByte(contentDense)
+	e.writeFloats(vector.denseData())
+	return nil
+}
+
+// Internal method.
+func (e *contentEncoder) writeFloats(floats []float32) {
+	e.writeUint32(uint32(len(floats))) //nolint:gosec // Vectors are far smaller than 4 billion values.
+	for _, f := range floats {
+		e.writeUint32(math.Float32bits(f))
+	}
+}
+
+// Internal method.
+func (e *contentEncoder) writeUint32(u uint32) {
+	e.buf.Write(binary.LittleEndian.AppendUint32(nil, u))
+}
+
+// Internal method.
+// Writes a string prefixed with its length.
+func (e *contentEncoder) writeString(s string) {
+	e.writeUint32(uint32(len(s))) //nolint:gosec // Vector names are far smaller than 4 GiB.
+	e.buf.WriteString(s)
+}
+
+// Internal method.
+// Writes a JSON object with sorted keys.
+func (e *contentEncoder) writeFields(fields map[string]*Value) error {
+	e.buf.WriteByte('{')
+	for i, key := range slices.Sorted(maps.Keys(fields)) {
+		if i > 0 {
+			e.buf.WriteByte(',')
+		}
+		if err := e.writeJSONString(key); err != nil {
+			return err
+		}
+		e.buf.WriteByte(':')
+		if err := e.writeValue(fields[key]); err != nil {
+			return err
+		}
+	}
+	e.buf.WriteByte('}')
+	return nil
+}
+
+// Internal method.
+func (e *contentEncoder) writeValue(value *Value) error {
+	switch v := value.GetKind().(type) {
+	case *Value_BoolValue:
+		e.buf.WriteString(strconv.FormatBool(v.BoolValue))
+	case *Value_IntegerValue:
+		e.buf.WriteString(strconv.FormatInt(v.IntegerValue, 10))
+	case *Value_DoubleValue:
+		e.buf.WriteString(strconv.FormatFloat(v.DoubleValue, 'g', -1, 64))
+	case *Value_StringValue:
+		return e.writeJSONString(v.StringValue)
+	case *Value_StructValue:
+		return e.writeFields(v.StructValue.GetFields())
+	case *Value_ListValue:
+		e.buf.WriteByte('[')
+		for i, item := range v.ListValue.GetValues() {
+			if i > 0 {
+				e.buf.WriteByte(',')
+			}
+			if err := e.writeValue(item); err != nil {
+				return err
+			}
+		}
+		e.buf.WriteByte(']')
+	default:
+		e.buf.WriteString("null")
+	}
+	return nil
+}
+
+// Internal method.
+// Writes a JSON string, escaping only the quotes, backslashes and control characters.
+func (e *contentEncoder) writeJSONString(s string) error {
+	if !utf8.ValidString(s) {
+		return fmt.Errorf("invalid UTF-8 string %q", s)
+	}
+	e.buf.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			e.buf.WriteByte('\\')
+			e.buf.WriteRune(r)
+		case r < ' ':
+			fmt.Fprintf(&e.buf, "\\u%04x", r)
+		default:
+			e.buf.WriteRune(r)
+		}
+	}
+	e.buf.WriteByte('"')
+	return nil
+}
+
+// FormatPointID returns the string form of a point ID: the decimal number of a numeric ID,
+// or the UUID of a UUID ID in its canonical, lowercase form, as returned by the server.
+// Returns an empty string for a nil or empty ID.
+// ParsePointID reverts it.
+func FormatPointID(id *PointId) string {
+	switch v := id.GetPointIdOptions().(type) {
+	case *PointId_Num:
+		return strconv.FormatUint(v.Num, 10)
+	case *PointId_Uuid:
+		if parsed, err := uuid.Parse(v.Uuid); err == nil {
+			return parsed.String()
+		}
+		return v.Uuid
+	}
+	return ""
+}
+
+// ParsePointID parses the string form of a point ID, as returned by FormatPointID.
+// Decimal numbers give numeric IDs, and UUIDs, in any of the forms accepted by uuid.Parse,
+// give UUID IDs in their canonical form.
+func ParsePointID(s string) (*PointId, error) {
+	if num, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return NewIDNum(num), nil
+	}
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid point ID %q: expected an unsigned integer or a UUID", s)
+	}
+	return NewIDUUID(id.String()), nil
+}
+
+// PointIDFunc derives the ID of a point, e.g. from its payload.
+type PointIDFunc func(point *PointStruct) (*PointId, error)
+
+// KeyPointIDs returns a PointIDFunc deriving the ID of a point with NewIDFromKey,
+// from the external key returned by key.
+func KeyPointIDs(namespace uuid.UUID, key func(point *PointStruct) (string, error)) PointIDFunc {
+	return func(point *PointStruct) (*PointId, error) {
+		k, err := key(point)
+		if err != nil {
+			return nil, err
+		}
+		if k == "" {
+			return nil, errors.New("empty point key")
+		}
+		return NewIDFromKey(namespace, k), nil
+	}
+}
+
+// ContentPointIDs returns a PointIDFunc deriving the ID of a point with NewIDFromContent,
+// from its payload and vectors.
+func ContentPointIDs(namespace uuid.UUID) PointIDFunc {
+	return func(point *PointStruct) (*PointId, error) {
+		return NewIDFromContent(namespace, point.GetPayload(), point.GetVectors())
+	}
+}
+
+// AssignPointIDs sets the ID of the points without one, with the ID derived by id,
+// before they are sent with Upsert. Points that already have an ID are left as is.
+//
+// Returns an error with the index of the first point whose ID cannot be derived.
+func AssignPointIDs(points []*PointStruct, id PointIDFunc) error {
+	for i, point := range points {
+		if point.GetId().GetPointIdOptions() != nil {
+			continue
+		}
+		pointID, err := id(point)
+		if err != nil {
+			return fmt.Errorf("failed to derive the ID of point %d: %w", i, err)
+		}
+		point.Id = pointID
+	}
+	return nil
+}
@@ -17,6 +17,13 @@ import (
 //   - *UpdateResult: The result of the upsert operation.
 //   - error: An error if the operation fails.
 func (c *Client) Upsert(ctx context.Context, request *UpsertPoints) (*UpdateResult, error) {
+	if err := c.validateUpsert(ctx, request); err != nil {
+		return nil, newQdrantErr(err, "Upsert", request.GetCollectionName())
+	}
+	request, err := normalizeRequest(ctx, c, request.GetCollectionName(), request, normalizeUpsert)
+	if err != nil {
+		return nil, newQdrantErr(err, "Upsert", request.GetCollectionName())
+	}
 	resp, err := c.GetPointsClient().Upsert(ctx, request)
 	if err != nil {
 		return nil, newQdrantErr(err, "Upsert", request.GetCollectionName())
@@ -103,6 +110,13 @@ func (c *Client) ScrollAndOffset(ctx context.Context, request *ScrollPoints) ([]
 //   - *UpdateResult: The result of the update operation.
 //   - error: An error if the operation fails.
 func (c *Client) UpdateVectors(ctx context.Context, request *UpdatePointVectors) (*UpdateResult, error) {
+	if err := c.validateUpdateVectors(ctx, request); err != nil {
+		return nil, newQdrantErr(err, "UpdateVectors", request.GetCollectionName())
+	}
+	request, err := normalizeRequest(ctx, c, request.GetCollectionName(), request, normalizeUpdateVectors)
+	if err != nil {
+		return nil, newQdrantErr(err, "UpdateVectors", request.GetCollectionName())
+	}
 	resp, err := c.GetPointsClient().UpdateVectors(ctx, request)
 	if err != nil {
 		return nil, newQdrantErr(err, "UpdateVectors", request.GetCollectionName())
@@ -292,6 +306,13 @@ func (c *Client) Count(ctx context.Context, request *CountPoints) (uint64, error
 //   - []*UpdateResult: A slice of results for each update operation.
 //   - error: An error if the operation fails.
 func (c *Client) UpdateBatch(ctx context.Context, request *UpdateBatchPoints) ([]*UpdateResult, error) {
+	if err := c.validateUpdateBatch(ctx, request); err != nil {
+		return nil, newQdrantErr(err, "UpdateBatch", request.GetCollectionName())
+	}
+	request, err := normalizeRequest(ctx, c, request.GetCollectionName(), request, normalizeUpdateBatch)
+	if err != nil {
+		return nil, newQdrantErr(err, "UpdateBatch", request.GetCollectionName())
+	}
 	resp, err := c.GetPointsClient().UpdateBatch(ctx, request)
 	if err != nil {
 		return nil, newQdrantErr(err, "UpdateBatch", request.GetCollectionName())
@@ -311,6 +332,10 @@ func (c *Client) UpdateBatch(ctx context.Context, request *UpdateBatchPoints) ([
 //   - []*ScoredPoint: A slice of scored points matching the query.
 //   - error: An error if the operation fails.
 func (c *Client) Query(ctx context.Context, request *QueryPoints) ([]*ScoredPoint, error) {
+	request, err := normalizeRequest(ctx, c, request.GetCollectionName(), request, normalizeQuery)
+	if err != nil {
+		return nil, newQdrantErr(err, "Query", request.GetCollectionName())
+	}
 	resp, err := c.GetPointsClient().Query(ctx, request)
 	if err != nil {
 		return nil, newQdrantErr(err, "Query", request.GetCollectionName())
@@ -330,6 +355,10 @@ func (c *Client) Query(ctx context.Context, request *QueryPoints) ([]*ScoredPoin
 //   - []*BatchResult: A slice of batch results for each query.
 //   - error: An error if the operation fails.
 func (c *Client) QueryBatch(ctx context.Context, request *QueryBatchPoints) ([]*BatchResult, error) {
+	request, err := normalizeRequest(ctx, c, request.GetCollectionName(), request, normalizeQueryBatch)
+	if err != nil {
+		return nil, newQdrantErr(err, "QueryBatch", request.GetCollectionName())
+	}
 	resp, err := c.GetPointsClient().QueryBatch(ctx, request)
 	if err != nil {
 		return nil, newQdrantErr(err, "QueryBatch", request.GetCollectionName())
@@ -349,6 +378,10 @@ func (c *Client) QueryBatch(ctx context.Context, request *QueryBatchPoints) ([]*
 //   - []*PointGroup: A slice of point groups matching the query.
 //   - error: An error if the operation fails.
 func (c *Client) QueryGroups(ctx context.Context, request *QueryPointGroups) ([]*PointGroup, error) {
+	request, err := normalizeRequest(ctx, c, request.GetCollectionName(), request, normalizeQueryGroups)
+	if err != nil {
+		return nil, newQdrantErr(err, "QueryGroups", request.GetCollectionName())
+	}
 	resp, err := c.GetPointsClient().QueryGroups(ctx, request)
 	if err != nil {
 		return nil, newQdrantErr(err, "QueryGroups", request.GetCollectionName())
@@ -411,6 +444,8 @@ type ScrollIterator struct {
 	ctx     context.Context
 	request *ScrollPoints
 	done    bool
+	// Whether the iterator is registered as in-flight work of the client.
+	tracked bool
 }
 
 // ScrollAll returns a ScrollIterator that automatically paginates through
@@ -435,12 +470,20 @@ func (it *ScrollIterator) Next() ([]*RetrievedPoint, error) {
 	if it.done {
 		return nil, io.EOF
 	}
-	points, nextOffset, err := it.client.ScrollAndOffset(it.ctx, it.request)
+	if !it.tracked {
+		if !it.client.tracker.acquire() {
+			return nil, newQdrantErr(ErrClientClosed, "ScrollAll", it.request.GetCollectionName())
+		}
+		it.tracked = true
+	}
+	points, nextOffset, err := it.client.ScrollAndOffset(withTracked(it.ctx), it.request)
 	if err != nil {
+		it.untrack()
 		return nil, err
 	}
 	if nextOffset == nil {
 		it.done = true
+		it.untrack()
 		if len(points) == 0 {
 			return nil, io.EOF
 		}
@@ -450,6 +493,22 @@ func (it *ScrollIterator) Next() ([]*RetrievedPoint, error) {
 	return points, nil
 }
 
+// Close stops the iteration. Subsequent calls to Next return io.EOF.
+// It only needs to be called when an iterator is abandoned before reaching io.EOF,
+// so that Client.Shutdown does not wait for it.
+func (it *ScrollIterator) Close() {
+	it.done = true
+	it.untrack()
+}
+
+// Internal method.
+func (it *ScrollIterator) untrack() {
+	if it.tracked {
+		it.tracked = false
+		it.client.tracker.release()
+	}
+}
+
 // GetDense returns the DenseVector from the VectorOutput.
 // Returns nil if no dense vector data is available.
 func (v *VectorOutput) GetDenseVector() *DenseVector {
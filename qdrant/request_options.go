@@ -0,0 +1,242 @@
+package qdrant
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Names of the optional request fields that can be set with a RequestOption.
+const (
+	readConsistencyField  = "read_consistency"
+	shardKeySelectorField = "shard_key_selector"
+	waitField             = "wait"
+	orderingField         = "ordering"
+	timeoutField          = "timeout"
+)
+
+// RequestOption sets an optional field that is shared by many request types,
+// such as read consistency, shard key selectors, wait, write ordering or timeout.
+//
+// Options are applied to every request that has the corresponding field and ignored by the others.
+// A field explicitly set on the request always takes precedence over an option.
+// Options nested inside batch requests (e.g. the individual queries of QueryBatchPoints) are not modified.
+//
+// Options can be set per request with WithRequestOptions or for every request with Config.RequestOptions.
+//
+// If no timeout is set, it is derived from the context deadline. See Config.DisableDeadlinePropagation.
+type RequestOption func(*requestOptions)
+
+// Internal type holding the values set by RequestOptions.
+// A nil field means the option is not set.
+type requestOptions struct {
+	readConsistency  *ReadConsistency
+	shardKeySelector *ShardKeySelector
+	wait             *bool
+	ordering         *WriteOrdering
+	timeout          *uint64
+	// Whether the timeout must not be derived from the context deadline.
+	ignoreDeadline bool
+}
+
+type requestOptionsContextKey struct{}
+
+// WithReadConsistency sets the read consistency of read requests, e.g. Query, Scroll, Count or Get.
+//
+//	ctx = qdrant.WithRequestOptions(ctx, qdrant.WithReadConsistency(qdrant.ReadConsistencyType_Majority))
+func WithReadConsistency(readConsistencyType ReadConsistencyType) RequestOption {
+	return WithReadConsistencySelector(NewReadConsistencyType(readConsistencyType))
+}
+
+// WithReadConsistencySelector sets the read consistency of read requests
+// from a *ReadConsistency, e.g. created with NewReadConsistencyFactor().
+func WithReadConsistencySelector(readConsistency *ReadConsistency) RequestOption {
+	return func(o *requestOptions) {
+		o.readConsistency = readConsistency
+	}
+}
+
+// WithShardKeys sets the shard keys that requests are routed to.
+// See: https://qdrant.tech/documentation/guides/distributed_deployment/#user-defined-sharding
+func WithShardKeys(keys ...*ShardKey) RequestOption {
+	return WithShardKeySelector(&ShardKeySelector{
+		ShardKeys: keys,
+	})
+}
+
+// WithShardKeySelector sets the shard key selector that requests are routed to.
+func WithShardKeySelector(selector *ShardKeySelector) RequestOption {
+	return func(o *requestOptions) {
+		o.shardKeySelector = selector
+	}
+}
+
+// WithWait sets whether write requests wait for the changes to be applied.
+func WithWait(wait bool) RequestOption {
+	return func(o *requestOptions) {
+		o.wait = &wait
+	}
+}
+
+// WithOrdering sets the write ordering guarantees of write requests.
+// See: https://qdrant.tech/documentation/concepts/points/#write-ordering
+func WithOrdering(orderingType WriteOrderingType) RequestOption {
+	return func(o *requestOptions) {
+		o.ordering = &WriteOrdering{Type: orderingType}
+	}
+}
+
+// WithServerTimeout sets the timeout the server applies to requests.
+// The duration is rounded up to whole seconds.
+// Takes precedence over the timeout derived from the context deadline.
+func WithServerTimeout(timeout time.Duration) RequestOption {
+	seconds := uint64(math.Ceil(timeout.Seconds()))
+	return func(o *requestOptions) {
+		o.timeout = &seconds
+	}
+}
+
+// WithoutDeadlinePropagation disables deriving the server-side timeout from the context deadline.
+// See Config.DisableDeadlinePropagation.
+func WithoutDeadlinePropagation() RequestOption {
+	return func(o *requestOptions) {
+		o.ignoreDeadline = true
+	}
+}
+
+// WithRequestOptions returns a new context that applies the options to every request
+// made using that context. Options added this way take precedence over Config.RequestOptions
+// and over options previously added to the context.
+//
+//	ctx = qdrant.WithRequestOptions(ctx, qdrant.WithWait(true), qdrant.WithOrdering(qdrant.WriteOrderingType_Strong))
+//	client.Upsert(ctx, ...)
+func WithRequestOptions(ctx context.Context, opts ...RequestOption) context.Context {
+	if len(opts) == 0 {
+		return ctx
+	}
+	existing, _ := ctx.Value(requestOptionsContextKey{}).([]RequestOption)
+	combined := make([]RequestOption, 0, len(existing)+len(opts))
+	combined = append(combined, existing...)
+	combined = append(combined, opts...)
+	return context.WithValue(ctx, requestOptionsContextKey{}, combined)
+}
+
+// Internal method.
+// Builds the options from the config defaults and the ones stored in ctx.
+// If no timeout is set, it is derived from the deadline of ctx, unless disabled.
+func (c *Config) newRequestOptions(ctx context.Context) *requestOptions {
+	options := &requestOptions{}
+	for _, opt := range c.RequestOptions {
+		opt(options)
+	}
+	fromContext, _ := ctx.Value(requestOptionsContextKey{}).([]RequestOption)
+	for _, opt := range fromContext {
+		opt(options)
+	}
+	if options.timeout == nil && !options.ignoreDeadline && !c.DisableDeadlinePropagation {
+		if deadline, ok := ctx.Deadline(); ok {
+			options.timeout = deadlineTimeout(time.Until(deadline), c.getDeadlineSafetyMargin())
+		}
+	}
+	return options
+}
+
+// Internal method.
+// Converts the time remaining until a deadline into a server-side timeout in seconds.
+// The margin is subtracted and the result rounded down, so that the server gives up before the client does.
+// The timeout is at least 1 second, since 0 is not a valid timeout.
+func deadlineTimeout(remaining, margin time.Duration) *uint64 {
+	seconds := uint64(max(int64((remaining-margin)/time.Second), 1))
+	return &seconds
+}
+
+// Internal method.
+// Returns a shallow copy of msg with the unset fields that have a corresponding option set,
+// so that the caller's request is not modified. Returns nil if no field needs to be set.
+func (o *requestOptions) apply(msg protoreflect.Message) protoreflect.Message {
+	var clone protoreflect.Message
+	for _, v := range o.fieldValues() {
+		fd := msg.Descriptor().Fields().ByName(v.name)
+		if fd == nil || fd.Kind() != v.kind || msg.Has(fd) {
+			continue
+		}
+		if clone == nil {
+			clone = shallowClone(msg)
+		}
+		clone.Set(fd, v.value)
+	}
+	return clone
+}
+
+type fieldValue struct {
+	name  protoreflect.Name
+	kind  protoreflect.Kind
+	value protoreflect.Value
+}
+
+// Internal method.
+func (o *requestOptions) fieldValues() []fieldValue {
+	var values []fieldValue
+	if o.readConsistency != nil {
+		values = append(values, fieldValue{
+			readConsistencyField, protoreflect.MessageKind, protoreflect.ValueOfMessage(o.readConsistency.ProtoReflect()),
+		})
+	}
+	if o.shardKeySelector != nil {
+		values = append(values, fieldValue{
+			shardKeySelectorField, protoreflect.MessageKind, protoreflect.ValueOfMessage(o.shardKeySelector.ProtoReflect()),
+		})
+	}
+	if o.wait != nil {
+		values = append(values, fieldValue{
+			waitField, protoreflect.BoolKind, protoreflect.ValueOfBool(*o.wait),
+		})
+	}
+	if o.ordering != nil {
+		values = append(values, fieldValue{
+			orderingField, protoreflect.MessageKind, protoreflect.ValueOfMessage(o.ordering.ProtoReflect()),
+		})
+	}
+	if o.timeout != nil {
+		values = append(values, fieldValue{
+			timeoutField, protoreflect.Uint64Kind, protoreflect.ValueOfUint64(*o.timeout),
+		})
+	}
+	return values
+}
+
+// Internal method.
+// Returns a shallow copy of msg. Nested messages and lists are shared with msg.
+func shallowClone(msg protoreflect.Message) protoreflect.Message {
+	clone := msg.New()
+	msg.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		clone.Set(fd, v)
+		return true
+	})
+	clone.SetUnknown(msg.GetUnknown())
+	return clone
+}
+
+// Internal method.
+func (c *Config) getRequestOptionsInterceptor() grpc.DialOption {
+	return grpc.WithChainUnaryInterceptor(func(
+		ctx context.Context,
+		method string,
+		req,
+		reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption) error {
+		if msg, ok := req.(proto.Message); ok {
+			options := c.newRequestOptions(ctx)
+			if modified := options.apply(msg.ProtoReflect()); modified != nil {
+				req = modified.Interface()
+			}
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	})
+}
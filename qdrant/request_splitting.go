@@ -0,0 +1,153 @@
+package qdrant
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// Internal method.
+// Splits the requests larger than Config.MaxRequestSize. It runs after the request options and embedding
+// interceptors, so that the requests are measured as sent, and before the logging, rate limiting and retry
+// interceptors, which see each part as a request of its own.
+func (c *Config) getRequestSplittingInterceptor() grpc.DialOption {
+	return grpc.WithChainUnaryInterceptor(func(
+		ctx context.Context,
+		method string,
+		req,
+		reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption) error {
+		if c.MaxRequestSize <= 0 {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		invoke := func(ctx context.Context, req, reply proto.Message) error {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		switch request := req.(type) {
+		case *UpsertPoints:
+			if resp, ok := reply.(*PointsOperationResponse); ok {
+				return sendSplit(ctx, c.MaxRequestSize, request, request.GetPoints(), setUpsertPoints,
+					resp, keepLastResponse, invoke)
+			}
+		case *UpdatePointVectors:
+			if resp, ok := reply.(*PointsOperationResponse); ok {
+				return sendSplit(ctx, c.MaxRequestSize, request, request.GetPoints(), setUpdateVectorsPoints,
+					resp, keepLastResponse, invoke)
+			}
+		case *SetPayloadPoints:
+			if resp, ok := reply.(*PointsOperationResponse); ok {
+				ids := request.GetPointsSelector().GetPoints().GetIds()
+				return sendSplit(ctx, c.MaxRequestSize, request, ids, setSetPayloadIDs, resp, keepLastResponse, invoke)
+			}
+		case *GetPoints:
+			if resp, ok := reply.(*GetResponse); ok {
+				return sendSplit(ctx, c.MaxRequestSize, request, request.GetIds(), setGetIDs, resp, mergeGetResponses,
+					invoke)
+			}
+		case *QueryBatchPoints:
+			if resp, ok := reply.(*QueryBatchResponse); ok {
+				return sendSplit(ctx, c.MaxRequestSize, request, request.GetQueryPoints(), setQueryBatchQueries,
+					resp, mergeQueryBatchResponses, invoke)
+			}
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	})
+}
+
+// Internal method.
+// Sends request with invoke into reply. If request is larger than maxBytes, items, a repeated field of request
+// set by setItems, is split so that every request fits, and the requests are sent one after the other.
+// The response of each request is merged into reply with merge, in the order of the items.
+func sendSplit[R proto.Message, T proto.Message, Res proto.Message](ctx context.Context, maxBytes int, request R,
+	items []T, setItems func(R, []T), reply Res, merge func(reply, part Res),
+	invoke func(ctx context.Context, req, reply proto.Message) error,
+) error {
+	if len(items) < 2 || proto.Size(request) <= maxBytes {
+		return invoke(ctx, request, reply)
+	}
+	empty := cloneWithItems(request, nil, setItems)
+	for _, chunk := range splitBySize(items, proto.Size(empty)+nestingOverhead, maxBytes) {
+		part, _ := reply.ProtoReflect().New().Interface().(Res)
+		if err := invoke(ctx, cloneWithItems(request, chunk, setItems), part); err != nil {
+			return err
+		}
+		merge(reply, part)
+	}
+	return nil
+}
+
+// Internal method.
+// Returns a shallow copy of request with items set by setItems.
+func cloneWithItems[R proto.Message, T any](request R, items []T, setItems func(R, []T)) R {
+	clone, _ := shallowClone(request.ProtoReflect()).Interface().(R)
+	setItems(clone, items)
+	return clone
+}
+
+// Internal method.
+// Returns items split into consecutive chunks whose size, added to emptySize, does not exceed maxBytes.
+// An item too large to fit with emptySize is in a chunk of its own.
+func splitBySize[T proto.Message](items []T, emptySize, maxBytes int) [][]T {
+	var chunks [][]T
+	start, size := 0, emptySize
+	for i, item := range items {
+		// Repeated fields of the requests have field numbers below 16, which take a one-byte tag.
+		itemSize := protowire.SizeTag(1) + protowire.SizeBytes(proto.Size(item))
+		if i > start && size+itemSize > maxBytes {
+			chunks = append(chunks, items[start:i])
+			start, size = i, emptySize
+		}
+		size += itemSize
+	}
+	return append(chunks, items[start:])
+}
+
+// Internal method.
+// The result of a split update is the result of its last part.
+func keepLastResponse(reply, part *PointsOperationResponse) {
+	time := reply.GetTime() + part.GetTime()
+	proto.Reset(reply)
+	proto.Merge(reply, part)
+	reply.Time = time
+}
+
+// Internal method.
+func mergeGetResponses(reply, part *GetResponse) {
+	reply.Result = append(reply.Result, part.GetResult()...)
+	reply.Time += part.GetTime()
+}
+
+// Internal method.
+func mergeQueryBatchResponses(reply, part *QueryBatchResponse) {
+	reply.Result = append(reply.Result, part.GetResult()...)
+	reply.Time += part.GetTime()
+}
+
+// Internal method.
+func setUpsertPoints(request *UpsertPoints, points []*PointStruct) {
+	request.Points = points
+}
+
+// Internal method.
+func setUpdateVectorsPoints(request *UpdatePointVectors, points []*PointVectors) {
+	request.Points = points
+}
+
+// Internal method.
+func setSetPayloadIDs(request *SetPayloadPoints, ids []*PointId) {
+	request.PointsSelector = NewPointsSelectorIDs(ids)
+}
+
+// Internal method.
+func setGetIDs(request *GetPoints, ids []*PointId) {
+	request.Ids = ids
+}
+
+// Internal method.
+func setQueryBatchQueries(request *QueryBatchPoints, queries []*QueryPoints) {
+	request.QueryPoints = queries
+}
@@ -0,0 +1,1483 @@
+// This file contains converters between the JSON bodies of the Qdrant REST API and the gRPC request types,
+// to replay REST requests through the gRPC client and to share fixtures between both APIs.
+// https://api.qdrant.tech/api-reference
+//
+// USAGE:
+//
+//	filter, err := qdrant.FilterFromJSON([]byte(`{"must": [{"key": "city", "match": {"value": "London"}}]}`))
+//	request, err := qdrant.QueryPointsFromJSON("my_collection", body)
+//	points, err := client.Query(ctx, request)
+
+package qdrant
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// FilterFromJSON converts a filter in the JSON format of the REST API to a *Filter.
+//
+//	filter, err := qdrant.FilterFromJSON([]byte(`{"must": [{"key": "city", "match": {"value": "London"}}]}`))
+func FilterFromJSON(data []byte) (*Filter, error) {
+	filter := &Filter{}
+	if err := unmarshalREST(data, filter); err != nil {
+		return nil, newQdrantErr(err, "FilterFromJSON")
+	}
+	return filter, nil
+}
+
+// FilterToJSON converts a *Filter to the JSON format of the REST API.
+func FilterToJSON(filter *Filter) ([]byte, error) {
+	data, err := marshalREST(filter)
+	if err != nil {
+		return nil, newQdrantErr(err, "FilterToJSON")
+	}
+	return data, nil
+}
+
+// QueryPointsFromJSON converts the body of a REST query request
+// (POST /collections/{collection_name}/points/query) to a *QueryPoints on the given collection.
+//
+// The read consistency and timeout are URL parameters of the REST API
+// and can be set with WithRequestOptions.
+// Formula and relevance feedback queries are not supported.
+func QueryPointsFromJSON(collectionName string, data []byte) (*QueryPoints, error) {
+	request := &QueryPoints{}
+	if err := unmarshalREST(data, request); err != nil {
+		return nil, newQdrantErr(err, "QueryPointsFromJSON", collectionName)
+	}
+	request.CollectionName = collectionName
+	return request, nil
+}
+
+// QueryPointsToJSON converts a *QueryPoints to the body of a REST query request.
+// The collection name, read consistency and timeout are omitted, as they are part of the URL.
+func QueryPointsToJSON(request *QueryPoints) ([]byte, error) {
+	data, err := marshalREST(request)
+	if err != nil {
+		return nil, newQdrantErr(err, "QueryPointsToJSON", request.GetCollectionName())
+	}
+	return data, nil
+}
+
+// CreateCollectionFromJSON converts the body of a REST create collection request
+// (PUT /collections/{collection_name}) to a *CreateCollection for the given collection.
+func CreateCollectionFromJSON(collectionName string, data []byte) (*CreateCollection, error) {
+	request := &CreateCollection{}
+	if err := unmarshalREST(data, request); err != nil {
+		return nil, newQdrantErr(err, "CreateCollectionFromJSON", collectionName)
+	}
+	request.CollectionName = collectionName
+	return request, nil
+}
+
+// CreateCollectionToJSON converts a *CreateCollection to the body of a REST create collection request.
+// The collection name and timeout are omitted, as they are part of the URL.
+func CreateCollectionToJSON(request *CreateCollection) ([]byte, error) {
+	data, err := marshalREST(request)
+	if err != nil {
+		return nil, newQdrantErr(err, "CreateCollectionToJSON", request.GetCollectionName())
+	}
+	return data, nil
+}
+
+// Internal method.
+func unmarshalREST(data []byte, msg proto.Message) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return errors.New("invalid JSON: unexpected data after the top-level value")
+	}
+	return decodeRESTMessage(msg.ProtoReflect(), value, "")
+}
+
+// Internal method.
+func marshalREST(msg proto.Message) ([]byte, error) {
+	value, err := encodeRESTMessage(msg.ProtoReflect())
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+// Internal method.
+// Returns the proto field name of a REST field, for the fields named differently in both APIs.
+// Fields mapped to "" only exist in the URL of REST requests.
+func restFieldAliases(message protoreflect.FullName) map[string]protoreflect.Name {
+	switch message {
+	case "qdrant.QueryPoints":
+		return map[string]protoreflect.Name{
+			"with_vector":      "with_vectors",
+			"shard_key":        "shard_key_selector",
+			"collection_name":  "",
+			"read_consistency": "",
+			"timeout":          "",
+		}
+	case "qdrant.LookupLocation":
+		return map[string]protoreflect.Name{
+			"collection": "collection_name",
+			"vector":     "vector_name",
+			"shard_key":  "shard_key_selector",
+		}
+	case "qdrant.CreateCollection":
+		return map[string]protoreflect.Name{
+			"vectors":         "vectors_config",
+			"sparse_vectors":  "sparse_vectors_config",
+			"collection_name": "",
+			"timeout":         "",
+		}
+	case "qdrant.OptimizersConfigDiff":
+		return map[string]protoreflect.Name{
+			"deprecated_max_optimization_threads": "",
+		}
+	}
+	return nil
+}
+
+// Internal method.
+func restFieldByKey(desc protoreflect.MessageDescriptor, key string) protoreflect.FieldDescriptor {
+	name := protoreflect.Name(key)
+	if alias, ok := restFieldAliases(desc.FullName())[key]; ok {
+		name = alias
+	} else if slices.Contains(restAliasTargets(desc.FullName()), name) {
+		return nil
+	}
+	if name == "" {
+		return nil
+	}
+	return desc.Fields().ByName(name)
+}
+
+// Internal method.
+// Returns the REST key of a field, or "" if the field is not part of REST bodies.
+func restKeyByField(fd protoreflect.FieldDescriptor) string {
+	for key, name := range restFieldAliases(fd.ContainingMessage().FullName()) {
+		if name == fd.Name() {
+			return key
+		}
+	}
+	if _, skipped := restFieldAliases(fd.ContainingMessage().FullName())[string(fd.Name())]; skipped {
+		return ""
+	}
+	return string(fd.Name())
+}
+
+// Internal method.
+// Returns the REST fields that have no proto equivalent. They are accepted when null.
+func restOnlyFields(message protoreflect.FullName) []string {
+	if message == "qdrant.CreateCollection" {
+		return []string{"init_from"}
+	}
+	return nil
+}
+
+// Internal method.
+// Returns the proto field names that are only reachable through an alias.
+func restAliasTargets(message protoreflect.FullName) []protoreflect.Name {
+	var names []protoreflect.Name
+	for _, name := range restFieldAliases(message) {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+type restDecodeFunc func(msg protoreflect.Message, value any, path string) error
+
+type restEncodeFunc func(msg protoreflect.Message) (any, error)
+
+// Internal method.
+func restDecoder[T proto.Message](decode func(T, any, string) error) restDecodeFunc {
+	return func(msg protoreflect.Message, value any, path string) error {
+		typed, _ := msg.Interface().(T)
+		return decode(typed, value, path)
+	}
+}
+
+// Internal method.
+func restEncoder[T proto.Message](encode func(T) (any, error)) restEncodeFunc {
+	return func(msg protoreflect.Message) (any, error) {
+		typed, _ := msg.Interface().(T)
+		return encode(typed)
+	}
+}
+
+// Internal method.
+// Returns the conversions of the messages whose REST format differs from their fields.
+func restHooks(message protoreflect.FullName) (restDecodeFunc, restEncodeFunc) {
+	switch message {
+	case "qdrant.Condition":
+		return restDecoder(decodeRESTCondition), restEncoder(encodeRESTCondition)
+	case "qdrant.FieldCondition":
+		return restDecoder(decodeRESTFieldCondition), restEncoder(encodeRESTFieldCondition)
+	case "qdrant.Match":
+		return restDecoder(decodeRESTMatch), restEncoder(encodeRESTMatch)
+	case "qdrant.PointId":
+		return restDecoder(decodeRESTPointID), restEncoder(encodeRESTPointID)
+	case "qdrant.ShardKey":
+		return restDecoder(decodeRESTShardKey), restEncoder(encodeRESTShardKey)
+	case "qdrant.ShardKeySelector":
+		return restDecoder(decodeRESTShardKeySelector), restEncoder(encodeRESTShardKeySelector)
+	case "qdrant.Value":
+		return restDecoder(decodeRESTValue), restEncoder(encodeRESTValue)
+	case "google.protobuf.Timestamp":
+		return restDecoder(decodeRESTTimestamp), restEncoder(encodeRESTTimestamp)
+	case "qdrant.Query":
+		return restDecoder(decodeRESTQuery), restEncoder(encodeRESTQuery)
+	case "qdrant.VectorInput":
+		return restDecoder(decodeRESTVectorInput), restEncoder(encodeRESTVectorInput)
+	case "qdrant.ContextInput":
+		return restDecoder(decodeRESTContextInput), restEncoder(encodeRESTContextInput)
+	case "qdrant.OrderBy":
+		return restDecoder(decodeRESTOrderBy), encodeRESTFields
+	case "qdrant.StartFrom":
+		return restDecoder(decodeRESTStartFrom), restEncoder(encodeRESTStartFrom)
+	case "qdrant.WithPayloadSelector":
+		return restDecoder(decodeRESTWithPayload), restEncoder(encodeRESTWithPayload)
+	case "qdrant.WithVectorsSelector":
+		return restDecoder(decodeRESTWithVectors), restEncoder(encodeRESTWithVectors)
+	case "qdrant.VectorsConfig":
+		return restDecoder(decodeRESTVectorsConfig), restEncoder(encodeRESTVectorsConfig)
+	case "qdrant.MaxOptimizationThreads":
+		return restDecoder(decodeRESTMaxOptimizationThreads), restEncoder(encodeRESTMaxOptimizationThreads)
+	case "qdrant.BinaryQuantizationQueryEncoding":
+		return restDecoder(decodeRESTQueryEncoding), restEncoder(encodeRESTQueryEncoding)
+	case "qdrant.VectorParamsMap", "qdrant.SparseVectorConfig",
+		"qdrant.StrictModeMultivectorConfig", "qdrant.StrictModeSparseConfig":
+		// Messages wrapping a single map, which is inlined in REST.
+		return decodeRESTMapWrapper, encodeRESTMapWrapper
+	}
+	return nil, nil
+}
+
+// Internal method.
+func decodeRESTMessage(msg protoreflect.Message, value any, path string) error {
+	if decode, _ := restHooks(msg.Descriptor().FullName()); decode != nil {
+		return decode(msg, value, path)
+	}
+	return decodeRESTFields(msg, value, path)
+}
+
+// Internal method.
+// Decodes a JSON object whose keys are the field names of msg.
+func decodeRESTFields(msg protoreflect.Message, value any, path string) error {
+	obj, err := restObject(value, path)
+	if err != nil {
+		return err
+	}
+	for _, key := range sortedKeys(obj) {
+		fd := restFieldByKey(msg.Descriptor(), key)
+		if fd == nil && slices.Contains(restOnlyFields(msg.Descriptor().FullName()), key) {
+			if obj[key] == nil {
+				continue
+			}
+			return fmt.Errorf("%s: not supported by the gRPC API", restPath(path, key))
+		}
+		if fd == nil {
+			return fmt.Errorf("%s: unknown field", restPath(path, key))
+		}
+		if obj[key] == nil {
+			continue
+		}
+		if err := decodeRESTField(msg, fd, obj[key], restPath(path, key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Internal method.
+func decodeRESTField(msg protoreflect.Message, fd protoreflect.FieldDescriptor, value any, path string) error {
+	switch {
+	case fd.IsList():
+		items, ok := value.([]any)
+		if !ok {
+			if _, isObject := value.(map[string]any); !isObject || fd.Message() == nil {
+				return restTypeError(path, "an array", value)
+			}
+			// A single object is accepted where a list of objects is expected, e.g. for "must" or "prefetch".
+			items = []any{value}
+		}
+		list := msg.Mutable(fd).List()
+		for i, item := range items {
+			element, err := decodeRESTElement(list.NewElement(), fd, item, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return err
+			}
+			list.Append(element)
+		}
+	case fd.IsMap():
+		obj, err := restObject(value, path)
+		if err != nil {
+			return err
+		}
+		entries := msg.Mutable(fd).Map()
+		for _, key := range sortedKeys(obj) {
+			element, err := decodeRESTElement(entries.NewValue(), fd.MapValue(), obj[key], restPath(path, key))
+			if err != nil {
+				return err
+			}
+			entries.Set(protoreflect.ValueOfString(key).MapKey(), element)
+		}
+	case fd.Message() != nil:
+		return decodeRESTMessage(msg.Mutable(fd).Message(), value, path)
+	default:
+		scalar, err := decodeRESTScalar(fd, value, path)
+		if err != nil {
+			return err
+		}
+		msg.Set(fd, scalar)
+	}
+	return nil
+}
+
+// Internal method.
+// Decodes a list element or map value. Messages are decoded into element.
+func decodeRESTElement(
+	element protoreflect.Value,
+	fd protoreflect.FieldDescriptor,
+	value any,
+	path string,
+) (protoreflect.Value, error) {
+	if fd.Message() != nil {
+		return element, decodeRESTMessage(element.Message(), value, path)
+	}
+	return decodeRESTScalar(fd, value, path)
+}
+
+// Internal method.
+func decodeRESTScalar(fd protoreflect.FieldDescriptor, value any, path string) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		if b, ok := value.(bool); ok {
+			return protoreflect.ValueOfBool(b), nil
+		}
+		return protoreflect.Value{}, restTypeError(path, "a boolean", value)
+	case protoreflect.StringKind:
+		if s, ok := value.(string); ok {
+			return protoreflect.ValueOfString(s), nil
+		}
+		return protoreflect.Value{}, restTypeError(path, "a string", value)
+	case protoreflect.EnumKind:
+		return decodeRESTEnum(fd.Enum(), value, path)
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		f, err := restFloat(value, path)
+		if fd.Kind() == protoreflect.FloatKind {
+			return protoreflect.ValueOfFloat32(float32(f)), err
+		}
+		return protoreflect.ValueOfFloat64(f), err
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		i, err := restInt(value, 32, path)
+		return protoreflect.ValueOfInt32(int32(i)), err
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		i, err := restInt(value, 64, path)
+		return protoreflect.ValueOfInt64(i), err
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		u, err := restUint(value, 32, path)
+		return protoreflect.ValueOfUint32(uint32(u)), err
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		u, err := restUint(value, 64, path)
+		return protoreflect.ValueOfUint64(u), err
+	case protoreflect.BytesKind, protoreflect.MessageKind, protoreflect.GroupKind:
+	}
+	return protoreflect.Value{}, fmt.Errorf("%s: unsupported field type %s", path, fd.Kind())
+}
+
+// Internal method.
+// Matches enum values ignoring case and underscores, e.g. "max_sim" matches MaxSim.
+func decodeRESTEnum(enum protoreflect.EnumDescriptor, value any, path string) (protoreflect.Value, error) {
+	name, ok := value.(string)
+	if !ok {
+		return protoreflect.Value{}, restTypeError(path, "a string", value)
+	}
+	normalize := func(s string) string {
+		return strings.ToLower(strings.ReplaceAll(s, "_", ""))
+	}
+	values := enum.Values()
+	for i := range values.Len() {
+		if normalize(string(values.Get(i).Name())) == normalize(name) {
+			return protoreflect.ValueOfEnum(values.Get(i).Number()), nil
+		}
+	}
+	return protoreflect.Value{}, fmt.Errorf("%s: unknown %s %q", path, enum.Name(), name)
+}
+
+// Internal method.
+func encodeRESTMessage(msg protoreflect.Message) (any, error) {
+	if _, encode := restHooks(msg.Descriptor().FullName()); encode != nil {
+		return encode(msg)
+	}
+	return encodeRESTFields(msg)
+}
+
+// Internal method.
+// Encodes the populated fields of msg as a JSON object.
+func encodeRESTFields(msg protoreflect.Message) (any, error) {
+	obj := make(map[string]any)
+	var err error
+	msg.Range(func(fd protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		key := restKeyByField(fd)
+		if key == "" {
+			return true
+		}
+		obj[key], err = encodeRESTField(fd, value)
+		if err != nil {
+			err = fmt.Errorf("%s: %w", key, err)
+		}
+		return err == nil
+	})
+	if err != nil {
+		return obj, err
+	}
+	encodeRESTZeroEnums(msg, obj)
+	return obj, nil
+}
+
+// Internal method.
+// Adds the enum fields without presence that are set to their zero value, which Range skips.
+// REST requires some of them, e.g. the comparator of a multivector config set to MaxSim.
+// Zero values named Unknown, which mean unset, are left out.
+func encodeRESTZeroEnums(msg protoreflect.Message, obj map[string]any) {
+	fields := msg.Descriptor().Fields()
+	for i := range fields.Len() {
+		fd := fields.Get(i)
+		if fd.Kind() != protoreflect.EnumKind || fd.HasPresence() || fd.IsList() || msg.Has(fd) {
+			continue
+		}
+		zero := fd.Enum().Values().ByNumber(0)
+		key := restKeyByField(fd)
+		if key == "" || zero == nil || strings.HasPrefix(string(zero.Name()), "Unknown") {
+			continue
+		}
+		obj[key] = restEnumName(fd.Enum(), 0)
+	}
+}
+
+// Internal method.
+func encodeRESTField(fd protoreflect.FieldDescriptor, value protoreflect.Value) (any, error) {
+	switch {
+	case fd.IsList():
+		list := value.List()
+		items := make([]any, list.Len())
+		for i := range list.Len() {
+			item, err := encodeRESTSingular(fd, list.Get(i))
+			if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return items, nil
+	case fd.IsMap():
+		obj := make(map[string]any)
+		var err error
+		value.Map().Range(func(key protoreflect.MapKey, entry protoreflect.Value) bool {
+			obj[key.String()], err = encodeRESTSingular(fd.MapValue(), entry)
+			return err == nil
+		})
+		return obj, err
+	}
+	return encodeRESTSingular(fd, value)
+}
+
+// Internal method.
+func encodeRESTSingular(fd protoreflect.FieldDescriptor, value protoreflect.Value) (any, error) {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return encodeRESTMessage(value.Message())
+	case protoreflect.EnumKind:
+		return restEnumName(fd.Enum(), value.Enum()), nil
+	case protoreflect.FloatKind:
+		return restNumber(value.Float(), 32), nil
+	case protoreflect.DoubleKind:
+		return restNumber(value.Float(), 64), nil
+	case protoreflect.BytesKind:
+		return nil, fmt.Errorf("unsupported field type %s", fd.Kind())
+	case protoreflect.BoolKind, protoreflect.StringKind,
+		protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+	}
+	return value.Interface(), nil
+}
+
+// Internal method.
+// Returns the REST name of an enum value: the proto name for distances, e.g. "Cosine",
+// and the snake case proto name otherwise, e.g. "max_sim" for MaxSim.
+func restEnumName(enum protoreflect.EnumDescriptor, number protoreflect.EnumNumber) any {
+	value := enum.Values().ByNumber(number)
+	if value == nil {
+		return int32(number)
+	}
+	name := string(value.Name())
+	if enum.FullName() == "qdrant.Distance" {
+		return name
+	}
+	var snake strings.Builder
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) && unicode.IsLower(rune(name[i-1])) {
+			snake.WriteByte('_')
+		}
+		snake.WriteRune(unicode.ToLower(r))
+	}
+	return snake.String()
+}
+
+// Internal method.
+// Encodes a float with the shortest representation that round-trips at the given precision,
+// so that e.g. float32(0.1) is encoded as 0.1.
+func restNumber(f float64, bitSize int) json.Number {
+	return json.Number(strconv.FormatFloat(f, 'g', -1, bitSize))
+}
+
+// Internal method.
+func restPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// Internal method.
+func restTypeError(path, expected string, value any) error {
+	if path == "" {
+		path = "body"
+	}
+	return fmt.Errorf("%s: expected %s, got %s", path, expected, restTypeName(value))
+}
+
+// Internal method.
+func restTypeName(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "a boolean"
+	case json.Number:
+		return "a number"
+	case string:
+		return "a string"
+	case []any:
+		return "an array"
+	case map[string]any:
+		return "an object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// Internal method.
+func restObject(value any, path string) (map[string]any, error) {
+	obj, ok := value.(map[string]any)
+	if !ok {
+		return nil, restTypeError(path, "an object", value)
+	}
+	return obj, nil
+}
+
+// Internal method.
+// Returns the only key of obj, which must have exactly one key.
+func restSingleKey(obj map[string]any, path string) (string, error) {
+	if len(obj) != 1 {
+		return "", fmt.Errorf("%s: expected an object with a single key, got %q", restOr(path), sortedKeys(obj))
+	}
+	for key := range obj {
+		return key, nil
+	}
+	return "", nil
+}
+
+// Internal method.
+func restOr(path string) string {
+	if path == "" {
+		return "body"
+	}
+	return path
+}
+
+// Internal method.
+func sortedKeys(obj map[string]any) []string {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// Internal method.
+func restFloat(value any, path string) (float64, error) {
+	number, ok := value.(json.Number)
+	if !ok {
+		return 0, restTypeError(path, "a number", value)
+	}
+	f, err := number.Float64()
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid number %s", path, number)
+	}
+	return f, nil
+}
+
+// Internal method.
+func restInt(value any, bitSize int, path string) (int64, error) {
+	number, ok := value.(json.Number)
+	if !ok {
+		return 0, restTypeError(path, "an integer", value)
+	}
+	i, err := strconv.ParseInt(number.String(), 10, bitSize)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid integer %s", path, number)
+	}
+	return i, nil
+}
+
+// Internal method.
+func restUint(value any, bitSize int, path string) (uint64, error) {
+	number, ok := value.(json.Number)
+	if !ok {
+		return 0, restTypeError(path, "a non-negative integer", value)
+	}
+	u, err := strconv.ParseUint(number.String(), 10, bitSize)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid non-negative integer %s", path, number)
+	}
+	return u, nil
+}
+
+// Internal method.
+func restFloats(value any, path string) ([]float32, error) {
+	items, ok := value.([]any)
+	if !ok {
+		return nil, restTypeError(path, "an array of numbers", value)
+	}
+	floats := make([]float32, len(items))
+	for i, item := range items {
+		f, err := restFloat(item, fmt.Sprintf("%s[%d]", path, i))
+		if err != nil {
+			return nil, err
+		}
+		floats[i] = float32(f)
+	}
+	return floats, nil
+}
+
+// Internal method.
+func encodeRESTFloats(floats []float32) []any {
+	items := make([]any, len(floats))
+	for i, f := range floats {
+		items[i] = restNumber(float64(f), 32)
+	}
+	return items
+}
+
+// Internal method.
+// A condition is a field condition if it has a "key", a filter if it has filter clauses,
+// and otherwise an object with a single key naming the condition, e.g. {"is_empty": {"key": "tags"}}.
+func decodeRESTCondition(condition *Condition, value any, path string) error {
+	obj, err := restObject(value, path)
+	if err != nil {
+		return err
+	}
+	if _, ok := obj["key"]; ok {
+		field := &FieldCondition{}
+		condition.ConditionOneOf = &Condition_Field{Field: field}
+		return decodeRESTMessage(field.ProtoReflect(), obj, path)
+	}
+	for key := range obj {
+		if restFieldByKey((&Filter{}).ProtoReflect().Descriptor(), key) != nil {
+			filter := &Filter{}
+			condition.ConditionOneOf = &Condition_Filter{Filter: filter}
+			return decodeRESTMessage(filter.ProtoReflect(), obj, path)
+		}
+	}
+	key, err := restSingleKey(obj, path)
+	if err != nil {
+		return err
+	}
+	switch key {
+	case "has_id":
+		hasID := &HasIdCondition{}
+		condition.ConditionOneOf = &Condition_HasId{HasId: hasID}
+		return decodeRESTFields(hasID.ProtoReflect(), obj, path)
+	case "has_vector":
+		name, ok := obj[key].(string)
+		if !ok {
+			return restTypeError(restPath(path, key), "a string", obj[key])
+		}
+		condition.ConditionOneOf = NewHasVector(name).GetConditionOneOf()
+		return nil
+	case "is_empty", "is_null", "nested":
+		fd := condition.ProtoReflect().Descriptor().Fields().ByName(protoreflect.Name(key))
+		return decodeRESTField(condition.ProtoReflect(), fd, obj[key], restPath(path, key))
+	}
+	return fmt.Errorf("%s: unknown condition", restPath(path, key))
+}
+
+// Internal method.
+func encodeRESTCondition(condition *Condition) (any, error) {
+	switch c := condition.GetConditionOneOf().(type) {
+	case *Condition_Field:
+		return encodeRESTMessage(c.Field.ProtoReflect())
+	case *Condition_Filter:
+		return encodeRESTMessage(c.Filter.ProtoReflect())
+	case *Condition_HasId:
+		return encodeRESTFields(c.HasId.ProtoReflect())
+	case *Condition_HasVector:
+		return map[string]any{"has_vector": c.HasVector.GetHasVector()}, nil
+	case nil:
+		return nil, errors.New("empty condition")
+	}
+	return encodeRESTFields(condition.ProtoReflect())
+}
+
+// Internal method.
+// A range on strings is a datetime range in the proto definitions.
+func decodeRESTFieldCondition(field *FieldCondition, value any, path string) error {
+	obj, err := restObject(value, path)
+	if err != nil {
+		return err
+	}
+	if bounds, ok := obj["range"].(map[string]any); ok {
+		for _, bound := range bounds {
+			if _, isString := bound.(string); isString {
+				obj = cloneRESTObject(obj)
+				obj["datetime_range"] = obj["range"]
+				delete(obj, "range")
+				break
+			}
+		}
+	}
+	return decodeRESTFields(field.ProtoReflect(), obj, path)
+}
+
+// Internal method.
+func encodeRESTFieldCondition(field *FieldCondition) (any, error) {
+	encoded, err := encodeRESTFields(field.ProtoReflect())
+	if err != nil {
+		return nil, err
+	}
+	obj, _ := encoded.(map[string]any)
+	if datetimeRange, ok := obj["datetime_range"]; ok {
+		if _, hasRange := obj["range"]; hasRange {
+			return nil, fmt.Errorf("field condition on %q has both a range and a datetime range", field.GetKey())
+		}
+		obj["range"] = datetimeRange
+		delete(obj, "datetime_range")
+	}
+	return obj, nil
+}
+
+// Internal method.
+func cloneRESTObject(obj map[string]any) map[string]any {
+	clone := make(map[string]any, len(obj))
+	for key, value := range obj {
+		clone[key] = value
+	}
+	return clone
+}
+
+// Internal method.
+func decodeRESTMatch(match *Match, value any, path string) error {
+	obj, err := restObject(value, path)
+	if err != nil {
+		return err
+	}
+	key, err := restSingleKey(obj, path)
+	if err != nil {
+		return err
+	}
+	path = restPath(path, key)
+	switch key {
+	case "value":
+		switch v := obj[key].(type) {
+		case string:
+			match.MatchValue = &Match_Keyword{Keyword: v}
+		case bool:
+			match.MatchValue = &Match_Boolean{Boolean: v}
+		default:
+			integer, err := restInt(v, 64, path)
+			if err != nil {
+				return err
+			}
+			match.MatchValue = &Match_Integer{Integer: integer}
+		}
+	case "text", "phrase", "text_any":
+		text, ok := obj[key].(string)
+		if !ok {
+			return restTypeError(path, "a string", obj[key])
+		}
+		switch key {
+		case "text":
+			match.MatchValue = &Match_Text{Text: text}
+		case "phrase":
+			match.MatchValue = &Match_Phrase{Phrase: text}
+		default:
+			match.MatchValue = &Match_TextAny{TextAny: text}
+		}
+	case "any", "except":
+		strs, ints, err := restKeywordsOrInts(obj[key], path)
+		if err != nil {
+			return err
+		}
+		switch {
+		case key == "any" && strs != nil:
+			match.MatchValue = &Match_Keywords{Keywords: &RepeatedStrings{Strings: strs}}
+		case key == "any":
+			match.MatchValue = &Match_Integers{Integers: &RepeatedIntegers{Integers: ints}}
+		case strs != nil:
+			match.MatchValue = &Match_ExceptKeywords{ExceptKeywords: &RepeatedStrings{Strings: strs}}
+		default:
+			match.MatchValue = &Match_ExceptIntegers{ExceptIntegers: &RepeatedIntegers{Integers: ints}}
+		}
+	default:
+		return fmt.Errorf("%s: unknown match", path)
+	}
+	return nil
+}
+
+// Internal method.
+// Returns the items of a list of strings or of integers.
+// An empty list is returned as an empty list of strings.
+func restKeywordsOrInts(value any, path string) ([]string, []int64, error) {
+	items, ok := value.([]any)
+	if !ok {
+		return nil, nil, restTypeError(path, "an array", value)
+	}
+	strs := []string{}
+	var ints []int64
+	for i, item := range items {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+		if s, isString := item.(string); isString && ints == nil {
+			strs = append(strs, s)
+			continue
+		}
+		if len(strs) > 0 {
+			return nil, nil, restTypeError(itemPath, "a string", item)
+		}
+		integer, err := restInt(item, 64, itemPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		ints = append(ints, integer)
+	}
+	if ints != nil {
+		return nil, ints, nil
+	}
+	return strs, nil, nil
+}
+
+// Internal method.
+func encodeRESTMatch(match *Match) (any, error) {
+	switch m := match.GetMatchValue().(type) {
+	case *Match_Keyword:
+		return map[string]any{"value": m.Keyword}, nil
+	case *Match_Integer:
+		return map[string]any{"value": m.Integer}, nil
+	case *Match_Boolean:
+		return map[string]any{"value": m.Boolean}, nil
+	case *Match_Text:
+		return map[string]any{"text": m.Text}, nil
+	case *Match_Phrase:
+		return map[string]any{"phrase": m.Phrase}, nil
+	case *Match_TextAny:
+		return map[string]any{"text_any": m.TextAny}, nil
+	case *Match_Keywords:
+		return map[string]any{"any": nonNilSlice(m.Keywords.GetStrings())}, nil
+	case *Match_Integers:
+		return map[string]any{"any": nonNilSlice(m.Integers.GetIntegers())}, nil
+	case *Match_ExceptKeywords:
+		return map[string]any{"except": nonNilSlice(m.ExceptKeywords.GetStrings())}, nil
+	case *Match_ExceptIntegers:
+		return map[string]any{"except": nonNilSlice(m.ExceptIntegers.GetIntegers())}, nil
+	}
+	return nil, errors.New("empty match")
+}
+
+// Internal method.
+// Returns an empty slice for nil, so that it is encoded as [] rather than null.
+func nonNilSlice[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
+// Internal method.
+func decodeRESTPointID(id *PointId, value any, path string) error {
+	if uuid, ok := value.(string); ok {
+		id.PointIdOptions = NewIDUUID(uuid).GetPointIdOptions()
+		return nil
+	}
+	num, err := restUint(value, 64, path)
+	if err != nil {
+		return restTypeError(path, "an unsigned integer or a UUID string", value)
+	}
+	id.PointIdOptions = NewIDNum(num).GetPointIdOptions()
+	return nil
+}
+
+// Internal method.
+func encodeRESTPointID(id *PointId) (any, error) {
+	switch id.GetPointIdOptions().(type) {
+	case *PointId_Num:
+		return id.GetNum(), nil
+	case *PointId_Uuid:
+		return id.GetUuid(), nil
+	}
+	return nil, errors.New("empty point ID")
+}
+
+// Internal method.
+func decodeRESTShardKey(key *ShardKey, value any, path string) error {
+	if keyword, ok := value.(string); ok {
+		key.Key = NewShardKeyKeyword(keyword).GetKey()
+		return nil
+	}
+	num, err := restUint(value, 64, path)
+	if err != nil {
+		return restTypeError(path, "an unsigned integer or a string", value)
+	}
+	key.Key = NewShardKeyNum(num).GetKey()
+	return nil
+}
+
+// Internal method.
+func encodeRESTShardKey(key *ShardKey) (any, error) {
+	switch key.GetKey().(type) {
+	case *ShardKey_Number:
+		return key.GetNumber(), nil
+	case *ShardKey_Keyword:
+		return key.GetKeyword(), nil
+	}
+	return nil, errors.New("empty shard key")
+}
+
+// Internal method.
+// A shard key selector is a single key, a list of keys or an object with "target" keys and a "fallback" key.
+func decodeRESTShardKeySelector(selector *ShardKeySelector, value any, path string) error {
+	target := value
+	if obj, ok := value.(map[string]any); ok {
+		for _, key := range sortedKeys(obj) {
+			switch key {
+			case "target":
+			case "fallback":
+				selector.Fallback = &ShardKey{}
+				if err := decodeRESTShardKey(selector.GetFallback(), obj[key], restPath(path, key)); err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("%s: unknown field", restPath(path, key))
+			}
+		}
+		target = obj["target"]
+		path = restPath(path, "target")
+	}
+	items, ok := target.([]any)
+	if !ok {
+		items = []any{target}
+	}
+	for i, item := range items {
+		key := &ShardKey{}
+		if err := decodeRESTShardKey(key, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+			return err
+		}
+		selector.ShardKeys = append(selector.ShardKeys, key)
+	}
+	return nil
+}
+
+// Internal method.
+func encodeRESTShardKeySelector(selector *ShardKeySelector) (any, error) {
+	keys := make([]any, len(selector.GetShardKeys()))
+	for i, key := range selector.GetShardKeys() {
+		encoded, err := encodeRESTShardKey(key)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = encoded
+	}
+	var target any = keys
+	if len(keys) == 1 {
+		target = keys[0]
+	}
+	if selector.GetFallback() == nil {
+		return target, nil
+	}
+	fallback, err := encodeRESTShardKey(selector.GetFallback())
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"target": target, "fallback": fallback}, nil
+}
+
+// Internal method.
+func decodeRESTValue(value *Value, jsonValue any, path string) error {
+	converted, err := NewValue(restNumbersToGo(jsonValue))
+	if err != nil {
+		return fmt.Errorf("%s: %w", restOr(path), err)
+	}
+	value.Kind = converted.GetKind()
+	return nil
+}
+
+// Internal method.
+// Converts the json.Numbers in value to int64 if they are integers and to float64 otherwise.
+func restNumbersToGo(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case []any:
+		converted := make([]any, len(v))
+		for i, item := range v {
+			converted[i] = restNumbersToGo(item)
+		}
+		return converted
+	case map[string]any:
+		converted := make(map[string]any, len(v))
+		for key, item := range v {
+			converted[key] = restNumbersToGo(item)
+		}
+		return converted
+	}
+	return value
+}
+
+// Internal method.
+func encodeRESTValue(value *Value) (any, error) {
+	switch kind := value.GetKind().(type) {
+	case *Value_NullValue, nil:
+		return nil, nil
+	case *Value_BoolValue:
+		return kind.BoolValue, nil
+	case *Value_IntegerValue:
+		return kind.IntegerValue, nil
+	case *Value_DoubleValue:
+		return restNumber(kind.DoubleValue, 64), nil
+	case *Value_StringValue:
+		return kind.StringValue, nil
+	case *Value_ListValue:
+		items := make([]any, len(kind.ListValue.GetValues()))
+		for i, item := range kind.ListValue.GetValues() {
+			encoded, err := encodeRESTValue(item)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = encoded
+		}
+		return items, nil
+	case *Value_StructValue:
+		obj := make(map[string]any, len(kind.StructValue.GetFields()))
+		for key, item := range kind.StructValue.GetFields() {
+			encoded, err := encodeRESTValue(item)
+			if err != nil {
+				return nil, err
+			}
+			obj[key] = encoded
+		}
+		return obj, nil
+	}
+	return nil, fmt.Errorf("unsupported value %T", value.GetKind())
+}
+
+// Internal method.
+func decodeRESTTimestamp(timestamp *timestamppb.Timestamp, value any, path string) error {
+	s, ok := value.(string)
+	if !ok {
+		return restTypeError(path, "a datetime string", value)
+	}
+	parsed, ok := parseDatetime(s)
+	if !ok {
+		return fmt.Errorf("%s: invalid datetime %q, expected RFC 3339 format", path, s)
+	}
+	timestamp.Seconds, timestamp.Nanos = parsed.GetSeconds(), parsed.GetNanos()
+	return nil
+}
+
+// Internal method.
+func encodeRESTTimestamp(timestamp *timestamppb.Timestamp) (any, error) {
+	return timestamp.AsTime().UTC().Format(time.RFC3339Nano), nil
+}
+
+// Internal method.
+// A query is either a vector input, which searches the nearest points,
+// or an object with a single key naming the query, e.g. {"fusion": "rrf"}.
+func decodeRESTQuery(query *Query, value any, path string) error {
+	obj, isObject := value.(map[string]any)
+	if !isObject || isRESTVectorInput(obj) {
+		nearest := &VectorInput{}
+		query.Variant = &Query_Nearest{Nearest: nearest}
+		return decodeRESTVectorInput(nearest, value, path)
+	}
+	if _, ok := obj["mmr"]; ok {
+		nearest := &NearestInputWithMmr{}
+		query.Variant = &Query_NearestWithMmr{NearestWithMmr: nearest}
+		return decodeRESTFields(nearest.ProtoReflect(), obj, path)
+	}
+	key, err := restSingleKey(obj, path)
+	if err != nil {
+		return err
+	}
+	fd := query.ProtoReflect().Descriptor().Oneofs().ByName("variant").Fields().ByName(protoreflect.Name(key))
+	switch {
+	case fd == nil || key == "nearest_with_mmr":
+		return fmt.Errorf("%s: unknown query", restPath(path, key))
+	case key == "formula" || key == "relevance_feedback":
+		return fmt.Errorf("%s: %s queries are not supported", restPath(path, key), key)
+	}
+	return decodeRESTField(query.ProtoReflect(), fd, obj[key], restPath(path, key))
+}
+
+// Internal method.
+func encodeRESTQuery(query *Query) (any, error) {
+	switch q := query.GetVariant().(type) {
+	case *Query_Nearest:
+		return encodeRESTVectorInput(q.Nearest)
+	case *Query_NearestWithMmr:
+		return encodeRESTFields(q.NearestWithMmr.ProtoReflect())
+	case *Query_Formula, *Query_RelevanceFeedback:
+		return nil, fmt.Errorf("%T queries are not supported", q)
+	case nil:
+		return nil, errors.New("empty query")
+	}
+	return encodeRESTFields(query.ProtoReflect())
+}
+
+// Internal method.
+// Reports whether obj is a sparse vector or an inference input, rather than a named query.
+func isRESTVectorInput(obj map[string]any) bool {
+	for _, key := range []string{"indices", "text", "image", "object"} {
+		if _, ok := obj[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Internal method.
+// A vector input is a dense vector, a multi-vector, a sparse vector, a point ID,
+// or a document, image or object to embed with inference.
+func decodeRESTVectorInput(input *VectorInput, value any, path string) error {
+	switch v := value.(type) {
+	case []any:
+		if len(v) > 0 {
+			if _, isMulti := v[0].([]any); isMulti {
+				vectors := make([][]float32, len(v))
+				for i, item := range v {
+					vector, err := restFloats(item, fmt.Sprintf("%s[%d]", path, i))
+					if err != nil {
+						return err
+					}
+					vectors[i] = vector
+				}
+				input.Variant = NewVectorInputMulti(vectors).GetVariant()
+				return nil
+			}
+		}
+		vector, err := restFloats(v, path)
+		if err != nil {
+			return err
+		}
+		input.Variant = NewVectorInputDense(vector).GetVariant()
+		return nil
+	case map[string]any:
+		switch {
+		case v["indices"] != nil:
+			sparse := &SparseVector{}
+			input.Variant = &VectorInput_Sparse{Sparse: sparse}
+			return decodeRESTFields(sparse.ProtoReflect(), v, path)
+		case v["text"] != nil:
+			document := &Document{}
+			input.Variant = &VectorInput_Document{Document: document}
+			return decodeRESTFields(document.ProtoReflect(), v, path)
+		case v["image"] != nil:
+			image := &Image{}
+			input.Variant = &VectorInput_Image{Image: image}
+			return decodeRESTFields(image.ProtoReflect(), v, path)
+		case v["object"] != nil:
+			object := &InferenceObject{}
+			input.Variant = &VectorInput_Object{Object: object}
+			return decodeRESTFields(object.ProtoReflect(), v, path)
+		}
+		return fmt.Errorf("%s: unknown vector input with keys %q", restOr(path), sortedKeys(v))
+	}
+	id := &PointId{}
+	if err := decodeRESTPointID(id, value, path); err != nil {
+		return restTypeError(path, "a vector or a point ID", value)
+	}
+	input.Variant = NewVectorInputID(id).GetVariant()
+	return nil
+}
+
+// Internal method.
+func encodeRESTVectorInput(input *VectorInput) (any, error) {
+	switch v := input.GetVariant().(type) {
+	case *VectorInput_Id:
+		return encodeRESTPointID(v.Id)
+	case *VectorInput_Dense:
+		return encodeRESTFloats(v.Dense.GetData()), nil
+	case *VectorInput_MultiDense:
+		vectors := make([]any, len(v.MultiDense.GetVectors()))
+		for i, vector := range v.MultiDense.GetVectors() {
+			vectors[i] = encodeRESTFloats(vector.GetData())
+		}
+		return vectors, nil
+	case *VectorInput_Sparse:
+		return map[string]any{
+			"indices": nonNilSlice(v.Sparse.GetIndices()),
+			"values":  encodeRESTFloats(v.Sparse.GetValues()),
+		}, nil
+	case *VectorInput_Document:
+		return encodeRESTFields(v.Document.ProtoReflect())
+	case *VectorInput_Image:
+		return encodeRESTFields(v.Image.ProtoReflect())
+	case *VectorInput_Object:
+		return encodeRESTFields(v.Object.ProtoReflect())
+	}
+	return nil, errors.New("empty vector input")
+}
+
+// Internal method.
+// A context is a list of pairs, or a single pair.
+func decodeRESTContextInput(context *ContextInput, value any, path string) error {
+	return decodeRESTField(context.ProtoReflect(), context.ProtoReflect().Descriptor().Fields().ByName("pairs"),
+		value, path)
+}
+
+// Internal method.
+func encodeRESTContextInput(context *ContextInput) (any, error) {
+	fd := context.ProtoReflect().Descriptor().Fields().ByName("pairs")
+	return encodeRESTField(fd, context.ProtoReflect().Get(fd))
+}
+
+// Internal method.
+// An order by is a payload key or an object.
+func decodeRESTOrderBy(orderBy *OrderBy, value any, path string) error {
+	if key, ok := value.(string); ok {
+		orderBy.Key = key
+		return nil
+	}
+	return decodeRESTFields(orderBy.ProtoReflect(), value, path)
+}
+
+// Internal method.
+// A start from is a number or a datetime string.
+func decodeRESTStartFrom(startFrom *StartFrom, value any, path string) error {
+	if datetime, ok := value.(string); ok {
+		startFrom.Value = NewStartFromDatetime(datetime).GetValue()
+		return nil
+	}
+	if integer, err := restInt(value, 64, path); err == nil {
+		startFrom.Value = NewStartFromInt(integer).GetValue()
+		return nil
+	}
+	f, err := restFloat(value, path)
+	if err != nil {
+		return restTypeError(path, "a number or a datetime string", value)
+	}
+	startFrom.Value = NewStartFromFloat(f).GetValue()
+	return nil
+}
+
+// Internal method.
+func encodeRESTStartFrom(startFrom *StartFrom) (any, error) {
+	switch v := startFrom.GetValue().(type) {
+	case *StartFrom_Integer:
+		return v.Integer, nil
+	case *StartFrom_Float:
+		return restNumber(v.Float, 64), nil
+	case *StartFrom_Timestamp:
+		return encodeRESTTimestamp(v.Timestamp)
+	case *StartFrom_Datetime:
+		return v.Datetime, nil
+	}
+	return nil, errors.New("empty start from")
+}
+
+// Internal method.
+// A payload selector is a boolean, a list of fields to include,
+// or an object with the fields to "include" or "exclude".
+func decodeRESTWithPayload(selector *WithPayloadSelector, value any, path string) error {
+	switch v := value.(type) {
+	case bool:
+		selector.SelectorOptions = NewWithPayloadEnable(v).GetSelectorOptions()
+		return nil
+	case []any:
+		fields, err := restStrings(v, path)
+		selector.SelectorOptions = NewWithPayloadInclude(fields...).GetSelectorOptions()
+		return err
+	case map[string]any:
+		key, err := restSingleKey(v, path)
+		if err != nil {
+			return err
+		}
+		fields, err := restStrings(v[key], restPath(path, key))
+		switch key {
+		case "include":
+			selector.SelectorOptions = NewWithPayloadInclude(fields...).GetSelectorOptions()
+		case "exclude":
+			selector.SelectorOptions = NewWithPayloadExclude(fields...).GetSelectorOptions()
+		default:
+			return fmt.Errorf("%s: unknown field", restPath(path, key))
+		}
+		return err
+	}
+	return restTypeError(path, "a boolean, an array or an object", value)
+}
+
+// Internal method.
+func encodeRESTWithPayload(selector *WithPayloadSelector) (any, error) {
+	switch s := selector.GetSelectorOptions().(type) {
+	case *WithPayloadSelector_Enable:
+		return s.Enable, nil
+	case *WithPayloadSelector_Include:
+		return nonNilSlice(s.Include.GetFields()), nil
+	case *WithPayloadSelector_Exclude:
+		return map[string]any{"exclude": nonNilSlice(s.Exclude.GetFields())}, nil
+	}
+	return nil, errors.New("empty payload selector")
+}
+
+// Internal method.
+// A vectors selector is a boolean or a list of vector names.
+func decodeRESTWithVectors(selector *WithVectorsSelector, value any, path string) error {
+	switch v := value.(type) {
+	case bool:
+		selector.SelectorOptions = NewWithVectorsEnable(v).GetSelectorOptions()
+		return nil
+	case []any:
+		names, err := restStrings(v, path)
+		selector.SelectorOptions = NewWithVectorsInclude(names...).GetSelectorOptions()
+		return err
+	}
+	return restTypeError(path, "a boolean or an array", value)
+}
+
+// Internal method.
+func encodeRESTWithVectors(selector *WithVectorsSelector) (any, error) {
+	switch s := selector.GetSelectorOptions().(type) {
+	case *WithVectorsSelector_Enable:
+		return s.Enable, nil
+	case *WithVectorsSelector_Include:
+		return nonNilSlice(s.Include.GetNames()), nil
+	}
+	return nil, errors.New("empty vectors selector")
+}
+
+// Internal method.
+func restStrings(value any, path string) ([]string, error) {
+	items, ok := value.([]any)
+	if !ok {
+		return nil, restTypeError(path, "an array of strings", value)
+	}
+	strs := make([]string, len(items))
+	for i, item := range items {
+		s, ok := item.(string)
+		if !ok {
+			return nil, restTypeError(fmt.Sprintf("%s[%d]", path, i), "a string", item)
+		}
+		strs[i] = s
+	}
+	return strs, nil
+}
+
+// Internal method.
+// The vectors config is the parameters of the default vector, which have a "size",
+// or a map of vector names to parameters.
+func decodeRESTVectorsConfig(config *VectorsConfig, value any, path string) error {
+	obj, err := restObject(value, path)
+	if err != nil {
+		return err
+	}
+	if _, ok := obj["size"]; ok {
+		params := &VectorParams{}
+		config.Config = &VectorsConfig_Params{Params: params}
+		return decodeRESTFields(params.ProtoReflect(), obj, path)
+	}
+	paramsMap := &VectorParamsMap{}
+	config.Config = &VectorsConfig_ParamsMap{ParamsMap: paramsMap}
+	return decodeRESTMapWrapper(paramsMap.ProtoReflect(), obj, path)
+}
+
+// Internal method.
+func encodeRESTVectorsConfig(config *VectorsConfig) (any, error) {
+	switch c := config.GetConfig().(type) {
+	case *VectorsConfig_Params:
+		return encodeRESTFields(c.Params.ProtoReflect())
+	case *VectorsConfig_ParamsMap:
+		return encodeRESTMapWrapper(c.ParamsMap.ProtoReflect())
+	}
+	return nil, errors.New("empty vectors config")
+}
+
+// Internal method.
+func decodeRESTMapWrapper(msg protoreflect.Message, value any, path string) error {
+	return decodeRESTField(msg, msg.Descriptor().Fields().Get(0), value, path)
+}
+
+// Internal method.
+func encodeRESTMapWrapper(msg protoreflect.Message) (any, error) {
+	fd := msg.Descriptor().Fields().Get(0)
+	return encodeRESTField(fd, msg.Get(fd))
+}
+
+// Internal method.
+// The maximum optimization threads is a number or "auto".
+func decodeRESTMaxOptimizationThreads(threads *MaxOptimizationThreads, value any, path string) error {
+	if _, ok := value.(string); ok {
+		fd := threads.ProtoReflect().Descriptor().Fields().ByName("setting")
+		return decodeRESTField(threads.ProtoReflect(), fd, value, path)
+	}
+	count, err := restUint(value, 64, path)
+	if err != nil {
+		return restTypeError(path, `a non-negative integer or "auto"`, value)
+	}
+	threads.Variant = NewMaxOptimizationThreads(count).GetVariant()
+	return nil
+}
+
+// Internal method.
+func encodeRESTMaxOptimizationThreads(threads *MaxOptimizationThreads) (any, error) {
+	switch v := threads.GetVariant().(type) {
+	case *MaxOptimizationThreads_Value:
+		return v.Value, nil
+	case *MaxOptimizationThreads_Setting_:
+		fd := threads.ProtoReflect().Descriptor().Fields().ByName("setting")
+		return restEnumName(fd.Enum(), protoreflect.EnumNumber(v.Setting)), nil
+	}
+	return nil, errors.New("empty max optimization threads")
+}
+
+// Internal method.
+// The query encoding of binary quantization is a setting name, e.g. "scalar8bits".
+func decodeRESTQueryEncoding(encoding *BinaryQuantizationQueryEncoding, value any, path string) error {
+	fd := encoding.ProtoReflect().Descriptor().Fields().ByName("setting")
+	return decodeRESTField(encoding.ProtoReflect(), fd, value, path)
+}
+
+// Internal method.
+func encodeRESTQueryEncoding(encoding *BinaryQuantizationQueryEncoding) (any, error) {
+	fd := encoding.ProtoReflect().Descriptor().Fields().ByName("setting")
+	if !encoding.ProtoReflect().Has(fd) {
+		return nil, errors.New("empty query encoding")
+	}
+	return restEnumName(fd.Enum(), encoding.ProtoReflect().Get(fd).Enum()), nil
+}
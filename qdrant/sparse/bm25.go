@@ -0,0 +1,110 @@
+package sparse
+
+import (
+	"slices"
+
+	"github.com/qdrant/go-client/qdrant"
+)
+
+// Default BM25 parameters, as used by the Qdrant/bm25 model of FastEmbed.
+const (
+	DefaultK         = 1.2
+	DefaultB         = 0.75
+	DefaultAvgDocLen = 256
+)
+
+// BM25Options are the parameters of the BM25 term frequency weighting.
+type BM25Options struct {
+	// Term frequency saturation. If 0, defaults to 1.2.
+	K float64
+	// Document length normalization, between 0 and 1. If 0, defaults to 0.75.
+	B float64
+	// Average number of terms of the documents. If 0, defaults to 256.
+	AvgDocLen float64
+}
+
+// BM25 encodes documents and queries into sparse vectors. Obtain one with NewBM25.
+type BM25 struct {
+	tokenizer *Tokenizer
+	k         float64
+	b         float64
+	avgDocLen float64
+}
+
+// NewBM25 creates an encoder splitting texts with tokenizer.
+// If options is nil, the default parameters are used.
+func NewBM25(tokenizer *Tokenizer, options *BM25Options) *BM25 {
+	if options == nil {
+		options = &BM25Options{}
+	}
+	e := &BM25{
+		tokenizer: tokenizer,
+		k:         options.K,
+		b:         options.B,
+		avgDocLen: options.AvgDocLen,
+	}
+	if e.k == 0 {
+		e.k = DefaultK
+	}
+	if e.b == 0 {
+		e.b = DefaultB
+	}
+	if e.avgDocLen == 0 {
+		e.avgDocLen = DefaultAvgDocLen
+	}
+	return e
+}
+
+// VectorParams returns the parameters of a sparse vector storing BM25 vectors,
+// with the IDF modifier so that the server weights the terms by their inverse document frequency.
+func VectorParams() *qdrant.SparseVectorParams {
+	return &qdrant.SparseVectorParams{
+		Modifier: qdrant.Modifier_Idf.Enum(),
+	}
+}
+
+// EncodeDocument returns the indices and values of the sparse vector of a document,
+// weighting each term by its BM25 term frequency:
+//
+//	tf * (k + 1) / (tf + k * (1 - b + b * len / avgDocLen))
+//
+// The indices are sorted. Terms whose hashes collide share an index.
+func (e *BM25) EncodeDocument(text string) ([]uint32, []float32) {
+	terms := e.tokenizer.Tokenize(text)
+	frequencies := termFrequencies(terms)
+	norm := e.k * (1 - e.b + e.b*float64(len(terms))/e.avgDocLen)
+	return sortedVector(frequencies, func(tf float64) float32 {
+		return float32(tf * (e.k + 1) / (tf + norm))
+	})
+}
+
+// EncodeQuery returns the indices and values of the sparse vector of a query,
+// in which every distinct term has a weight of 1.
+func (e *BM25) EncodeQuery(text string) ([]uint32, []float32) {
+	frequencies := termFrequencies(e.tokenizer.Tokenize(text))
+	return sortedVector(frequencies, func(float64) float32 { return 1 })
+}
+
+// Internal method.
+// Returns the number of occurrences of the terms, by index.
+func termFrequencies(terms []string) map[uint32]float64 {
+	frequencies := make(map[uint32]float64, len(terms))
+	for _, term := range terms {
+		frequencies[HashTerm(term)]++
+	}
+	return frequencies
+}
+
+// Internal method.
+func sortedVector(frequencies map[uint32]float64, weight func(tf float64) float32) ([]uint32, []float32) {
+	indices := make([]uint32, 0, len(frequencies))
+	for index := range frequencies {
+		indices = append(indices, index)
+	}
+	slices.Sort(indices)
+	values := make([]float32, len(indices))
+	for i, index := range indices {
+		values[i] = weight(frequencies[index])
+	}
+	return indices, values
+}
@@ -0,0 +1,33 @@
+/*
+Package sparse encodes texts into BM25 sparse vectors on the client side.
+
+Texts are split into terms by a Tokenizer configured like a full-text payload index,
+terms are hashed into stable uint32 indices and weighted by their BM25 term frequency.
+The inverse document frequency is applied by the server, so the sparse vector must be
+configured with the IDF modifier, e.g. with VectorParams.
+
+USAGE:
+
+	err := client.CreateCollection(ctx, &qdrant.CreateCollection{
+		CollectionName:      "my_collection",
+		SparseVectorsConfig: qdrant.NewSparseVectorsConfig(map[string]*qdrant.SparseVectorParams{
+			"bm25": sparse.VectorParams(),
+		}),
+	})
+	tokenizer, err := sparse.NewTokenizer(&qdrant.TextIndexParams{
+		Tokenizer: qdrant.TokenizerType_Word,
+		Stopwords: &qdrant.StopwordsSet{Languages: []string{"english"}},
+		Stemmer:   qdrant.NewStemmingAlgorithmSnowball(&qdrant.SnowballParams{Language: "english"}),
+	})
+	encoder := sparse.NewBM25(tokenizer, nil)
+	indices, values := encoder.EncodeDocument("The quick brown fox")
+	vectors := qdrant.NewVectorsMap(map[string]*qdrant.Vector{"bm25": qdrant.NewVectorSparse(indices, values)})
+	...
+	indices, values = encoder.EncodeQuery("quick foxes")
+	query := qdrant.NewQuerySparse(indices, values)
+
+Documents and queries must be encoded with the same tokenizer.
+Hashing is compatible with the Qdrant/bm25 model of FastEmbed, so that vectors encoded
+with either can be mixed when the tokenizers agree.
+*/
+package sparse
@@ -0,0 +1,66 @@
+package sparse
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// MurmurHash3 x86_32 constants.
+const (
+	murmurC1 = 0xcc9e2d51
+	murmurC2 = 0x1b873593
+	murmurN  = 0xe6546b64
+	murmurF1 = 0x85ebca6b
+	murmurF2 = 0xc2b2ae35
+)
+
+// HashTerm returns the index of a term in a sparse vector.
+//
+// It is the absolute value of the signed 32-bit MurmurHash3 of the UTF-8 term with seed 0,
+// as computed by the Qdrant/bm25 model of FastEmbed.
+func HashTerm(term string) uint32 {
+	hash := int32(murmur3(term)) //nolint:gosec // Reinterpreted as signed on purpose.
+	if hash < 0 {
+		return uint32(-int64(hash))
+	}
+	return uint32(hash)
+}
+
+// Internal method.
+// MurmurHash3 x86_32 with seed 0.
+func murmur3(data string) uint32 {
+	var hash uint32
+	length := len(data)
+	for ; len(data) >= 4; data = data[4:] {
+		k := binary.LittleEndian.Uint32([]byte(data[:4]))
+		hash ^= murmurScramble(k)
+		hash = bits.RotateLeft32(hash, 13)
+		hash = hash*5 + murmurN
+	}
+	var k uint32
+	switch len(data) {
+	case 3:
+		k ^= uint32(data[2]) << 16
+		fallthrough
+	case 2:
+		k ^= uint32(data[1]) << 8
+		fallthrough
+	case 1:
+		k ^= uint32(data[0])
+		hash ^= murmurScramble(k)
+	}
+	hash ^= uint32(length) //nolint:gosec // Truncated like the reference implementation.
+	hash ^= hash >> 16
+	hash *= murmurF1
+	hash ^= hash >> 13
+	hash *= murmurF2
+	hash ^= hash >> 16
+	return hash
+}
+
+// Internal method.
+func murmurScramble(k uint32) uint32 {
+	k *= murmurC1
+	k = bits.RotateLeft32(k, 15)
+	return k * murmurC2
+}
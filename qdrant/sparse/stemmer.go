@@ -0,0 +1,353 @@
+package sparse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Stemmer reduces a lowercase term to its stem.
+type Stemmer interface {
+	Stem(term string) string
+}
+
+// StemmerFunc adapts a function to the Stemmer interface.
+type StemmerFunc func(term string) string
+
+// Stem calls f.
+func (f StemmerFunc) Stem(term string) string {
+	return f(term)
+}
+
+// NewSnowballStemmer returns the Snowball stemmer of a language, as set in SnowballParams.Language.
+// Only English is built in. Stemmers of other languages can be set with Tokenizer.WithStemmer.
+func NewSnowballStemmer(language string) (Stemmer, error) {
+	switch strings.ToLower(language) {
+	case "english", "en":
+		return StemmerFunc(stemEnglish), nil
+	}
+	return nil, fmt.Errorf("unsupported stemmer language %q", language)
+}
+
+// A suffix replaced by a step of the English stemmer.
+type englishSuffix struct {
+	suffix      string
+	replacement string
+}
+
+// Internal method.
+// Stems a lowercase English word with the Snowball English (Porter2) algorithm.
+// https://snowballstem.org/algorithms/english/stemmer.html
+func stemEnglish(word string) string {
+	if len(word) <= 2 { //nolint:mnd // Words of up to 2 letters are left as is.
+		return word
+	}
+	if stem, ok := englishException(word); ok {
+		return stem
+	}
+	w := []byte(strings.TrimPrefix(word, "'"))
+	for i, c := range w {
+		if c == 'y' && (i == 0 || isEnglishVowel(w[i-1])) {
+			w[i] = 'Y'
+		}
+	}
+	r1 := englishR1(w)
+	r2 := englishRegionStart(w, r1)
+
+	w = englishStep0(w)
+	w = englishStep1a(w)
+	if isEnglishInvariant(string(w)) {
+		return string(w)
+	}
+	w = englishStep1b(w, r1)
+	w = englishStep1c(w)
+	w = englishStep2(w, r1)
+	w = englishStep3(w, r1, r2)
+	w = englishStep4(w, r2)
+	w = englishStep5(w, r1, r2)
+	return strings.ReplaceAll(string(w), "Y", "y")
+}
+
+// Internal method.
+func englishException(word string) (string, bool) {
+	switch word {
+	case "skis":
+		return "ski", true
+	case "skies":
+		return "sky", true
+	case "dying":
+		return "die", true
+	case "lying":
+		return "lie", true
+	case "tying":
+		return "tie", true
+	case "idly":
+		return "idl", true
+	case "gently":
+		return "gentl", true
+	case "ugly":
+		return "ugli", true
+	case "early":
+		return "earli", true
+	case "only":
+		return "onli", true
+	case "singly":
+		return "singl", true
+	case "sky", "news", "howe", "atlas", "cosmos", "bias", "andes":
+		return word, true
+	}
+	return "", false
+}
+
+// Internal method.
+// Reports words left as is after step 1a.
+func isEnglishInvariant(word string) bool {
+	switch word {
+	case "inning", "outing", "canning", "herring", "earring", "proceed", "exceed", "succeed":
+		return true
+	}
+	return false
+}
+
+// Internal method.
+func isEnglishVowel(c byte) bool {
+	switch c {
+	case 'a', 'e', 'i', 'o', 'u', 'y':
+		return true
+	}
+	return false
+}
+
+// Internal method.
+func englishR1(w []byte) int {
+	for _, prefix := range []string{"gener", "commun", "arsen"} {
+		if strings.HasPrefix(string(w), prefix) {
+			return len(prefix)
+		}
+	}
+	return englishRegionStart(w, 0)
+}
+
+// Internal method.
+// Returns the index after the first non-vowel following a vowel, from start.
+func englishRegionStart(w []byte, start int) int {
+	for i := start + 1; i < len(w); i++ {
+		if !isEnglishVowel(w[i]) && isEnglishVowel(w[i-1]) {
+			return i + 1
+		}
+	}
+	return len(w)
+}
+
+// Internal method.
+func hasEnglishVowel(w []byte) bool {
+	for _, c := range w {
+		if isEnglishVowel(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// Internal method.
+func endsWithShortSyllable(w []byte) bool {
+	n := len(w)
+	//nolint:mnd // A short syllable has 2 or 3 letters.
+	switch {
+	case n >= 3:
+		last := w[n-1]
+		return !isEnglishVowel(w[n-3]) && isEnglishVowel(w[n-2]) && !isEnglishVowel(last) &&
+			last != 'w' && last != 'x' && last != 'Y'
+	case n == 2:
+		return isEnglishVowel(w[0]) && !isEnglishVowel(w[1])
+	}
+	return false
+}
+
+// Internal method.
+func hasSuffix(w []byte, suffix string) bool {
+	return strings.HasSuffix(string(w), suffix)
+}
+
+// Internal method.
+// Returns the first, and longest, of suffixes ending w.
+func longestSuffix(w []byte, suffixes []englishSuffix) (englishSuffix, bool) {
+	for _, s := range suffixes {
+		if hasSuffix(w, s.suffix) {
+			return s, true
+		}
+	}
+	return englishSuffix{}, false
+}
+
+// Internal method.
+func replaceSuffix(w []byte, s englishSuffix) []byte {
+	return append(w[:len(w)-len(s.suffix)], s.replacement...)
+}
+
+// Internal method.
+func englishStep0(w []byte) []byte {
+	for _, suffix := range []string{"'s'", "'s", "'"} {
+		if hasSuffix(w, suffix) {
+			return w[:len(w)-len(suffix)]
+		}
+	}
+	return w
+}
+
+// Internal method.
+func englishStep1a(w []byte) []byte {
+	n := len(w)
+	switch {
+	case hasSuffix(w, "sses"):
+		return w[:n-2]
+	case hasSuffix(w, "ied"), hasSuffix(w, "ies"):
+		// "ties" becomes "tie" but "cries" becomes "cri".
+		if n > 4 { //nolint:mnd // More than one letter before the suffix.
+			return w[:n-2]
+		}
+		return w[:n-1]
+	case hasSuffix(w, "us"), hasSuffix(w, "ss"):
+		return w
+	case hasSuffix(w, "s"):
+		// "gaps" becomes "gap" but "gas" is left as is.
+		if n >= 2 && hasEnglishVowel(w[:n-2]) {
+			return w[:n-1]
+		}
+	}
+	return w
+}
+
+// Internal method.
+func englishStep1b(w []byte, r1 int) []byte {
+	for _, suffix := range []string{"eedly", "eed"} {
+		if hasSuffix(w, suffix) {
+			if len(w)-len(suffix) >= r1 {
+				return replaceSuffix(w, englishSuffix{suffix, "ee"})
+			}
+			return w
+		}
+	}
+	for _, suffix := range []string{"ingly", "edly", "ing", "ed"} {
+		if !hasSuffix(w, suffix) {
+			continue
+		}
+		stem := w[:len(w)-len(suffix)]
+		if !hasEnglishVowel(stem) {
+			return w
+		}
+		switch {
+		case hasSuffix(stem, "at"), hasSuffix(stem, "bl"), hasSuffix(stem, "iz"):
+			return append(stem, 'e')
+		case isEnglishDouble(stem):
+			return stem[:len(stem)-1]
+		case r1 >= len(stem) && endsWithShortSyllable(stem):
+			return append(stem, 'e')
+		}
+		return stem
+	}
+	return w
+}
+
+// Internal method.
+func isEnglishDouble(w []byte) bool {
+	n := len(w)
+	if n < 2 || w[n-1] != w[n-2] { //nolint:mnd // A double is 2 letters.
+		return false
+	}
+	switch w[n-1] {
+	case 'b', 'd', 'f', 'g', 'm', 'n', 'p', 'r', 't':
+		return true
+	}
+	return false
+}
+
+// Internal method.
+func englishStep1c(w []byte) []byte {
+	n := len(w)
+	if n > 2 && (w[n-1] == 'y' || w[n-1] == 'Y') && !isEnglishVowel(w[n-2]) {
+		w[n-1] = 'i'
+	}
+	return w
+}
+
+// Internal method.
+func englishStep2(w []byte, r1 int) []byte {
+	s, ok := longestSuffix(w, []englishSuffix{
+		{"ization", "ize"}, {"ational", "ate"}, {"fulness", "ful"}, {"ousness", "ous"}, {"iveness", "ive"},
+		{"tional", "tion"}, {"biliti", "ble"}, {"lessli", "less"},
+		{"entli", "ent"}, {"ation", "ate"}, {"alism", "al"}, {"aliti", "al"}, {"ousli", "ous"},
+		{"iviti", "ive"}, {"fulli", "ful"},
+		{"enci", "ence"}, {"anci", "ance"}, {"abli", "able"}, {"izer", "ize"}, {"ator", "ate"}, {"alli", "al"},
+		{"bli", "ble"}, {"ogi", "og"},
+		{"li", ""},
+	})
+	if !ok || len(w)-len(s.suffix) < r1 {
+		return w
+	}
+	stem := w[:len(w)-len(s.suffix)]
+	switch s.suffix {
+	case "ogi":
+		if !hasSuffix(stem, "l") {
+			return w
+		}
+	case "li":
+		if len(stem) == 0 || !strings.ContainsRune("cdeghkmnrt", rune(stem[len(stem)-1])) {
+			return w
+		}
+	}
+	return replaceSuffix(w, s)
+}
+
+// Internal method.
+func englishStep3(w []byte, r1, r2 int) []byte {
+	s, ok := longestSuffix(w, []englishSuffix{
+		{"ational", "ate"}, {"tional", "tion"},
+		{"alize", "al"}, {"icate", "ic"}, {"iciti", "ic"}, {"ative", ""},
+		{"ical", "ic"}, {"ness", ""},
+		{"ful", ""},
+	})
+	if !ok || len(w)-len(s.suffix) < r1 {
+		return w
+	}
+	if s.suffix == "ative" && len(w)-len(s.suffix) < r2 {
+		return w
+	}
+	return replaceSuffix(w, s)
+}
+
+// Internal method.
+func englishStep4(w []byte, r2 int) []byte {
+	s, ok := longestSuffix(w, []englishSuffix{
+		{"ement", ""},
+		{"ance", ""}, {"ence", ""}, {"able", ""}, {"ible", ""}, {"ment", ""},
+		{"ant", ""}, {"ent", ""}, {"ism", ""}, {"ate", ""}, {"iti", ""}, {"ous", ""}, {"ive", ""}, {"ize", ""},
+		{"ion", ""},
+		{"al", ""}, {"er", ""}, {"ic", ""},
+	})
+	if !ok || len(w)-len(s.suffix) < r2 {
+		return w
+	}
+	if s.suffix == "ion" {
+		stem := w[:len(w)-len(s.suffix)]
+		if !hasSuffix(stem, "s") && !hasSuffix(stem, "t") {
+			return w
+		}
+	}
+	return replaceSuffix(w, s)
+}
+
+// Internal method.
+func englishStep5(w []byte, r1, r2 int) []byte {
+	n := len(w)
+	switch {
+	case hasSuffix(w, "e"):
+		if n-1 >= r2 || (n-1 >= r1 && !endsWithShortSyllable(w[:n-1])) {
+			return w[:n-1]
+		}
+	case hasSuffix(w, "l"):
+		if n-1 >= r2 && hasSuffix(w[:n-1], "l") {
+			return w[:n-1]
+		}
+	}
+	return w
+}
@@ -0,0 +1,252 @@
+package sparse
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/qdrant/go-client/qdrant"
+)
+
+// Tokenizer splits texts into terms, the way a full-text payload index configured
+// with the same TextIndexParams does. Obtain one with NewTokenizer.
+type Tokenizer struct {
+	splitWords   bool
+	lowercase    bool
+	asciiFolding bool
+	minTokenLen  int
+	maxTokenLen  int
+	stopwords    map[string]struct{}
+	stemmer      Stemmer
+}
+
+// NewTokenizer creates a tokenizer configured like a full-text payload index.
+// If params is nil, texts are split into lowercase words.
+//
+// The word and multilingual tokenizers split texts on characters that are neither letters nor digits,
+// and the whitespace tokenizer on whitespace. The prefix tokenizer is not supported.
+// Tokens are lowercased unless Lowercase is false, and folded to ASCII if AsciiFolding is true.
+// Tokens shorter than MinTokenLen or longer than MaxTokenLen characters and stopwords are dropped,
+// then the remaining tokens are stemmed.
+//
+// Returns an error if a stopwords or stemmer language is not supported.
+func NewTokenizer(params *qdrant.TextIndexParams) (*Tokenizer, error) {
+	t := &Tokenizer{
+		lowercase:    params == nil || params.Lowercase == nil || params.GetLowercase(),
+		asciiFolding: params.GetAsciiFolding(),
+		minTokenLen:  int(params.GetMinTokenLen()), //nolint:gosec // Token lengths are small.
+		maxTokenLen:  int(params.GetMaxTokenLen()), //nolint:gosec // Token lengths are small.
+	}
+	switch params.GetTokenizer() {
+	case qdrant.TokenizerType_Unknown, qdrant.TokenizerType_Word, qdrant.TokenizerType_Multilingual:
+		t.splitWords = true
+	case qdrant.TokenizerType_Whitespace:
+	case qdrant.TokenizerType_Prefix:
+		return nil, errors.New("prefix tokenizer is not supported for sparse vectors")
+	}
+	if stopwords := params.GetStopwords(); stopwords != nil {
+		t.stopwords = make(map[string]struct{})
+		for _, language := range stopwords.GetLanguages() {
+			words, err := languageStopwords(language)
+			if err != nil {
+				return nil, err
+			}
+			t.addStopwords(words)
+		}
+		t.addStopwords(stopwords.GetCustom())
+	}
+	if snowball := params.GetStemmer().GetSnowball(); snowball != nil {
+		stemmer, err := NewSnowballStemmer(snowball.GetLanguage())
+		if err != nil {
+			return nil, err
+		}
+		t.stemmer = stemmer
+	}
+	return t, nil
+}
+
+// WithStemmer returns a copy of the tokenizer using stemmer, e.g. for a language not built in.
+// If stemmer is nil, tokens are not stemmed.
+func (t *Tokenizer) WithStemmer(stemmer Stemmer) *Tokenizer {
+	clone := *t
+	clone.stemmer = stemmer
+	return &clone
+}
+
+// Tokenize returns the terms of text, in order and with duplicates.
+func (t *Tokenizer) Tokenize(text string) []string {
+	var tokens []string
+	if t.splitWords {
+		tokens = strings.FieldsFunc(text, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+	} else {
+		tokens = strings.Fields(text)
+	}
+	terms := tokens[:0]
+	for _, token := range tokens {
+		if term, ok := t.processToken(token); ok {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// Internal method.
+func (t *Tokenizer) processToken(token string) (string, bool) {
+	if t.lowercase {
+		token = strings.ToLower(token)
+	}
+	if t.asciiFolding {
+		token = foldASCII(token)
+	}
+	length := utf8.RuneCountInString(token)
+	if length == 0 || length < t.minTokenLen || (t.maxTokenLen > 0 && length > t.maxTokenLen) {
+		return "", false
+	}
+	if _, ok := t.stopwords[token]; ok {
+		return "", false
+	}
+	if t.stemmer != nil {
+		token = t.stemmer.Stem(token)
+	}
+	return token, true
+}
+
+// Internal method.
+func (t *Tokenizer) addStopwords(words []string) {
+	for _, word := range words {
+		if t.lowercase {
+			word = strings.ToLower(word)
+		}
+		t.stopwords[word] = struct{}{}
+	}
+}
+
+// Internal method.
+// Replaces the accented Latin letters of token with their ASCII base letters.
+func foldASCII(token string) string {
+	var b strings.Builder
+	for i, r := range token {
+		folded := foldRune(r)
+		if folded == "" {
+			if b.Len() > 0 {
+				b.WriteRune(r)
+			}
+			continue
+		}
+		if b.Len() == 0 {
+			b.Grow(len(token))
+			b.WriteString(token[:i])
+		}
+		b.WriteString(folded)
+	}
+	if b.Len() == 0 {
+		return token
+	}
+	return b.String()
+}
+
+// Internal method.
+// Returns the ASCII folding of r, or "" if r is left as is.
+//
+//nolint:cyclop,gocyclo // A lookup table.
+func foldRune(r rune) string {
+	if r < utf8.RuneSelf {
+		return ""
+	}
+	switch r {
+	case 'à', 'á', 'â', 'ã', 'ä', 'å', 'ā', 'ă', 'ą':
+		return "a"
+	case 'À', 'Á', 'Â', 'Ã', 'Ä', 'Å', 'Ā', 'Ă', 'Ą':
+		return "A"
+	case 'æ':
+		return "ae"
+	case 'Æ':
+		return "AE"
+	case 'ç', 'ć', 'ĉ', 'ċ', 'č':
+		return "c"
+	case 'Ç', 'Ć', 'Ĉ', 'Ċ', 'Č':
+		return "C"
+	case 'ď', 'đ', 'ð':
+		return "d"
+	case 'Ď', 'Đ', 'Ð':
+		return "D"
+	case 'è', 'é', 'ê', 'ë', 'ē', 'ĕ', 'ė', 'ę', 'ě':
+		return "e"
+	case 'È', 'É', 'Ê', 'Ë', 'Ē', 'Ĕ', 'Ė', 'Ę', 'Ě':
+		return "E"
+	case 'ĝ', 'ğ', 'ġ', 'ģ':
+		return "g"
+	case 'Ĝ', 'Ğ', 'Ġ', 'Ģ':
+		return "G"
+	case 'ĥ', 'ħ':
+		return "h"
+	case 'Ĥ', 'Ħ':
+		return "H"
+	case 'ì', 'í', 'î', 'ï', 'ĩ', 'ī', 'ĭ', 'į', 'ı':
+		return "i"
+	case 'Ì', 'Í', 'Î', 'Ï', 'Ĩ', 'Ī', 'Ĭ', 'Į', 'İ':
+		return "I"
+	case 'ĵ':
+		return "j"
+	case 'Ĵ':
+		return "J"
+	case 'ķ':
+		return "k"
+	case 'Ķ':
+		return "K"
+	case 'ĺ', 'ļ', 'ľ', 'ŀ', 'ł':
+		return "l"
+	case 'Ĺ', 'Ļ', 'Ľ', 'Ŀ', 'Ł':
+		return "L"
+	case 'ñ', 'ń', 'ņ', 'ň':
+		return "n"
+	case 'Ñ', 'Ń', 'Ņ', 'Ň':
+		return "N"
+	case 'ò', 'ó', 'ô', 'õ', 'ö', 'ø', 'ō', 'ŏ', 'ő':
+		return "o"
+	case 'Ò', 'Ó', 'Ô', 'Õ', 'Ö', 'Ø', 'Ō', 'Ŏ', 'Ő':
+		return "O"
+	case 'œ':
+		return "oe"
+	case 'Œ':
+		return "OE"
+	case 'ŕ', 'ŗ', 'ř':
+		return "r"
+	case 'Ŕ', 'Ŗ', 'Ř':
+		return "R"
+	case 'ś', 'ŝ', 'ş', 'š':
+		return "s"
+	case 'Ś', 'Ŝ', 'Ş', 'Š':
+		return "S"
+	case 'ß':
+		return "ss"
+	case 'ţ', 'ť', 'ŧ':
+		return "t"
+	case 'Ţ', 'Ť', 'Ŧ':
+		return "T"
+	case 'þ':
+		return "th"
+	case 'Þ':
+		return "TH"
+	case 'ù', 'ú', 'û', 'ü', 'ũ', 'ū', 'ŭ', 'ů', 'ű', 'ų':
+		return "u"
+	case 'Ù', 'Ú', 'Û', 'Ü', 'Ũ', 'Ū', 'Ŭ', 'Ů', 'Ű', 'Ų':
+		return "U"
+	case 'ŵ':
+		return "w"
+	case 'Ŵ':
+		return "W"
+	case 'ý', 'ÿ', 'ŷ':
+		return "y"
+	case 'Ý', 'Ÿ', 'Ŷ':
+		return "Y"
+	case 'ź', 'ż', 'ž':
+		return "z"
+	case 'Ź', 'Ż', 'Ž':
+		return "Z"
+	}
+	return ""
+}
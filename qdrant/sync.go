@@ -0,0 +1,272 @@
+package qdrant
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"maps"
+)
+
+// Default number of source points compared and upserted per request by SyncPoints.
+const defaultSyncBatchSize = 256
+
+// SyncOptions configures SyncPoints.
+type SyncOptions struct {
+	// Payload key in which the content hash of the points is stored.
+	// If set, the hash of the payload and vectors of every source point is stored under this key,
+	// and existing points are only upserted again when their stored hash differs.
+	// If empty, existing points are never upserted again, and are reported as unchanged.
+	HashKey string
+	// Whether to delete the points of the collection that are not in the source.
+	Delete bool
+	// Restricts the points of the collection considered for deletion, e.g. to the points of one tenant
+	// when several sources are synced into the same collection. Only used if Delete is set.
+	Filter *Filter
+	// Derives the ID of the source points without one, e.g. with KeyPointIDs.
+	// If nil, every source point must have an ID.
+	ID PointIDFunc
+	// Number of source points compared and upserted per request.
+	// Defaults to 256.
+	BatchSize int
+	// Whether to wait for the upserts and deletions to be applied.
+	// Defaults to false.
+	Wait bool
+}
+
+// SyncReport counts the points processed by SyncPoints.
+type SyncReport struct {
+	// Source points that were not in the collection.
+	Inserted uint64
+	// Source points that were in the collection with a different content hash, or without one.
+	Updated uint64
+	// Source points that were in the collection with the same content hash.
+	Unchanged uint64
+	// Points of the collection that were not in the source.
+	Deleted uint64
+}
+
+// Internal method.
+func (o *SyncOptions) getBatchSize() int {
+	if o.BatchSize <= 0 {
+		return defaultSyncBatchSize
+	}
+	return o.BatchSize
+}
+
+// Synchronizes a collection with an external dataset: upserts the source points that are new or changed,
+// and optionally deletes the points of the collection that are not in the source.
+// Syncing the same source again is a no-op.
+//
+// Source points are compared with the points of the collection by ID, and by content hash if
+// SyncOptions.HashKey is set. Deleting requires keeping the IDs of all source points in memory.
+// Source points of the same batch with the same ID are synced and counted once, with the content of the last one.
+//
+// Parameters:
+//   - ctx: The context for the request.
+//   - collectionName: The name of the collection to sync.
+//   - source: The points of the dataset. They are not modified.
+//   - options: How to compare and update the points. May be nil to only insert the new points.
+//
+// Returns:
+//   - *SyncReport: The counts of inserted, updated, unchanged and deleted points.
+//     If an error occurs, it counts the points processed before it.
+//   - error: An error if the operation fails.
+func (c *Client) SyncPoints(ctx context.Context, collectionName string, source iter.Seq[*PointStruct],
+	options *SyncOptions,
+) (*SyncReport, error) {
+	if options == nil {
+		options = &SyncOptions{}
+	}
+	s := &pointSyncer{
+		client:         c,
+		collectionName: collectionName,
+		options:        options,
+		report:         &SyncReport{},
+	}
+	if options.Delete {
+		s.seen = make(map[string]struct{})
+	}
+	batch := make([]*PointStruct, 0, options.getBatchSize())
+	for point := range source {
+		batch = append(batch, point)
+		if len(batch) < cap(batch) {
+			continue
+		}
+		if err := s.syncBatch(ctx, batch); err != nil {
+			return s.report, newQdrantErr(err, "SyncPoints", collectionName)
+		}
+		batch = batch[:0]
+	}
+	if err := s.syncBatch(ctx, batch); err != nil {
+		return s.report, newQdrantErr(err, "SyncPoints", collectionName)
+	}
+	if options.Delete {
+		if err := s.deleteMissing(ctx); err != nil {
+			return s.report, newQdrantErr(err, "SyncPoints", collectionName)
+		}
+	}
+	return s.report, nil
+}
+
+// Internal type holding the state of a SyncPoints call.
+type pointSyncer struct {
+	client         *Client
+	collectionName string
+	options        *SyncOptions
+	report         *SyncReport
+	// IDs of the source points, as formatted by FormatPointID. Only kept if deleting.
+	seen map[string]struct{}
+}
+
+// Internal method.
+// Compares a batch of source points with the collection and upserts the new and changed ones.
+func (s *pointSyncer) syncBatch(ctx context.Context, batch []*PointStruct) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	points := make([]*PointStruct, 0, len(batch))
+	ids := make([]*PointId, 0, len(batch))
+	hashes := make([]string, 0, len(batch))
+	// Source points with the same ID are synced once, with the content of the last one.
+	positions := make(map[string]int, len(batch))
+	for _, source := range batch {
+		point, hash, err := s.prepare(source)
+		if err != nil {
+			return err
+		}
+		key := FormatPointID(point.GetId())
+		if i, ok := positions[key]; ok {
+			points[i], hashes[i] = point, hash
+			continue
+		}
+		positions[key] = len(points)
+		points, ids, hashes = append(points, point), append(ids, point.GetId()), append(hashes, hash)
+	}
+	withPayload := NewWithPayload(false)
+	if s.options.HashKey != "" {
+		withPayload = NewWithPayloadInclude(s.options.HashKey)
+	}
+	existing, err := s.client.Get(ctx, &GetPoints{
+		CollectionName: s.collectionName,
+		Ids:            ids,
+		WithPayload:    withPayload,
+		WithVectors:    NewWithVectors(false),
+	})
+	if err != nil {
+		return err
+	}
+	storedHashes := make(map[string]string, len(existing))
+	for _, point := range existing {
+		storedHashes[FormatPointID(point.GetId())] = point.GetPayload()[s.options.HashKey].GetStringValue()
+	}
+	var changed []*PointStruct
+	for i, point := range points {
+		key := FormatPointID(point.GetId())
+		if s.seen != nil {
+			s.seen[key] = struct{}{}
+		}
+		stored, ok := storedHashes[key]
+		switch {
+		case !ok:
+			s.report.Inserted++
+		case s.options.HashKey == "" || stored == hashes[i]:
+			s.report.Unchanged++
+			continue
+		default:
+			s.report.Updated++
+		}
+		changed = append(changed, point)
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	_, err = s.client.Upsert(ctx, &UpsertPoints{
+		CollectionName: s.collectionName,
+		Wait:           &s.options.Wait,
+		Points:         changed,
+	})
+	return err
+}
+
+// Internal method.
+// Returns a copy of a source point with its ID and content hash set, and the content hash.
+func (s *pointSyncer) prepare(source *PointStruct) (*PointStruct, string, error) {
+	point := &PointStruct{
+		Id:      source.GetId(),
+		Payload: source.GetPayload(),
+		Vectors: source.GetVectors(),
+	}
+	if point.GetId().GetPointIdOptions() == nil {
+		if s.options.ID == nil {
+			return nil, "", errors.New("source point without ID")
+		}
+		id, err := s.options.ID(source)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to derive the ID of a source point: %w", err)
+		}
+		point.Id = id
+	}
+	if s.options.HashKey == "" {
+		return point, "", nil
+	}
+	payload := maps.Clone(point.GetPayload())
+	if payload == nil {
+		payload = make(map[string]*Value)
+	}
+	delete(payload, s.options.HashKey)
+	content, err := marshalContent(payload, point.GetVectors())
+	if err != nil {
+		return nil, "", fmt.Errorf("point %s: %w", FormatPointID(point.GetId()), err)
+	}
+	sum := sha256.Sum256(content)
+	hash := hex.EncodeToString(sum[:])
+	payload[s.options.HashKey] = NewValueString(hash)
+	point.Payload = payload
+	return point, hash, nil
+}
+
+// Internal method.
+// Deletes the points of the collection matching the filter whose IDs are not in the source.
+func (s *pointSyncer) deleteMissing(ctx context.Context) error {
+	limit := uint32(s.options.getBatchSize()) //nolint:gosec // The batch size is a small positive number.
+	it := s.client.ScrollAll(ctx, &ScrollPoints{
+		CollectionName: s.collectionName,
+		Filter:         s.options.Filter,
+		Limit:          &limit,
+		WithPayload:    NewWithPayload(false),
+		WithVectors:    NewWithVectors(false),
+	})
+	defer it.Close()
+	for {
+		points, err := it.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var missing []*PointId
+		for _, point := range points {
+			if _, ok := s.seen[FormatPointID(point.GetId())]; !ok {
+				missing = append(missing, point.GetId())
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+		// Deleting the points of the current page does not affect the next pages, which start after it.
+		_, err = s.client.Delete(ctx, &DeletePoints{
+			CollectionName: s.collectionName,
+			Wait:           &s.options.Wait,
+			Points:         NewPointsSelectorIDs(missing),
+		})
+		if err != nil {
+			return err
+		}
+		s.report.Deleted += uint64(len(missing))
+	}
+}
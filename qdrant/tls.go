@@ -0,0 +1,270 @@
+package qdrant
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+const defaultCertExpiryWarning = 30 * 24 * time.Hour
+
+// MTLSConfig configures mutual TLS using PEM files on disk.
+// The files are re-read whenever they change, so rotated certificates and CA bundles
+// are picked up on the next handshake without recreating the Client.
+type MTLSConfig struct {
+	// Path to a PEM bundle of CA certificates used to verify the server.
+	// If empty, the system root CAs are used.
+	CAFile string
+	// Path to the PEM-encoded client certificate.
+	// If empty, no client certificate is presented.
+	CertFile string
+	// Path to the PEM-encoded private key of the client certificate.
+	KeyFile string
+	// ServerName overrides the name used for SNI and server certificate verification.
+	// If empty, Config.Host is used.
+	ServerName string
+	// ExpiryWarning specifies how long before expiry a warning is logged for loaded certificates.
+	// The client certificate is checked again on every handshake, and each warning is logged once per certificate.
+	// If 0, defaults to 30 days. If negative, no warnings are logged.
+	ExpiryWarning time.Duration
+}
+
+// ClientTLSConfig returns a clone of base that loads the client certificate and CA bundle
+// from the configured files, reloading them when they change on disk.
+// If base is nil, a config with minimum TLS version set to 1.3 is used.
+func (m *MTLSConfig) ClientTLSConfig(base *tls.Config) (*tls.Config, error) {
+	if (m.CertFile == "") != (m.KeyFile == "") {
+		return nil, errors.New("both CertFile and KeyFile must be set for mutual TLS")
+	}
+	var tlsConfig *tls.Config
+	if base != nil {
+		tlsConfig = base.Clone()
+	} else {
+		tlsConfig = &tls.Config{
+			MinVersion: tls.VersionTLS13,
+		}
+	}
+	if m.ServerName != "" {
+		tlsConfig.ServerName = m.ServerName
+	}
+	reloader := &tlsReloader{config: *m}
+	if err := reloader.reload(); err != nil {
+		return nil, err
+	}
+	if m.CertFile != "" {
+		tlsConfig.Certificates = nil
+		tlsConfig.GetClientCertificate = reloader.getClientCertificate
+	}
+	if m.CAFile != "" {
+		// Verification against the reloadable CA pool is done in VerifyConnection,
+		// since RootCAs cannot be swapped once the config is handed to gRPC.
+		serverName := tlsConfig.ServerName
+		tlsConfig.InsecureSkipVerify = true //nolint:gosec // The chain is verified in VerifyConnection.
+		tlsConfig.VerifyConnection = func(state tls.ConnectionState) error {
+			return reloader.verifyConnection(state, serverName)
+		}
+	}
+	return tlsConfig, nil
+}
+
+// Internal type that caches the certificate and CA pool loaded from disk.
+type tlsReloader struct {
+	config  MTLSConfig
+	mu      sync.Mutex
+	stamps  map[string]fileStamp
+	cert    *tls.Certificate
+	rootCAs *x509.CertPool
+	// Warnings already logged, so that a certificate checked on every handshake is warned about once.
+	warned map[expiryWarning]struct{}
+}
+
+// Internal type identifying a warning about a certificate that is about to expire, or has expired.
+type expiryWarning struct {
+	cert    *x509.Certificate
+	expired bool
+}
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func (r *tlsReloader) getClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reloadIfChanged()
+	if r.cert != nil {
+		r.warnIfExpiring(r.config.CertFile, r.cert.Leaf)
+	}
+	return r.cert, nil
+}
+
+func (r *tlsReloader) verifyConnection(state tls.ConnectionState, serverName string) error {
+	r.mu.Lock()
+	r.reloadIfChanged()
+	roots := r.rootCAs
+	r.mu.Unlock()
+
+	if len(state.PeerCertificates) == 0 {
+		return errors.New("server presented no certificates")
+	}
+	if state.ServerName != "" {
+		serverName = state.ServerName
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range state.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := state.PeerCertificates[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		DNSName:       serverName,
+		Intermediates: intermediates,
+	})
+	return err
+}
+
+// Internal method.
+// Reloads the files if any of them changed since the last load.
+// Errors are logged and the previously loaded material is kept, since a file
+// may be observed in the middle of being rewritten.
+// The caller must hold r.mu.
+func (r *tlsReloader) reloadIfChanged() {
+	if !r.changed() {
+		return
+	}
+	if err := r.load(); err != nil {
+		slog.Default().Warn("Failed to reload TLS files, keeping previously loaded ones", "err", err)
+	}
+}
+
+// Internal method.
+func (r *tlsReloader) reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.load()
+}
+
+// Internal method.
+// The caller must hold r.mu.
+func (r *tlsReloader) changed() bool {
+	for _, path := range r.paths() {
+		info, err := os.Stat(path)
+		if err != nil {
+			return true
+		}
+		if r.stamps[path] != (fileStamp{modTime: info.ModTime(), size: info.Size()}) {
+			return true
+		}
+	}
+	return false
+}
+
+// Internal method.
+func (r *tlsReloader) paths() []string {
+	var paths []string
+	for _, path := range []string{r.config.CAFile, r.config.CertFile, r.config.KeyFile} {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// Internal method.
+// The caller must hold r.mu.
+func (r *tlsReloader) load() error {
+	stamps := make(map[string]fileStamp)
+	for _, path := range r.paths() {
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("stat %s: %w", path, err)
+		}
+		stamps[path] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+	}
+
+	// The certificates of the previous load are no longer checked.
+	r.warned = make(map[expiryWarning]struct{})
+	var cert *tls.Certificate
+	if r.config.CertFile != "" {
+		loaded, err := tls.LoadX509KeyPair(r.config.CertFile, r.config.KeyFile)
+		if err != nil {
+			return fmt.Errorf("load client certificate: %w", err)
+		}
+		cert = &loaded
+		r.warnIfExpiring(r.config.CertFile, loaded.Leaf)
+	}
+
+	var rootCAs *x509.CertPool
+	if r.config.CAFile != "" {
+		pemData, err := os.ReadFile(r.config.CAFile)
+		if err != nil {
+			return fmt.Errorf("read CA file: %w", err)
+		}
+		rootCAs = x509.NewCertPool()
+		if !rootCAs.AppendCertsFromPEM(pemData) {
+			return fmt.Errorf("no certificates found in CA file %s", r.config.CAFile)
+		}
+		for _, caCert := range parsePEMCertificates(pemData) {
+			r.warnIfExpiring(r.config.CAFile, caCert)
+		}
+	}
+
+	r.stamps = stamps
+	r.cert = cert
+	r.rootCAs = rootCAs
+	return nil
+}
+
+// Internal method.
+// The caller must hold r.mu.
+func (r *tlsReloader) warnIfExpiring(path string, cert *x509.Certificate) {
+	threshold := r.config.ExpiryWarning
+	if threshold < 0 || cert == nil {
+		return
+	}
+	if threshold == 0 {
+		threshold = defaultCertExpiryWarning
+	}
+	remaining := time.Until(cert.NotAfter)
+	if remaining > threshold {
+		return
+	}
+	warning := expiryWarning{cert: cert, expired: remaining <= 0}
+	if _, ok := r.warned[warning]; ok {
+		return
+	}
+	r.warned[warning] = struct{}{}
+	logger := slog.Default()
+	if remaining <= 0 {
+		logger.Warn("TLS certificate has expired.",
+			"file", path, "subject", cert.Subject.String(), "notAfter", cert.NotAfter)
+		return
+	}
+	logger.Warn("TLS certificate is about to expire.",
+		"file", path, "subject", cert.Subject.String(), "notAfter", cert.NotAfter)
+}
+
+func parsePEMCertificates(pemData []byte) []*x509.Certificate {
+	var certs []*x509.Certificate
+	for len(pemData) > 0 {
+		var block *pem.Block
+		block, pemData = pem.Decode(pemData)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err == nil {
+			certs = append(certs, cert)
+		}
+	}
+	return certs
+}
@@ -0,0 +1,217 @@
+package qdrant
+
+import (
+	"context"
+	"maps"
+	"path"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// UsageKey identifies a group of accumulated usage in a UsageCollector.
+type UsageKey struct {
+	// Name of the collection the requests were made to. Empty for requests without a collection.
+	Collection string
+	// Name of the gRPC method, e.g. "Upsert" or "Query".
+	Operation string
+	// Tenant label set with WithUsageTenant. Empty if not set.
+	Tenant string
+}
+
+// UsageTotals holds usage counters accumulated from the Usage reported in responses.
+// Hardware usage is only reported by servers with hardware reporting enabled.
+// See: https://qdrant.tech/documentation/guides/configuration/
+type UsageTotals struct {
+	// Number of responses recorded, including the ones that carried no usage.
+	Requests            uint64
+	CPU                 uint64
+	PayloadIORead       uint64
+	PayloadIOWrite      uint64
+	PayloadIndexIORead  uint64
+	PayloadIndexIOWrite uint64
+	VectorIORead        uint64
+	VectorIOWrite       uint64
+	// Inference tokens used, by model name.
+	InferenceTokens map[string]uint64
+}
+
+// UsageCollector accumulates the HardwareUsage and InferenceUsage reported in responses,
+// grouped by collection, operation and tenant label.
+// It is safe for concurrent use.
+//
+// Attach it to every request of a client with Config.UsageCollector,
+// or to the requests made using a context with WithUsageCollector.
+type UsageCollector struct {
+	mu     sync.Mutex
+	totals map[UsageKey]*UsageTotals
+}
+
+type usageCollectorContextKey struct{}
+
+type usageTenantContextKey struct{}
+
+// NewUsageCollector creates an empty UsageCollector.
+func NewUsageCollector() *UsageCollector {
+	return &UsageCollector{
+		totals: make(map[UsageKey]*UsageTotals),
+	}
+}
+
+// Record adds usage to the totals of key. A nil usage only increments the request count.
+func (c *UsageCollector) Record(key UsageKey, usage *Usage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	totals, ok := c.totals[key]
+	if !ok {
+		totals = &UsageTotals{}
+		c.totals[key] = totals
+	}
+	totals.add(usage)
+}
+
+// Snapshot returns a copy of the accumulated totals.
+func (c *UsageCollector) Snapshot() map[UsageKey]UsageTotals {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.copyTotals()
+}
+
+// Reset returns the accumulated totals and clears them, e.g. at the end of a billing period.
+func (c *UsageCollector) Reset() map[UsageKey]UsageTotals {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	snapshot := c.copyTotals()
+	c.totals = make(map[UsageKey]*UsageTotals)
+	return snapshot
+}
+
+// Total returns the sum of all accumulated totals.
+func (c *UsageCollector) Total() UsageTotals {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var sum UsageTotals
+	for _, totals := range c.totals {
+		sum.merge(totals)
+	}
+	return sum
+}
+
+// Internal method.
+// The caller must hold c.mu.
+func (c *UsageCollector) copyTotals() map[UsageKey]UsageTotals {
+	snapshot := make(map[UsageKey]UsageTotals, len(c.totals))
+	for key, totals := range c.totals {
+		copied := *totals
+		copied.InferenceTokens = maps.Clone(totals.InferenceTokens)
+		snapshot[key] = copied
+	}
+	return snapshot
+}
+
+// Internal method.
+func (t *UsageTotals) add(usage *Usage) {
+	t.Requests++
+	hardware := usage.GetHardware()
+	t.CPU += hardware.GetCpu()
+	t.PayloadIORead += hardware.GetPayloadIoRead()
+	t.PayloadIOWrite += hardware.GetPayloadIoWrite()
+	t.PayloadIndexIORead += hardware.GetPayloadIndexIoRead()
+	t.PayloadIndexIOWrite += hardware.GetPayloadIndexIoWrite()
+	t.VectorIORead += hardware.GetVectorIoRead()
+	t.VectorIOWrite += hardware.GetVectorIoWrite()
+	for model, modelUsage := range usage.GetInference().GetModels() {
+		if t.InferenceTokens == nil {
+			t.InferenceTokens = make(map[string]uint64)
+		}
+		t.InferenceTokens[model] += modelUsage.GetTokens()
+	}
+}
+
+// Internal method.
+func (t *UsageTotals) merge(other *UsageTotals) {
+	t.Requests += other.Requests
+	t.CPU += other.CPU
+	t.PayloadIORead += other.PayloadIORead
+	t.PayloadIOWrite += other.PayloadIOWrite
+	t.PayloadIndexIORead += other.PayloadIndexIORead
+	t.PayloadIndexIOWrite += other.PayloadIndexIOWrite
+	t.VectorIORead += other.VectorIORead
+	t.VectorIOWrite += other.VectorIOWrite
+	for model, tokens := range other.InferenceTokens {
+		if t.InferenceTokens == nil {
+			t.InferenceTokens = make(map[string]uint64)
+		}
+		t.InferenceTokens[model] += tokens
+	}
+}
+
+// WithUsageCollector returns a new context that records the usage of every request
+// made using that context in collector, in addition to Config.UsageCollector.
+// A collector that is also the Config.UsageCollector of the client records each request once.
+//
+//	usage := qdrant.NewUsageCollector()
+//	ctx = qdrant.WithUsageCollector(ctx, usage)
+//	client.Query(ctx, ...)
+func WithUsageCollector(ctx context.Context, collector *UsageCollector) context.Context {
+	return context.WithValue(ctx, usageCollectorContextKey{}, collector)
+}
+
+// WithUsageTenant returns a new context that records the usage of every request
+// made using that context under the given tenant label.
+//
+//	ctx = qdrant.WithUsageTenant(ctx, "team-search")
+//	client.Query(ctx, ...)
+func WithUsageTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, usageTenantContextKey{}, tenant)
+}
+
+// Internal method.
+func (c *Config) getUsageInterceptor() grpc.DialOption {
+	return grpc.WithChainUnaryInterceptor(func(
+		ctx context.Context,
+		method string,
+		req,
+		reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		fromContext, _ := ctx.Value(usageCollectorContextKey{}).(*UsageCollector)
+		if fromContext == c.UsageCollector {
+			// The same collector attached both ways counts each request once.
+			fromContext = nil
+		}
+		if err != nil || (c.UsageCollector == nil && fromContext == nil) {
+			return err
+		}
+		key := UsageKey{Operation: path.Base(method)}
+		key.Tenant, _ = ctx.Value(usageTenantContextKey{}).(string)
+		var usage *Usage
+		if msg, ok := req.(proto.Message); ok {
+			key.Collection = stringField(msg.ProtoReflect(), "collection_name")
+		}
+		if msg, ok := reply.(proto.Message); ok {
+			usage = usageField(msg)
+		}
+		for _, collector := range []*UsageCollector{c.UsageCollector, fromContext} {
+			if collector != nil {
+				collector.Record(key, usage)
+			}
+		}
+		return nil
+	})
+}
+
+// Internal method.
+// Returns the Usage of a response, or nil if the response has none.
+func usageField(reply proto.Message) *Usage {
+	msg := reply.ProtoReflect()
+	fd := msg.Descriptor().Fields().ByName("usage")
+	if fd == nil || !msg.Has(fd) {
+		return nil
+	}
+	usage, _ := msg.Get(fd).Message().Interface().(*Usage)
+	return usage
+}
@@ -0,0 +1,335 @@
+// This file contains helpers to convert vectors to the storage datatypes of Qdrant,
+// and the client-side normalization of the vectors of collections using the Cosine distance.
+// https://qdrant.tech/documentation/concepts/vectors/#datatypes
+
+package qdrant
+
+import (
+	"context"
+	"math"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Layout of IEEE 754 single and half precision floats.
+const (
+	float32ExpBias   = 127
+	float32MantBits  = 23
+	float32ExpMask   = 0xff
+	float32MantMask  = 0x7fffff
+	float32Implicit  = 0x800000
+	float16ExpBias   = 15
+	float16MantBits  = 10
+	float16ExpMax    = 0x1f
+	float16MantMask  = 0x3ff
+	float16SignMask  = 0x8000
+	float16Inf       = 0x7c00
+	float16NaN       = 0x7e00
+	float16MinExp    = -24
+	float16SignShift = 16
+	mantBitsDiff     = float32MantBits - float16MantBits
+)
+
+// NormalizeVector returns a copy of vector scaled to a unit L2 norm,
+// as the server does for vectors using the Cosine distance.
+// A zero vector is returned unchanged.
+func NormalizeVector(vector []float32) []float32 {
+	normalized := append([]float32(nil), vector...)
+	normalizeInPlace(normalized)
+	return normalized
+}
+
+// NormalizeMultiVector returns a copy of a multi-vector in which every vector is normalized with NormalizeVector.
+func NormalizeMultiVector(vectors [][]float32) [][]float32 {
+	normalized := make([][]float32, len(vectors))
+	for i, vector := range vectors {
+		normalized[i] = NormalizeVector(vector)
+	}
+	return normalized
+}
+
+// QuantizeUint8 scales the values of vector by scale and rounds them to the nearest integer in [0, 255],
+// so that the result can be stored in a vector with the Uint8 datatype.
+// Values are clamped to the range, e.g. negative values become 0.
+func QuantizeUint8(vector []float32, scale float32) []float32 {
+	quantized := make([]float32, len(vector))
+	for i, value := range vector {
+		quantized[i] = float32(math.Min(math.Max(math.Round(float64(value*scale)), 0), math.MaxUint8))
+	}
+	return quantized
+}
+
+// DequantizeUint8 reverts QuantizeUint8, dividing the values of vector by scale.
+func DequantizeUint8(vector []float32, scale float32) []float32 {
+	dequantized := make([]float32, len(vector))
+	for i, value := range vector {
+		dequantized[i] = value / scale
+	}
+	return dequantized
+}
+
+// Float32ToFloat16 returns the bits of the half precision float nearest to value, rounding ties to even.
+// Values too large for a half precision float become infinities.
+func Float32ToFloat16(value float32) uint16 {
+	bits := math.Float32bits(value)
+	sign := uint16(bits>>float16SignShift) & float16SignMask
+	exp := int(bits>>float32MantBits) & float32ExpMask
+	mant := bits & float32MantMask
+	if exp == float32ExpMask {
+		if mant != 0 {
+			return sign | float16NaN
+		}
+		return sign | float16Inf
+	}
+	exp = exp - float32ExpBias + float16ExpBias
+	if exp >= float16ExpMax {
+		return sign | float16Inf
+	}
+	if exp <= 0 {
+		// Subnormal half precision float, or zero.
+		if exp < -float16MantBits {
+			return sign
+		}
+		return sign | uint16(roundShift(mant|float32Implicit, uint(mantBitsDiff+1-exp)))
+	}
+	// A carry out of the mantissa correctly increments the exponent, up to infinity.
+	return sign | uint16(uint32(exp)<<float16MantBits+roundShift(mant, mantBitsDiff))
+}
+
+// Float16ToFloat32 returns the value of the half precision float with the given bits.
+func Float16ToFloat32(bits uint16) float32 {
+	sign := uint32(bits&float16SignMask) << float16SignShift
+	exp := uint32(bits>>float16MantBits) & float16ExpMax
+	mant := uint32(bits & float16MantMask)
+	switch exp {
+	case float16ExpMax:
+		return math.Float32frombits(sign | float32ExpMask<<float32MantBits | mant<<mantBitsDiff)
+	case 0:
+		value := float32(math.Ldexp(float64(mant), float16MinExp))
+		if sign != 0 {
+			return -value
+		}
+		return value
+	}
+	return math.Float32frombits(sign | (exp-float16ExpBias+float32ExpBias)<<float32MantBits | mant<<mantBitsDiff)
+}
+
+// EncodeFloat16 converts vector to half precision floats with Float32ToFloat16.
+func EncodeFloat16(vector []float32) []uint16 {
+	encoded := make([]uint16, len(vector))
+	for i, value := range vector {
+		encoded[i] = Float32ToFloat16(value)
+	}
+	return encoded
+}
+
+// DecodeFloat16 converts half precision floats to a vector with Float16ToFloat32.
+func DecodeFloat16(bits []uint16) []float32 {
+	decoded := make([]float32, len(bits))
+	for i, b := range bits {
+		decoded[i] = Float16ToFloat32(b)
+	}
+	return decoded
+}
+
+// RoundFloat16 returns a copy of vector with its values rounded to half precision,
+// i.e. the values stored, and returned, by a vector with the Float16 datatype.
+func RoundFloat16(vector []float32) []float32 {
+	rounded := make([]float32, len(vector))
+	for i, value := range vector {
+		rounded[i] = Float16ToFloat32(Float32ToFloat16(value))
+	}
+	return rounded
+}
+
+// Internal method.
+// Returns value shifted right by shift bits, rounding to nearest, ties to even.
+func roundShift(value uint32, shift uint) uint32 {
+	shifted := value >> shift
+	rem := value & (1<<shift - 1)
+	half := uint32(1) << (shift - 1)
+	if rem > half || (rem == half && shifted&1 == 1) {
+		shifted++
+	}
+	return shifted
+}
+
+// Internal method.
+func normalizeInPlace(vector []float32) {
+	var sum float64
+	for _, value := range vector {
+		sum += float64(value) * float64(value)
+	}
+	if sum == 0 {
+		return
+	}
+	norm := math.Sqrt(sum)
+	for i, value := range vector {
+		vector[i] = float32(float64(value) / norm)
+	}
+}
+
+// Internal method.
+// Returns a copy of request in which normalize has normalized the vectors for the Cosine distance,
+// or request itself if normalization is disabled or the collection has no Cosine vectors,
+// so that the caller's request is not modified.
+func normalizeRequest[T proto.Message](ctx context.Context, c *Client, collectionName string, request T,
+	normalize func(*collectionSchema, T),
+) (T, error) {
+	if !c.normalizeVectors {
+		return request, nil
+	}
+	schema, err := c.getCollectionSchema(ctx, collectionName)
+	if err != nil {
+		return request, err
+	}
+	if !schema.hasCosineVectors() {
+		return request, nil
+	}
+	normalized := proto.CloneOf(request)
+	normalize(schema, normalized)
+	return normalized, nil
+}
+
+// Internal method.
+func normalizeUpsert(s *collectionSchema, request *UpsertPoints) {
+	for _, point := range request.GetPoints() {
+		s.normalizeVectors(point.GetVectors())
+	}
+}
+
+// Internal method.
+func normalizeUpdateVectors(s *collectionSchema, request *UpdatePointVectors) {
+	for _, point := range request.GetPoints() {
+		s.normalizeVectors(point.GetVectors())
+	}
+}
+
+// Internal method.
+func normalizeUpdateBatch(s *collectionSchema, request *UpdateBatchPoints) {
+	for _, operation := range request.GetOperations() {
+		for _, point := range operation.GetUpsert().GetPoints() {
+			s.normalizeVectors(point.GetVectors())
+		}
+		for _, point := range operation.GetUpdateVectors().GetPoints() {
+			s.normalizeVectors(point.GetVectors())
+		}
+	}
+}
+
+// Internal method.
+func normalizeQuery(s *collectionSchema, request *QueryPoints) {
+	s.normalizeQuery(request.GetQuery(), request.GetUsing())
+	s.normalizePrefetch(request.GetPrefetch())
+}
+
+// Internal method.
+func normalizeQueryBatch(s *collectionSchema, request *QueryBatchPoints) {
+	for _, query := range request.GetQueryPoints() {
+		normalizeQuery(s, query)
+	}
+}
+
+// Internal method.
+func normalizeQueryGroups(s *collectionSchema, request *QueryPointGroups) {
+	s.normalizeQuery(request.GetQuery(), request.GetUsing())
+	s.normalizePrefetch(request.GetPrefetch())
+}
+
+// Internal method.
+func (s *collectionSchema) isCosine(name string) bool {
+	params, ok := s.vectors[name]
+	return ok && params.GetDistance() == Distance_Cosine
+}
+
+// Internal method.
+func (s *collectionSchema) hasCosineVectors() bool {
+	for name := range s.vectors {
+		if s.isCosine(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Internal method.
+func (s *collectionSchema) normalizeVectors(vectors *Vectors) {
+	if vector := vectors.GetVector(); vector != nil {
+		if s.isCosine("") {
+			normalizeVectorMessage(vector)
+		}
+		return
+	}
+	for name, vector := range vectors.GetVectors().GetVectors() {
+		if s.isCosine(name) {
+			normalizeVectorMessage(vector)
+		}
+	}
+}
+
+// Internal method.
+func (s *collectionSchema) normalizeQuery(query *Query, using string) {
+	if query != nil && s.isCosine(using) {
+		normalizeDenseVectors(query.ProtoReflect())
+	}
+}
+
+// Internal method.
+func (s *collectionSchema) normalizePrefetch(prefetch []*PrefetchQuery) {
+	for _, p := range prefetch {
+		s.normalizeQuery(p.GetQuery(), p.GetUsing())
+		s.normalizePrefetch(p.GetPrefetch())
+	}
+}
+
+// Internal method.
+// Normalizes a dense or multi-dense vector, also if it uses the deprecated fields.
+func normalizeVectorMessage(vector *Vector) {
+	if data := vector.GetData(); len(data) > 0 && vector.GetIndices() == nil {
+		count := int(vector.GetVectorsCount())
+		if count == 0 || len(data)%count != 0 {
+			count = 1
+		}
+		size := len(data) / count
+		for i := range count {
+			normalizeInPlace(data[i*size : (i+1)*size])
+		}
+		return
+	}
+	normalizeDenseVectors(vector.ProtoReflect())
+}
+
+// Internal method.
+// Normalizes every dense vector in msg, including the vectors of multi-vectors.
+func normalizeDenseVectors(msg protoreflect.Message) {
+	switch m := msg.Interface().(type) {
+	case *DenseVector:
+		normalizeInPlace(m.GetData())
+		return
+	case *Value, *Filter:
+		// Payloads and filters never contain vectors.
+		return
+	}
+	msg.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.Message() == nil:
+		case fd.IsList():
+			list := v.List()
+			for i := range list.Len() {
+				normalizeDenseVectors(list.Get(i).Message())
+			}
+		case fd.IsMap():
+			if fd.MapValue().Message() == nil {
+				return true
+			}
+			v.Map().Range(func(_ protoreflect.MapKey, value protoreflect.Value) bool {
+				normalizeDenseVectors(value.Message())
+				return true
+			})
+		default:
+			normalizeDenseVectors(v.Message())
+		}
+		return true
+	})
+}
@@ -0,0 +1,341 @@
+package qdrant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// Largest finite value of a half-precision float.
+const maxFloat16 = 65504
+
+// PointVectorError describes why the vectors of a point do not match the vectors config of a collection.
+type PointVectorError struct {
+	// Index of the operation in an UpdateBatch request, 0 otherwise.
+	Operation int
+	// Index of the point in the points of the request or operation.
+	Index int
+	// ID of the point.
+	ID *PointId
+	// Name of the invalid vector. Empty for the default vector.
+	Vector string
+	// Description of the issue.
+	Err error
+}
+
+// Error returns the error as string.
+func (e *PointVectorError) Error() string {
+	return fmt.Sprintf("point %d (id %s), vector %q: %v", e.Index, e.ID, e.Vector, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *PointVectorError) Unwrap() error {
+	return e.Err
+}
+
+// VectorValidationError is returned when vector validation is enabled with Config.ValidateVectors,
+// or by ValidatePoints, and lists the invalid points of a request.
+// There is at most one error per point.
+type VectorValidationError struct {
+	Errors []*PointVectorError
+}
+
+// Error returns the error as string.
+func (e *VectorValidationError) Error() string {
+	errs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err.Error()
+	}
+	return fmt.Sprintf("invalid vectors: %s", strings.Join(errs, "; "))
+}
+
+// Indices returns the indices of the invalid points of the operation at index operation,
+// e.g. to drop them from the request and send the rest of the points.
+// Use 0 for requests other than UpdateBatch.
+func (e *VectorValidationError) Indices(operation int) []int {
+	var indices []int
+	for _, err := range e.Errors {
+		if err.Operation == operation {
+			indices = append(indices, err.Index)
+		}
+	}
+	return indices
+}
+
+// Validates the vectors of points against the vectors config of a collection,
+// before they are sent with Upsert.
+// The collection info is cached for Config.PayloadSchemaCacheTTL.
+//
+// It reports:
+//   - vector names missing from the vectors config of the collection,
+//   - dense vectors and vectors of multi-vectors whose size is not the configured size,
+//   - multi-vectors sent to a vector without multi-vector config, and vice versa,
+//   - sparse vectors with a different number of indices and values, or with duplicated indices,
+//   - values that cannot be stored with the uint8 or float16 datatype.
+//
+// Vectors inferred from a Document, Image or Object are not checked.
+//
+// Parameters:
+//   - ctx: The context for the request.
+//   - collectionName: The name of the collection to validate the points for.
+//   - points: The points to validate.
+//
+// Returns:
+//   - error: A *VectorValidationError listing the invalid points, or an error if fetching the collection info fails.
+func (c *Client) ValidatePoints(ctx context.Context, collectionName string, points []*PointStruct) error {
+	schema, err := c.getCollectionSchema(ctx, collectionName)
+	if err != nil {
+		return newQdrantErr(err, "ValidatePoints", collectionName)
+	}
+	if err := schema.validatePoints(0, points); err != nil {
+		return newQdrantErr(err, "ValidatePoints", collectionName)
+	}
+	return nil
+}
+
+// Internal method.
+// Validates the points of an Upsert request if vector validation is enabled.
+func (c *Client) validateUpsert(ctx context.Context, request *UpsertPoints) error {
+	if !c.validateVectors {
+		return nil
+	}
+	schema, err := c.getCollectionSchema(ctx, request.GetCollectionName())
+	if err != nil {
+		return err
+	}
+	return schema.validatePoints(0, request.GetPoints())
+}
+
+// Internal method.
+// Validates the points of an UpdateVectors request if vector validation is enabled.
+func (c *Client) validateUpdateVectors(ctx context.Context, request *UpdatePointVectors) error {
+	if !c.validateVectors {
+		return nil
+	}
+	schema, err := c.getCollectionSchema(ctx, request.GetCollectionName())
+	if err != nil {
+		return err
+	}
+	return schema.validatePointVectors(0, request.GetPoints())
+}
+
+// Internal method.
+// Validates the points of the upsert and update vectors operations of an UpdateBatch request
+// if vector validation is enabled.
+func (c *Client) validateUpdateBatch(ctx context.Context, request *UpdateBatchPoints) error {
+	if !c.validateVectors {
+		return nil
+	}
+	var schema *collectionSchema
+	var errs []*PointVectorError
+	for i, operation := range request.GetOperations() {
+		upsert, update := operation.GetUpsert(), operation.GetUpdateVectors()
+		if upsert == nil && update == nil {
+			continue
+		}
+		if schema == nil {
+			var err error
+			if schema, err = c.getCollectionSchema(ctx, request.GetCollectionName()); err != nil {
+				return err
+			}
+		}
+		var err error
+		if upsert != nil {
+			err = schema.validatePoints(i, upsert.GetPoints())
+		} else {
+			err = schema.validatePointVectors(i, update.GetPoints())
+		}
+		if validationErr := (*VectorValidationError)(nil); errors.As(err, &validationErr) {
+			errs = append(errs, validationErr.Errors...)
+		}
+	}
+	if len(errs) > 0 {
+		return &VectorValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// Internal method.
+func (s *collectionSchema) validatePoints(operation int, points []*PointStruct) error {
+	var errs []*PointVectorError
+	for i, point := range points {
+		if name, err := s.checkVectors(point.GetVectors()); err != nil {
+			errs = append(errs, &PointVectorError{
+				Operation: operation, Index: i, ID: point.GetId(), Vector: name, Err: err,
+			})
+		}
+	}
+	if len(errs) > 0 {
+		return &VectorValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// Internal method.
+func (s *collectionSchema) validatePointVectors(operation int, points []*PointVectors) error {
+	var errs []*PointVectorError
+	for i, point := range points {
+		if name, err := s.checkVectors(point.GetVectors()); err != nil {
+			errs = append(errs, &PointVectorError{
+				Operation: operation, Index: i, ID: point.GetId(), Vector: name, Err: err,
+			})
+		}
+	}
+	if len(errs) > 0 {
+		return &VectorValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// Internal method.
+// Returns the name of the first invalid vector and the reason.
+func (s *collectionSchema) checkVectors(vectors *Vectors) (string, error) {
+	if vector := vectors.GetVector(); vector != nil {
+		return "", s.checkVector("", vector)
+	}
+	named := vectors.GetVectors().GetVectors()
+	names := make([]string, 0, len(named))
+	for name := range named {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if err := s.checkVector(name, named[name]); err != nil {
+			return name, err
+		}
+	}
+	return "", nil
+}
+
+// Internal method.
+func (s *collectionSchema) checkVector(name string, vector *Vector) error {
+	switch vector.GetVector().(type) {
+	case *Vector_Document, *Vector_Image, *Vector_Object:
+		// Inferred by the server, or by an Embedder before sending.
+		if _, ok := s.vectors[name]; !ok {
+			if _, ok := s.sparseVectors[name]; !ok {
+				return errors.New("vector name is not in the vectors config of the collection")
+			}
+		}
+		return nil
+	}
+	if sparse := vector.sparseVector(); sparse != nil {
+		params, ok := s.sparseVectors[name]
+		if !ok {
+			return errors.New("sparse vector name is not in the sparse vectors config of the collection")
+		}
+		return checkSparseVector(sparse, params)
+	}
+	params, ok := s.vectors[name]
+	if !ok {
+		return errors.New("vector name is not in the vectors config of the collection")
+	}
+	if multi := vector.multiDenseVector(); multi != nil {
+		if params.GetMultivectorConfig() == nil {
+			return errors.New("got a multi-vector for a vector without multi-vector config")
+		}
+		if len(multi.GetVectors()) == 0 {
+			return errors.New("multi-vector is empty")
+		}
+		for i, dense := range multi.GetVectors() {
+			if err := checkDenseVector(dense.GetData(), params); err != nil {
+				return fmt.Errorf("vector %d: %w", i, err)
+			}
+		}
+		return nil
+	}
+	if params.GetMultivectorConfig() != nil {
+		return errors.New("got a dense vector for a multi-vector")
+	}
+	return checkDenseVector(vector.denseData(), params)
+}
+
+// Internal method.
+// Returns the sparse vector, also if it uses the deprecated fields.
+func (v *Vector) sparseVector() *SparseVector {
+	if sparse := v.GetSparse(); sparse != nil {
+		return sparse
+	}
+	if indices := v.GetIndices(); indices != nil {
+		return &SparseVector{Values: v.GetData(), Indices: indices.GetData()}
+	}
+	return nil
+}
+
+// Internal method.
+// Returns the multi-vector, also if it uses the deprecated fields.
+func (v *Vector) multiDenseVector() *MultiDenseVector {
+	if multi := v.GetMultiDense(); multi != nil {
+		return multi
+	}
+	count := int(v.GetVectorsCount())
+	if count == 0 || len(v.GetData())%count != 0 {
+		return nil
+	}
+	vectors, err := SplitMultiVector(v.GetData(), len(v.GetData())/count, nil)
+	if err != nil {
+		return nil
+	}
+	multi := &MultiDenseVector{Vectors: make([]*DenseVector, len(vectors))}
+	for i, data := range vectors {
+		multi.Vectors[i] = &DenseVector{Data: data}
+	}
+	return multi
+}
+
+// Internal method.
+// Returns the dense vector, also if it uses the deprecated fields.
+func (v *Vector) denseData() []float32 {
+	if dense := v.GetDense(); dense != nil {
+		return dense.GetData()
+	}
+	return v.GetData()
+}
+
+// Internal method.
+func checkDenseVector(data []float32, params *VectorParams) error {
+	if uint64(len(data)) != params.GetSize() {
+		return fmt.Errorf("got %d dimensions, expected %d", len(data), params.GetSize())
+	}
+	return checkDatatype(data, params.GetDatatype())
+}
+
+// Internal method.
+func checkSparseVector(vector *SparseVector, params *SparseVectorParams) error {
+	if len(vector.GetIndices()) != len(vector.GetValues()) {
+		return fmt.Errorf("got %d indices and %d values", len(vector.GetIndices()), len(vector.GetValues()))
+	}
+	seen := make(map[uint32]struct{}, len(vector.GetIndices()))
+	for _, index := range vector.GetIndices() {
+		if _, ok := seen[index]; ok {
+			return fmt.Errorf("index %d is duplicated", index)
+		}
+		seen[index] = struct{}{}
+	}
+	return checkDatatype(vector.GetValues(), params.GetIndex().GetDatatype())
+}
+
+// Internal method.
+func checkDatatype(data []float32, datatype Datatype) error {
+	for i, value := range data {
+		v := float64(value)
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("non-finite value at dimension %d", i)
+		}
+		switch datatype {
+		case Datatype_Uint8:
+			if v < 0 || v > math.MaxUint8 || v != math.Trunc(v) {
+				return fmt.Errorf("value %v at dimension %d is not a uint8", value, i)
+			}
+		case Datatype_Float16:
+			if math.Abs(v) > maxFloat16 {
+				return fmt.Errorf("value %v at dimension %d overflows a float16", value, i)
+			}
+		case Datatype_Default, Datatype_Float32:
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package qdrant_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/qdrant/go-client/qdrant"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestBatchBuilder(t *testing.T) {
+	collectionName := t.Name()
+	apiKey := "<BATCH_BUILDER_TEST>"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	container, err := standaloneQdrant(ctx, apiKey)
+	require.NoError(t, err)
+
+	err = container.Start(ctx)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		err := container.Terminate(ctx)
+		require.NoError(t, err)
+	})
+
+	host, err := container.Host(ctx)
+	require.NoError(t, err)
+
+	port, err := container.MappedPort(ctx, "6334/tcp")
+	require.NoError(t, err)
+
+	client, err := qdrant.NewClient(&qdrant.Config{
+		Host:   host,
+		Port:   int(port.Num()),
+		APIKey: apiKey,
+	})
+	require.NoError(t, err)
+
+	err = client.CreateCollection(ctx, &qdrant.CreateCollection{
+		CollectionName: collectionName,
+		VectorsConfig: qdrant.NewVectorsConfig(&qdrant.VectorParams{
+			Size:     4,
+			Distance: qdrant.Distance_Dot,
+		}),
+		StrictModeConfig: &qdrant.StrictModeConfig{
+			Enabled:            qdrant.PtrOf(true),
+			UpsertMaxBatchsize: qdrant.PtrOf(uint64(10)),
+		},
+	})
+	require.NoError(t, err)
+
+	points := make([]*qdrant.PointStruct, 25)
+	for i := range points {
+		points[i] = &qdrant.PointStruct{
+			Id:      qdrant.NewIDNum(uint64(i)),
+			Vectors: qdrant.NewVectors(1, 2, 3, 4),
+			Payload: qdrant.NewValueMap(map[string]any{"tag": "a"}),
+		}
+	}
+
+	results, err := client.NewBatchBuilder(collectionName).
+		WithWait(true).
+		Upsert(points...).
+		SetPayload(qdrant.NewPointsSelector(qdrant.NewIDNum(1)), qdrant.NewValueMap(map[string]any{"seen": true})).
+		DeletePayload(qdrant.NewPointsSelector(qdrant.NewIDNum(2)), "tag").
+		ClearPayload(qdrant.NewPointsSelector(qdrant.NewIDNum(3))).
+		UpdateVectors(&qdrant.PointVectors{Id: qdrant.NewIDNum(4), Vectors: qdrant.NewVectors(4, 3, 2, 1)}).
+		Delete(qdrant.NewPointsSelector(qdrant.NewIDNum(5))).
+		WithMaxRequestSize(1024).
+		Execute(ctx)
+	require.NoError(t, err)
+	require.Len(t, results, 6)
+	for _, result := range results {
+		require.Equal(t, qdrant.UpdateStatus_Completed, result.GetStatus())
+	}
+
+	count, err := client.Count(ctx, &qdrant.CountPoints{CollectionName: collectionName})
+	require.NoError(t, err)
+	require.Equal(t, uint64(24), count)
+
+	retrieved, err := client.Get(ctx, &qdrant.GetPoints{
+		CollectionName: collectionName,
+		Ids:            []*qdrant.PointId{qdrant.NewIDNum(1), qdrant.NewIDNum(2), qdrant.NewIDNum(3)},
+		WithPayload:    qdrant.NewWithPayload(true),
+	})
+	require.NoError(t, err)
+	require.Len(t, retrieved, 3)
+	require.True(t, retrieved[0].GetPayload()["seen"].GetBoolValue())
+	require.NotContains(t, retrieved[1].GetPayload(), "tag")
+	require.Empty(t, retrieved[2].GetPayload())
+
+	results, err = client.NewBatchBuilder(collectionName).Execute(ctx)
+	require.NoError(t, err)
+	require.Empty(t, results)
+}
+
+func TestBatchBuilderMaxRequestSize(t *testing.T) {
+	points := &fakePoints{}
+	client := fakeQdrant(t, fakeServices{points: points}, &qdrant.Config{MaxRequestSize: 1024})
+
+	builder := client.NewBatchBuilder("collection")
+	for i := range 50 {
+		builder.SetPayload(qdrant.NewPointsSelector(qdrant.NewIDNum(uint64(i))), qdrant.NewValueMap(map[string]any{
+			"description": strings.Repeat("x", 64),
+		}))
+	}
+	results, err := builder.Execute(context.Background())
+	require.NoError(t, err)
+	require.Len(t, results, 50)
+
+	// The builder uses the maximum request size of the client.
+	require.Greater(t, len(points.updateBatches), 1)
+	for _, request := range points.updateBatches {
+		require.LessOrEqual(t, proto.Size(request), 1024)
+	}
+}
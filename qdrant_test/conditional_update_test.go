@@ -0,0 +1,99 @@
+package qdrant_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qdrant/go-client/qdrant"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConditionalUpdates(t *testing.T) {
+	collectionName := t.Name()
+	apiKey := "<CONDITIONAL_UPDATES_TEST>"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	container, err := standaloneQdrant(ctx, apiKey)
+	require.NoError(t, err)
+
+	err = container.Start(ctx)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		err := container.Terminate(ctx)
+		require.NoError(t, err)
+	})
+
+	host, err := container.Host(ctx)
+	require.NoError(t, err)
+
+	port, err := container.MappedPort(ctx, "6334/tcp")
+	require.NoError(t, err)
+
+	client, err := qdrant.NewClient(&qdrant.Config{
+		Host:   host,
+		Port:   int(port.Num()),
+		APIKey: apiKey,
+	})
+	require.NoError(t, err)
+
+	err = client.CreateCollection(ctx, &qdrant.CreateCollection{
+		CollectionName: collectionName,
+		VectorsConfig: qdrant.NewVectorsConfig(&qdrant.VectorParams{
+			Size:     2,
+			Distance: qdrant.Distance_Dot,
+		}),
+	})
+	require.NoError(t, err)
+
+	point := func(status string, version int64) *qdrant.PointStruct {
+		return &qdrant.PointStruct{
+			Id:      qdrant.NewIDNum(1),
+			Vectors: qdrant.NewVectors(1, 2),
+			Payload: qdrant.NewValueMap(map[string]any{"status": status, "version": version}),
+		}
+	}
+
+	t.Run("UpdateIfExists", func(t *testing.T) {
+		applied, err := client.UpdateIfExists(ctx, collectionName, point("new", 0))
+		require.NoError(t, err)
+		require.False(t, applied)
+	})
+
+	t.Run("InsertIfAbsent", func(t *testing.T) {
+		applied, err := client.InsertIfAbsent(ctx, collectionName, point("new", 0))
+		require.NoError(t, err)
+		require.True(t, applied)
+
+		applied, err = client.InsertIfAbsent(ctx, collectionName, point("other", 0))
+		require.NoError(t, err)
+		require.False(t, applied)
+	})
+
+	t.Run("CompareAndSwap", func(t *testing.T) {
+		applied, err := client.CompareAndSwap(ctx, collectionName, point("running", 0), "version", 0)
+		require.NoError(t, err)
+		require.True(t, applied)
+
+		// A writer that read version 0 too does not overwrite the first write.
+		applied, err = client.CompareAndSwap(ctx, collectionName, point("failed", 0), "version", 0)
+		require.NoError(t, err)
+		require.False(t, applied)
+
+		points, err := client.Get(ctx, &qdrant.GetPoints{
+			CollectionName: collectionName,
+			Ids:            []*qdrant.PointId{qdrant.NewIDNum(1)},
+			WithPayload:    qdrant.NewWithPayload(true),
+		})
+		require.NoError(t, err)
+		require.Len(t, points, 1)
+		require.Equal(t, "running", points[0].GetPayload()["status"].GetStringValue())
+		require.Equal(t, int64(1), points[0].GetPayload()["version"].GetIntegerValue())
+
+		applied, err = client.UpdateIfExists(ctx, collectionName, point("done", 2))
+		require.NoError(t, err)
+		require.True(t, applied)
+	})
+}
@@ -0,0 +1,145 @@
+package qdrant_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/qdrant/go-client/qdrant"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbedder(t *testing.T) {
+	collectionName := t.Name()
+	apiKey := "<EMBEDDER_TEST>"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	container, err := standaloneQdrant(ctx, apiKey)
+	require.NoError(t, err)
+
+	err = container.Start(ctx)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		err := container.Terminate(ctx)
+		require.NoError(t, err)
+	})
+
+	host, err := container.Host(ctx)
+	require.NoError(t, err)
+
+	port, err := container.MappedPort(ctx, "6334/tcp")
+	require.NoError(t, err)
+
+	// Embeds the texts "a", "b" and "c" as one-hot vectors.
+	var calls atomic.Int32
+	oneHot := qdrant.EmbedderFunc(func(_ context.Context, model string, inputs []*qdrant.EmbeddingInput,
+	) ([]*qdrant.Vector, error) {
+		calls.Add(1)
+		vectors := make([]*qdrant.Vector, len(inputs))
+		for i, input := range inputs {
+			vector := make([]float32, 4)
+			vector[input.Document.GetText()[0]-'a'] = 1
+			vectors[i] = qdrant.NewVectorDense(vector)
+		}
+		return vectors, nil
+	})
+	failing := qdrant.EmbedderFunc(func(context.Context, string, []*qdrant.EmbeddingInput) ([]*qdrant.Vector, error) {
+		return nil, errors.New("model unavailable")
+	})
+
+	client, err := qdrant.NewClient(&qdrant.Config{
+		Host:               host,
+		Port:               int(port.Num()),
+		APIKey:             apiKey,
+		EmbeddingBatchSize: 2,
+		Embedders: map[string]qdrant.Embedder{
+			"one-hot": oneHot,
+			"failing": failing,
+		},
+	})
+	require.NoError(t, err)
+
+	err = client.CreateCollection(ctx, &qdrant.CreateCollection{
+		CollectionName: collectionName,
+		VectorsConfig: qdrant.NewVectorsConfig(&qdrant.VectorParams{
+			Size:     4,
+			Distance: qdrant.Distance_Dot,
+		}),
+	})
+	require.NoError(t, err)
+
+	t.Run("Upsert", func(t *testing.T) {
+		var points []*qdrant.PointStruct
+		for i, text := range []string{"a", "b", "c"} {
+			points = append(points, &qdrant.PointStruct{
+				Id:      qdrant.NewIDNum(uint64(i + 1)),
+				Vectors: qdrant.NewVectorsDocument(&qdrant.Document{Text: text, Model: "one-hot"}),
+			})
+		}
+		request := &qdrant.UpsertPoints{
+			CollectionName: collectionName,
+			Wait:           qdrant.PtrOf(true),
+			Points:         points,
+		}
+		_, err := client.Upsert(ctx, request)
+		require.NoError(t, err)
+		// 3 inputs in batches of 2.
+		require.Equal(t, int32(2), calls.Load())
+		// The caller's request is left untouched.
+		require.Equal(t, "a", request.GetPoints()[0].GetVectors().GetVector().GetDocument().GetText())
+	})
+
+	t.Run("Query", func(t *testing.T) {
+		points, err := client.Query(ctx, &qdrant.QueryPoints{
+			CollectionName: collectionName,
+			Prefetch: []*qdrant.PrefetchQuery{{
+				Query: qdrant.NewQueryDocument(&qdrant.Document{Text: "b", Model: "one-hot"}),
+				Limit: qdrant.PtrOf(uint64(10)),
+			}},
+			Query: qdrant.NewQueryNearest(qdrant.NewVectorInputDocument(&qdrant.Document{Text: "b", Model: "one-hot"})),
+			Limit: qdrant.PtrOf(uint64(1)),
+		})
+		require.NoError(t, err)
+		require.Len(t, points, 1)
+		require.Equal(t, uint64(2), points[0].GetId().GetNum())
+	})
+
+	t.Run("UpdateBatch", func(t *testing.T) {
+		_, err := client.UpdateBatch(ctx, &qdrant.UpdateBatchPoints{
+			CollectionName: collectionName,
+			Wait:           qdrant.PtrOf(true),
+			Operations: []*qdrant.PointsUpdateOperation{
+				qdrant.NewPointsUpdateUpdateVectors(&qdrant.PointsUpdateOperation_UpdateVectors{
+					Points: []*qdrant.PointVectors{{
+						Id:      qdrant.NewIDNum(1),
+						Vectors: qdrant.NewVectorsDocument(&qdrant.Document{Text: "c", Model: "one-hot"}),
+					}},
+				}),
+			},
+		})
+		require.NoError(t, err)
+
+		points, err := client.Query(ctx, &qdrant.QueryPoints{
+			CollectionName: collectionName,
+			Query:          qdrant.NewQueryDocument(&qdrant.Document{Text: "c", Model: "one-hot"}),
+			Limit:          qdrant.PtrOf(uint64(2)),
+		})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []uint64{1, 3}, []uint64{points[0].GetId().GetNum(), points[1].GetId().GetNum()})
+	})
+
+	t.Run("EmbedderError", func(t *testing.T) {
+		_, err := client.Query(ctx, &qdrant.QueryPoints{
+			CollectionName: collectionName,
+			Query:          qdrant.NewQueryDocument(&qdrant.Document{Text: "a", Model: "failing"}),
+		})
+		require.ErrorContains(t, err, "model unavailable")
+	})
+
+	err = client.DeleteCollection(ctx, collectionName)
+	require.NoError(t, err)
+}
@@ -0,0 +1,93 @@
+package qdrant_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/qdrant/go-client/qdrant"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachedEmbedder(t *testing.T) {
+	ctx := context.Background()
+	var embedded []string
+	embedder := qdrant.EmbedderFunc(func(_ context.Context, _ string, inputs []*qdrant.EmbeddingInput,
+	) ([]*qdrant.Vector, error) {
+		vectors := make([]*qdrant.Vector, len(inputs))
+		for i, input := range inputs {
+			embedded = append(embedded, input.Document.GetText())
+			vectors[i] = qdrant.NewVectorDense([]float32{float32(len(input.Document.GetText()))})
+		}
+		return vectors, nil
+	})
+	cached := qdrant.NewCachedEmbedder(embedder, qdrant.NewLRUEmbeddingStore(10, 0))
+	document := func(text string, options map[string]any) *qdrant.EmbeddingInput {
+		return &qdrant.EmbeddingInput{Document: &qdrant.Document{
+			Text:    text,
+			Model:   "model",
+			Options: qdrant.NewValueMap(options),
+		}}
+	}
+
+	vectors, err := cached.Embed(ctx, "model", []*qdrant.EmbeddingInput{
+		document("a", nil), document("bb", nil), document("a", nil),
+	})
+	require.NoError(t, err)
+	require.Equal(t, []float32{1}, vectors[0].GetDense().GetData())
+	require.Equal(t, []float32{2}, vectors[1].GetDense().GetData())
+	require.Equal(t, []float32{1}, vectors[2].GetDense().GetData())
+	// Identical inputs are embedded once.
+	require.Equal(t, []string{"a", "bb"}, embedded)
+
+	// Inputs with other options or models are cached separately.
+	_, err = cached.Embed(ctx, "model", []*qdrant.EmbeddingInput{
+		document("a", nil), document("a", map[string]any{"lowercase": true}),
+	})
+	require.NoError(t, err)
+	_, err = cached.Embed(ctx, "other", []*qdrant.EmbeddingInput{document("a", nil)})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "bb", "a", "a"}, embedded)
+
+	// Options are compared by their canonical encoding, whatever the order of their keys.
+	_, err = cached.Embed(ctx, "model", []*qdrant.EmbeddingInput{
+		document("c", map[string]any{"nested": map[string]any{"x": 1, "y": []any{"z", 2.5}}, "lowercase": true}),
+		document("c", map[string]any{"lowercase": true, "nested": map[string]any{"y": []any{"z", 2.5}, "x": 1}}),
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "bb", "a", "a", "c"}, embedded)
+
+	// Inputs that cannot be encoded canonically are rejected.
+	_, err = cached.Embed(ctx, "model", []*qdrant.EmbeddingInput{document("\xff", nil)})
+	require.Error(t, err)
+
+	stats := cached.Stats()
+	require.Equal(t, qdrant.EmbeddingCacheStats{Hits: 3, Misses: 5}, stats)
+	require.InDelta(t, 3.0/8, stats.HitRatio(), 1e-9)
+}
+
+func TestLRUEmbeddingStore(t *testing.T) {
+	ctx := context.Background()
+	vector := qdrant.NewVectorDense([]float32{1})
+
+	store := qdrant.NewLRUEmbeddingStore(2, 0)
+	require.NoError(t, store.Set(ctx, "a", vector))
+	require.NoError(t, store.Set(ctx, "b", vector))
+	_, ok, err := store.Get(ctx, "a")
+	require.NoError(t, err)
+	require.True(t, ok)
+	// "b" is the least recently used.
+	require.NoError(t, store.Set(ctx, "c", vector))
+	require.Equal(t, 2, store.Len())
+	_, ok, _ = store.Get(ctx, "b")
+	require.False(t, ok)
+	_, ok, _ = store.Get(ctx, "a")
+	require.True(t, ok)
+
+	expiring := qdrant.NewLRUEmbeddingStore(2, 10*time.Millisecond)
+	require.NoError(t, expiring.Set(ctx, "a", vector))
+	time.Sleep(20 * time.Millisecond)
+	_, ok, _ = expiring.Get(ctx, "a")
+	require.False(t, ok)
+	require.Equal(t, 0, expiring.Len())
+}
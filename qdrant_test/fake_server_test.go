@@ -0,0 +1,106 @@
+package qdrant_test
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/qdrant/go-client/qdrant"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// fakeServices are the services served by fakeQdrant. Nil services are not registered.
+type fakeServices struct {
+	collections qdrant.CollectionsServer
+	points      qdrant.PointsServer
+}
+
+// fakeQdrant serves the given services in-process, for the tests that do not need a real Qdrant
+// instance, and returns a client connected to them configured with config.
+func fakeQdrant(t *testing.T, services fakeServices, config *qdrant.Config) *qdrant.Client {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	if services.collections != nil {
+		qdrant.RegisterCollectionsServer(server, services.collections)
+	}
+	if services.points != nil {
+		qdrant.RegisterPointsServer(server, services.points)
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	cfg := *config
+	cfg.Host = "127.0.0.1"
+	cfg.Port = listener.Addr().(*net.TCPAddr).Port
+	cfg.SkipCompatibilityCheck = true
+	client, err := qdrant.NewClient(&cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	return client
+}
+
+// fakeCollections serves GetCollectionInfo with a fixed collection info.
+type fakeCollections struct {
+	qdrant.UnimplementedCollectionsServer
+	info *qdrant.CollectionInfo
+}
+
+func (f *fakeCollections) Get(_ context.Context, _ *qdrant.GetCollectionInfoRequest,
+) (*qdrant.GetCollectionInfoResponse, error) {
+	return &qdrant.GetCollectionInfoResponse{Result: f.info}, nil
+}
+
+// fakePoints serves Get, Search and SearchGroups with no results, and records the Upsert and UpdateBatch requests.
+type fakePoints struct {
+	qdrant.UnimplementedPointsServer
+	mu            sync.Mutex
+	upserts       []*qdrant.UpsertPoints
+	updateBatches []*qdrant.UpdateBatchPoints
+}
+
+func (f *fakePoints) Get(_ context.Context, _ *qdrant.GetPoints) (*qdrant.GetResponse, error) {
+	return &qdrant.GetResponse{}, nil
+}
+
+func (f *fakePoints) Search(_ context.Context, _ *qdrant.SearchPoints) (*qdrant.SearchResponse, error) {
+	return &qdrant.SearchResponse{}, nil
+}
+
+func (f *fakePoints) SearchGroups(_ context.Context, _ *qdrant.SearchPointGroups,
+) (*qdrant.SearchGroupsResponse, error) {
+	return &qdrant.SearchGroupsResponse{}, nil
+}
+
+func (f *fakePoints) UpdateBatch(_ context.Context, request *qdrant.UpdateBatchPoints,
+) (*qdrant.UpdateBatchResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.updateBatches = append(f.updateBatches, request)
+	results := make([]*qdrant.UpdateResult, len(request.GetOperations()))
+	for i := range results {
+		results[i] = &qdrant.UpdateResult{Status: qdrant.UpdateStatus_Completed}
+	}
+	return &qdrant.UpdateBatchResponse{Result: results}, nil
+}
+
+func (f *fakePoints) Upsert(_ context.Context, request *qdrant.UpsertPoints) (*qdrant.PointsOperationResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.upserts = append(f.upserts, request)
+	return &qdrant.PointsOperationResponse{
+		Result: &qdrant.UpdateResult{
+			OperationId: qdrant.PtrOf(uint64(len(f.upserts))),
+			Status:      qdrant.UpdateStatus_Completed,
+		},
+	}, nil
+}
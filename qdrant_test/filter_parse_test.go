@@ -0,0 +1,174 @@
+package qdrant_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qdrant/go-client/qdrant"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestParseFilter(t *testing.T) {
+	createdAt := timestamppb.New(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name     string
+		expr     string
+		expected *qdrant.Filter
+	}{
+		{
+			name:     "Empty",
+			expr:     "  ",
+			expected: &qdrant.Filter{},
+		},
+		{
+			name: "Conjunction",
+			expr: `city = "Berlin" AND price BETWEEN 10 AND 20 AND NOT tags IN ("a","b") AND created_at > "2024-01-01T00:00:00Z"`,
+			expected: &qdrant.Filter{
+				Must: []*qdrant.Condition{
+					qdrant.NewMatch("city", "Berlin"),
+					qdrant.NewRange("price", &qdrant.Range{Gte: qdrant.PtrOf(10.0), Lte: qdrant.PtrOf(20.0)}),
+					qdrant.NewDatetimeRange("created_at", &qdrant.DatetimeRange{Gt: createdAt}),
+				},
+				MustNot: []*qdrant.Condition{
+					qdrant.NewMatchKeywords("tags", "a", "b"),
+				},
+			},
+		},
+		{
+			name: "Precedence",
+			expr: `a = 1 or b = true and not c != "x"`,
+			expected: &qdrant.Filter{
+				Should: []*qdrant.Condition{
+					qdrant.NewMatchInt("a", 1),
+					qdrant.NewFilterAsCondition(&qdrant.Filter{
+						Must: []*qdrant.Condition{
+							qdrant.NewMatchBool("b", true),
+						},
+						MustNot: []*qdrant.Condition{
+							qdrant.NewFilterAsCondition(&qdrant.Filter{
+								MustNot: []*qdrant.Condition{qdrant.NewMatch("c", "x")},
+							}),
+						},
+					}),
+				},
+			},
+		},
+		{
+			name: "Operators",
+			expr: "(count >= -2.5 OR count < 1e3) AND tags EXCEPT (1, 2) AND discount IS NULL AND tags IS NOT EMPTY" +
+				` AND description TEXT "good" AND location NEAR (52.52, 13.405, 1000) AND HAS_ID (1, "5c56c793-69f3-4fbf-87e6-c4bf54c28c26")` +
+				" AND HAS_VECTOR \"image\" AND `first-name` = \"Ann\" AND country.cities[].population > 1000",
+			expected: &qdrant.Filter{
+				Must: []*qdrant.Condition{
+					qdrant.NewFilterAsCondition(&qdrant.Filter{
+						Should: []*qdrant.Condition{
+							qdrant.NewRange("count", &qdrant.Range{Gte: qdrant.PtrOf(-2.5)}),
+							qdrant.NewRange("count", &qdrant.Range{Lt: qdrant.PtrOf(1000.0)}),
+						},
+					}),
+					qdrant.NewMatchExceptInts("tags", 1, 2),
+					qdrant.NewIsNull("discount"),
+					qdrant.NewMatchText("description", "good"),
+					qdrant.NewGeoRadius("location", 52.52, 13.405, 1000),
+					qdrant.NewHasID(qdrant.NewIDNum(1), qdrant.NewID("5c56c793-69f3-4fbf-87e6-c4bf54c28c26")),
+					qdrant.NewHasVector("image"),
+					qdrant.NewMatch("first-name", "Ann"),
+					qdrant.NewRange("country.cities[].population", &qdrant.Range{Gt: qdrant.PtrOf(1000.0)}),
+				},
+				MustNot: []*qdrant.Condition{
+					qdrant.NewIsEmpty("tags"),
+				},
+			},
+		},
+		{
+			name: "Nested",
+			expr: `NESTED diet (food = "meat" AND liked = true)`,
+			expected: &qdrant.Filter{
+				Must: []*qdrant.Condition{
+					qdrant.NewNestedFilter("diet", &qdrant.Filter{
+						Must: []*qdrant.Condition{
+							qdrant.NewMatch("food", "meat"),
+							qdrant.NewMatchBool("liked", true),
+						},
+					}),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := qdrant.ParseFilter(tt.expr)
+			require.NoError(t, err)
+			require.True(t, proto.Equal(tt.expected, filter), "expected %v, got %v", tt.expected, filter)
+
+			formatted, err := qdrant.FormatFilter(filter)
+			require.NoError(t, err)
+			reparsed, err := qdrant.ParseFilter(formatted)
+			require.NoError(t, err, formatted)
+			require.True(t, proto.Equal(filter, reparsed), "formatted as %s", formatted)
+		})
+	}
+}
+
+func TestParseFilterSyntaxErrors(t *testing.T) {
+	tests := []struct {
+		expr   string
+		line   int
+		column int
+	}{
+		{`city = `, 1, 8},
+		{`city = "Berlin`, 1, 8},
+		{`city = "Berlin" AND`, 1, 20},
+		{`city == "Berlin"`, 1, 6},
+		{`(city = "Berlin"`, 1, 17},
+		{`price = 1.5`, 1, 9},
+		{`tags IN ("a", 1)`, 1, 15},
+		{`and = 1`, 1, 1},
+		{"city = \"Berlin\"\n  AND created_at > \"yesterday\"", 2, 20},
+		{`city = "Berlin" price > 1`, 1, 17},
+		// Non-ASCII digits are not numbers.
+		{`x = ٣`, 1, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := qdrant.ParseFilter(tt.expr)
+			var syntaxErr *qdrant.FilterSyntaxError
+			require.ErrorAs(t, err, &syntaxErr)
+			require.Equal(t, tt.line, syntaxErr.Line, syntaxErr.Error())
+			require.Equal(t, tt.column, syntaxErr.Column, syntaxErr.Error())
+		})
+	}
+}
+
+func TestFormatFilter(t *testing.T) {
+	filter := &qdrant.Filter{
+		Must: []*qdrant.Condition{
+			qdrant.NewMatch("city", "Berlin"),
+			qdrant.NewRange("price", &qdrant.Range{Gte: qdrant.PtrOf(10.0), Lte: qdrant.PtrOf(20.0)}),
+		},
+		Should: []*qdrant.Condition{
+			qdrant.NewMatchInt("rating", 5),
+			qdrant.NewMatchBool("featured", true),
+		},
+		MustNot: []*qdrant.Condition{
+			qdrant.NewMatchKeywords("tags", "a", "b"),
+		},
+	}
+	formatted, err := qdrant.FormatFilter(filter)
+	require.NoError(t, err)
+	require.Equal(t,
+		`city = "Berlin" AND price BETWEEN 10 AND 20 AND (rating = 5 OR featured = true) AND NOT tags IN ("a", "b")`,
+		formatted)
+
+	formatted, err = qdrant.FormatFilter(nil)
+	require.NoError(t, err)
+	require.Empty(t, formatted)
+
+	_, err = qdrant.FormatFilter(&qdrant.Filter{
+		Must: []*qdrant.Condition{qdrant.NewGeoBoundingBox("location", 1, 2, 3, 4)},
+	})
+	require.Error(t, err)
+}
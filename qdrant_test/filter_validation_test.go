@@ -0,0 +1,175 @@
+package qdrant_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qdrant/go-client/qdrant"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateFilter(t *testing.T) {
+	collectionName := t.Name()
+	apiKey := "<VALIDATE_FILTER_TEST>"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	container, err := standaloneQdrant(ctx, apiKey)
+	require.NoError(t, err)
+
+	err = container.Start(ctx)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		err := container.Terminate(ctx)
+		require.NoError(t, err)
+	})
+
+	host, err := container.Host(ctx)
+	require.NoError(t, err)
+
+	port, err := container.MappedPort(ctx, "6334/tcp")
+	require.NoError(t, err)
+
+	client, err := qdrant.NewClient(&qdrant.Config{
+		Host:   host,
+		Port:   int(port.Num()),
+		APIKey: apiKey,
+	})
+	require.NoError(t, err)
+
+	err = client.CreateCollection(ctx, &qdrant.CreateCollection{
+		CollectionName: collectionName,
+		VectorsConfig: qdrant.NewVectorsConfig(&qdrant.VectorParams{
+			Size:     4,
+			Distance: qdrant.Distance_Cosine,
+		}),
+		StrictModeConfig: &qdrant.StrictModeConfig{
+			Enabled:             qdrant.PtrOf(true),
+			FilterMaxConditions: qdrant.PtrOf(uint64(3)),
+			ConditionMaxSize:    qdrant.PtrOf(uint64(2)),
+		},
+	})
+	require.NoError(t, err)
+
+	for field, fieldType := range map[string]qdrant.FieldType{
+		"city":  qdrant.FieldType_FieldTypeKeyword,
+		"price": qdrant.FieldType_FieldTypeFloat,
+	} {
+		_, err = client.CreateFieldIndex(ctx, &qdrant.CreateFieldIndexCollection{
+			CollectionName: collectionName,
+			Wait:           qdrant.PtrOf(true),
+			FieldName:      field,
+			FieldType:      fieldType.Enum(),
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		err := client.ValidateFilter(ctx, collectionName, &qdrant.Filter{
+			Must: []*qdrant.Condition{
+				qdrant.NewMatch("city", "Berlin"),
+				qdrant.NewRange("price", &qdrant.Range{Lt: qdrant.PtrOf(10.0)}),
+				qdrant.NewHasID(qdrant.NewIDNum(1)),
+			},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		err := client.ValidateFilter(ctx, collectionName, &qdrant.Filter{
+			Must: []*qdrant.Condition{
+				qdrant.NewMatchKeywords("city", "Berlin", "Paris", "Rome"),
+				qdrant.NewRange("city", &qdrant.Range{Gt: qdrant.PtrOf(1.0)}),
+			},
+			Should: []*qdrant.Condition{
+				qdrant.NewMatchText("city", "berlin"),
+				qdrant.NewMatch("country", "Germany"),
+			},
+		})
+		var validationErr *qdrant.FilterValidationError
+		require.ErrorAs(t, err, &validationErr)
+		require.Equal(t, []qdrant.FilterIssue{
+			{Path: "must[0]", Key: "city", Reason: "match any condition has 3 values, more than the condition_max_size of 2"},
+			{Path: "must[1]", Key: "city", Reason: "range condition is not supported by a keyword index"},
+			{Path: "should[0]", Key: "city", Reason: "full-text match condition is not supported by a keyword index"},
+			{Path: "should[1]", Key: "country", Reason: "field condition on a key without a payload index"},
+			{Path: "filter", Reason: "has 4 conditions, more than the filter_max_conditions of 3"},
+		}, validationErr.Issues)
+	})
+
+	t.Run("Cached", func(t *testing.T) {
+		filter := &qdrant.Filter{
+			Must: []*qdrant.Condition{qdrant.NewMatch("country", "Germany")},
+		}
+		_, err := client.CreateFieldIndex(ctx, &qdrant.CreateFieldIndexCollection{
+			CollectionName: collectionName,
+			Wait:           qdrant.PtrOf(true),
+			FieldName:      "country",
+			FieldType:      qdrant.FieldType_FieldTypeKeyword.Enum(),
+		})
+		require.NoError(t, err)
+		require.Error(t, client.ValidateFilter(ctx, collectionName, filter))
+
+		client.InvalidateCollectionSchema(collectionName)
+		require.NoError(t, client.ValidateFilter(ctx, collectionName, filter))
+	})
+
+	err = client.DeleteCollection(ctx, collectionName)
+	require.NoError(t, err)
+}
+
+func TestValidateFilterSchemas(t *testing.T) {
+	ctx := context.Background()
+	collections := &fakeCollections{info: &qdrant.CollectionInfo{
+		PayloadSchema: map[string]*qdrant.PayloadSchemaInfo{
+			"city":  {DataType: qdrant.PayloadSchemaType_Keyword},
+			"count": {DataType: qdrant.PayloadSchemaType_Integer},
+			"year": {
+				DataType: qdrant.PayloadSchemaType_Integer,
+				Params: &qdrant.PayloadIndexParams{
+					IndexParams: &qdrant.PayloadIndexParams_IntegerIndexParams{
+						IntegerIndexParams: &qdrant.IntegerIndexParams{Range: qdrant.PtrOf(false)},
+					},
+				},
+			},
+		},
+	}}
+	client := fakeQdrant(t, fakeServices{collections: collections}, &qdrant.Config{})
+
+	t.Run("WithoutStrictMode", func(t *testing.T) {
+		err := client.ValidateFilter(ctx, "collection", &qdrant.Filter{
+			Must: []*qdrant.Condition{
+				qdrant.NewMatchKeywords("city", "Berlin", "Paris", "Rome"),
+				qdrant.NewHasID(qdrant.NewIDNum(1), qdrant.NewIDNum(2)),
+			},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("IndexWithoutParams", func(t *testing.T) {
+		err := client.ValidateFilter(ctx, "collection", &qdrant.Filter{
+			Must: []*qdrant.Condition{
+				qdrant.NewMatchInt("count", 1),
+				qdrant.NewRange("count", &qdrant.Range{Gt: qdrant.PtrOf(1.0)}),
+			},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		err := client.ValidateFilter(ctx, "collection", &qdrant.Filter{
+			Must: []*qdrant.Condition{
+				qdrant.NewRange("city", &qdrant.Range{Gt: qdrant.PtrOf(1.0)}),
+				qdrant.NewRange("year", &qdrant.Range{Gt: qdrant.PtrOf(2000.0)}),
+			},
+		})
+		var validationErr *qdrant.FilterValidationError
+		require.ErrorAs(t, err, &validationErr)
+		require.Equal(t, []qdrant.FilterIssue{
+			{Path: "must[0]", Key: "city", Reason: "range condition is not supported by a keyword index"},
+			{Path: "must[1]", Key: "year", Reason: "range on an integer index with range disabled"},
+		}, validationErr.Issues)
+	})
+}
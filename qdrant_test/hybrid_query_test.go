@@ -0,0 +1,120 @@
+package qdrant_test
+
+import (
+	"testing"
+
+	"github.com/qdrant/go-client/qdrant"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHybridQueryBuilder(t *testing.T) {
+	dense := []float32{0.1, 0.2, 0.3}
+	indices := []uint32{1, 42}
+	values := []float32{0.5, 0.7}
+	filter := &qdrant.Filter{Must: []*qdrant.Condition{qdrant.NewMatch("city", "Berlin")}}
+
+	t.Run("DenseSparseRRF", func(t *testing.T) {
+		request, err := qdrant.NewHybridQuery("books").
+			Dense("dense", dense, 100).
+			Sparse("bm25", indices, values, 50).
+			FuseRRF(60).
+			Filter(filter).
+			Limit(10).
+			WithPayload(qdrant.NewWithPayload(true)).
+			Build()
+		require.NoError(t, err)
+
+		require.Equal(t, "books", request.GetCollectionName())
+		require.Equal(t, uint64(10), request.GetLimit())
+		require.Equal(t, uint32(60), request.GetQuery().GetRrf().GetK())
+		require.Same(t, filter, request.GetFilter())
+		require.True(t, request.GetWithPayload().GetEnable())
+
+		require.Len(t, request.GetPrefetch(), 2)
+		denseFetch, sparseFetch := request.GetPrefetch()[0], request.GetPrefetch()[1]
+		require.Equal(t, "dense", denseFetch.GetUsing())
+		require.Equal(t, uint64(100), denseFetch.GetLimit())
+		require.Equal(t, dense, denseFetch.GetQuery().GetNearest().GetDense().GetData())
+		require.Same(t, filter, denseFetch.GetFilter())
+		require.Equal(t, "bm25", sparseFetch.GetUsing())
+		require.Equal(t, uint64(50), sparseFetch.GetLimit())
+		require.Equal(t, indices, sparseFetch.GetQuery().GetNearest().GetSparse().GetIndices())
+		require.Equal(t, values, sparseFetch.GetQuery().GetNearest().GetSparse().GetValues())
+		require.Same(t, filter, sparseFetch.GetFilter())
+	})
+
+	t.Run("DefaultRRF", func(t *testing.T) {
+		request, err := qdrant.NewHybridQuery("books").
+			Dense("", dense, 20).
+			Sparse("bm25", indices, values, 20).
+			FuseRRF(0).
+			Build()
+		require.NoError(t, err)
+		require.Equal(t, qdrant.Fusion_RRF, request.GetQuery().GetFusion())
+		require.Nil(t, request.GetPrefetch()[0].Using)
+	})
+
+	t.Run("DBSF", func(t *testing.T) {
+		request, err := qdrant.NewHybridQuery("books").
+			Dense("dense", dense, 20).
+			Sparse("bm25", indices, values, 20).
+			FuseDBSF().
+			Build()
+		require.NoError(t, err)
+		require.Equal(t, qdrant.Fusion_DBSF, request.GetQuery().GetFusion())
+	})
+
+	t.Run("SinglePrefetchWithoutFusion", func(t *testing.T) {
+		request, err := qdrant.NewHybridQuery("books").
+			Dense("dense", dense, 5).
+			Build()
+		require.NoError(t, err)
+		require.Empty(t, request.GetPrefetch())
+		require.Equal(t, "dense", request.GetUsing())
+		require.Equal(t, uint64(5), request.GetLimit())
+		require.Equal(t, dense, request.GetQuery().GetNearest().GetDense().GetData())
+	})
+
+	t.Run("WeightedRRF", func(t *testing.T) {
+		request, err := qdrant.NewHybridQuery("books").
+			Dense("dense", dense, 20).
+			Sparse("bm25", indices, values, 20).
+			FuseWeightedRRF(0, 2, 1).
+			Build()
+		require.NoError(t, err)
+		require.Equal(t, []float32{2, 1}, request.GetQuery().GetRrf().GetWeights())
+	})
+
+	invalid := map[string]*qdrant.HybridQueryBuilder{
+		"EmptyCollection": qdrant.NewHybridQuery("").
+			Dense("dense", dense, 20).Sparse("bm25", indices, values, 20).FuseRRF(0),
+		"NoPrefetch": qdrant.NewHybridQuery("books").FuseRRF(0),
+		"FusionWithSinglePrefetch": qdrant.NewHybridQuery("books").
+			Dense("dense", dense, 20).FuseRRF(0),
+		"MultiplePrefetchesWithoutFusion": qdrant.NewHybridQuery("books").
+			Dense("dense", dense, 20).Sparse("bm25", indices, values, 20),
+		"FusionSetTwice": qdrant.NewHybridQuery("books").
+			Dense("dense", dense, 20).Sparse("bm25", indices, values, 20).FuseRRF(0).FuseDBSF(),
+		"PrefetchLimitBelowLimit": qdrant.NewHybridQuery("books").
+			Dense("dense", dense, 20).Sparse("bm25", indices, values, 5).FuseRRF(0).Limit(10),
+		"PrefetchLimitBelowLimitPlusOffset": qdrant.NewHybridQuery("books").
+			Dense("dense", dense, 20).Sparse("bm25", indices, values, 20).FuseRRF(0).Limit(10).Offset(15),
+		"ZeroPrefetchLimit": qdrant.NewHybridQuery("books").
+			Dense("dense", dense, 0).Sparse("bm25", indices, values, 20).FuseRRF(0),
+		"EmptyDenseVector": qdrant.NewHybridQuery("books").
+			Dense("dense", nil, 20).Sparse("bm25", indices, values, 20).FuseRRF(0),
+		"SparseLengthMismatch": qdrant.NewHybridQuery("books").
+			Dense("dense", dense, 20).Sparse("bm25", indices, values[:1], 20).FuseRRF(0),
+		"WeightsMismatch": qdrant.NewHybridQuery("books").
+			Dense("dense", dense, 20).Sparse("bm25", indices, values, 20).FuseWeightedRRF(0, 1),
+		"PrefetchWithoutLimit": qdrant.NewHybridQuery("books").
+			Dense("dense", dense, 20).Prefetch(&qdrant.PrefetchQuery{Query: qdrant.NewQuery(0.1)}).FuseRRF(0),
+	}
+	for name, builder := range invalid {
+		t.Run(name, func(t *testing.T) {
+			request, err := builder.Build()
+			require.Error(t, err)
+			require.Nil(t, request)
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package qdrant_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/qdrant/go-client/qdrant"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestLogging(t *testing.T) {
+	collectionName := t.Name()
+	apiKey := "<REQUEST_LOGGING_TEST>"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	container, err := standaloneQdrant(ctx, apiKey)
+	require.NoError(t, err)
+
+	err = container.Start(ctx)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		err := container.Terminate(ctx)
+		require.NoError(t, err)
+	})
+
+	host, err := container.Host(ctx)
+	require.NoError(t, err)
+
+	port, err := container.MappedPort(ctx, "6334/tcp")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	client, err := qdrant.NewClient(&qdrant.Config{
+		Host:   host,
+		Port:   int(port.Num()),
+		APIKey: apiKey,
+		RequestLogger: &qdrant.RequestLogConfig{
+			Logger:         slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+			IncludeRequest: true,
+			IncludeHeaders: true,
+		},
+	})
+	require.NoError(t, err)
+
+	readRecords := func(t *testing.T) []map[string]any {
+		t.Helper()
+		var records []map[string]any
+		for line := range strings.Lines(buf.String()) {
+			var record map[string]any
+			require.NoError(t, json.Unmarshal([]byte(line), &record))
+			records = append(records, record)
+		}
+		buf.Reset()
+		return records
+	}
+
+	err = client.CreateCollection(ctx, &qdrant.CreateCollection{
+		CollectionName: collectionName,
+		VectorsConfig: qdrant.NewVectorsConfig(&qdrant.VectorParams{
+			Size:     4,
+			Distance: qdrant.Distance_Dot,
+		}),
+	})
+	require.NoError(t, err)
+	buf.Reset()
+
+	t.Run("SuccessfulRequest", func(t *testing.T) {
+		_, err := client.Upsert(ctx, &qdrant.UpsertPoints{
+			CollectionName: collectionName,
+			Wait:           qdrant.PtrOf(true),
+			Points: []*qdrant.PointStruct{
+				{Id: qdrant.NewIDNum(1), Vectors: qdrant.NewVectors(0.125, 0.25, 0.375, 0.5)},
+				{Id: qdrant.NewIDNum(2), Vectors: qdrant.NewVectors(0.125, 0.25, 0.375, 0.5)},
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = client.Query(ctx, &qdrant.QueryPoints{
+			CollectionName: collectionName,
+			Query:          qdrant.NewQuery(0.125, 0.25, 0.375, 0.5),
+		})
+		require.NoError(t, err)
+
+		records := readRecords(t)
+		require.Len(t, records, 2)
+		for _, record := range records {
+			require.Equal(t, "DEBUG", record["level"])
+			require.Equal(t, collectionName, record["collection"])
+			require.Equal(t, "OK", record["code"])
+			require.NotZero(t, record["requestSize"])
+			// Neither the API key nor the vectors are logged.
+			require.NotContains(t, record["request"], "0.375")
+			require.Equal(t, map[string]any{"api-key": "[REDACTED]"}, record["headers"])
+		}
+		require.Equal(t, "/qdrant.Points/Query", records[1]["method"])
+		require.InDelta(t, 2, records[1]["resultCount"], 0)
+	})
+
+	t.Run("FailedRequest", func(t *testing.T) {
+		_, err := client.Query(ctx, &qdrant.QueryPoints{
+			CollectionName: "nonexistent",
+		})
+		require.Error(t, err)
+
+		records := readRecords(t)
+		require.Len(t, records, 1)
+		require.Equal(t, "ERROR", records[0]["level"])
+		require.Equal(t, "NotFound", records[0]["code"])
+		require.NotEmpty(t, records[0]["err"])
+	})
+
+	err = client.DeleteCollection(ctx, collectionName)
+	require.NoError(t, err)
+}
+
+func TestRequestLoggingRedactsSearchVectors(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	client := fakeQdrant(t, fakeServices{points: &fakePoints{}}, &qdrant.Config{
+		RequestLogger: &qdrant.RequestLogConfig{
+			Logger:         slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+			IncludeRequest: true,
+		},
+	})
+
+	_, err := client.GetPointsClient().Search(ctx, &qdrant.SearchPoints{
+		CollectionName: "collection",
+		Vector:         []float32{0.125, 0.25, 0.375, 0.5},
+		Limit:          10,
+	})
+	require.NoError(t, err)
+	_, err = client.GetPointsClient().SearchGroups(ctx, &qdrant.SearchPointGroups{
+		CollectionName: "collection",
+		Vector:         []float32{0.125, 0.25, 0.375, 0.5},
+		GroupBy:        "document",
+		Limit:          10,
+		GroupSize:      2,
+	})
+	require.NoError(t, err)
+
+	lines := slices.Collect(strings.Lines(buf.String()))
+	require.Len(t, lines, 2)
+	for _, line := range lines {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		require.Contains(t, record["request"], "collection")
+		require.NotContains(t, record["request"], "0.375")
+	}
+}
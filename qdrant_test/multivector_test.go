@@ -0,0 +1,86 @@
+package qdrant_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/qdrant/go-client/qdrant"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitMultiVector(t *testing.T) {
+	vectors, err := qdrant.SplitMultiVector([]float32{1, 2, 3, 4, 5, 6}, 2, nil)
+	require.NoError(t, err)
+	require.Equal(t, [][]float32{{1, 2}, {3, 4}, {5, 6}}, vectors)
+
+	vectors, err = qdrant.SplitMultiVector([]float32{1, 2, 3, 4, 0, 0}, 2, []bool{true, true, false})
+	require.NoError(t, err)
+	require.Equal(t, [][]float32{{1, 2}, {3, 4}}, vectors)
+
+	_, err = qdrant.SplitMultiVector([]float32{1, 2, 3}, 2, nil)
+	require.Error(t, err)
+	_, err = qdrant.SplitMultiVector([]float32{1, 2}, 2, []bool{true, false})
+	require.Error(t, err)
+
+	multi := qdrant.NewVectorMulti(vectors).GetMultiDense()
+	require.Equal(t, vectors, multi.GetData())
+}
+
+func TestValidateMultiVector(t *testing.T) {
+	params := &qdrant.VectorParams{
+		Size:     2,
+		Distance: qdrant.Distance_Cosine,
+		MultivectorConfig: &qdrant.MultiVectorConfig{
+			Comparator: qdrant.MultiVectorComparator_MaxSim,
+		},
+	}
+	require.NoError(t, qdrant.ValidateMultiVector([][]float32{{1, 2}, {3, 4}}, params))
+	require.ErrorContains(t, qdrant.ValidateMultiVector([][]float32{{1, 2}, {3}}, params), "vector 1 has 1 dimensions")
+	require.ErrorContains(t, qdrant.ValidateMultiVector(nil, params), "empty")
+	require.ErrorContains(t, qdrant.ValidateMultiVector([][]float32{{1, float32(math.NaN())}}, params), "non-finite")
+	require.ErrorContains(t, qdrant.ValidateMultiVector([][]float32{{1, 2}}, &qdrant.VectorParams{Size: 2}),
+		"not configured as a multi-vector")
+}
+
+func TestMaxSim(t *testing.T) {
+	query := [][]float32{{1, 0}, {0, 1}}
+	document := [][]float32{{1, 0}, {0.5, 0.5}}
+
+	// Best matches: {1, 0} for both query vectors.
+	require.InDelta(t, 1+0.5, qdrant.MaxSim(query, document, qdrant.Distance_Dot), 1e-6)
+	require.InDelta(t, 1+math.Sqrt(0.5), qdrant.MaxSim(query, document, qdrant.Distance_Cosine), 1e-6)
+	require.InDelta(t, 0-math.Sqrt(0.5), qdrant.MaxSim(query, document, qdrant.Distance_Euclid), 1e-6)
+	require.InDelta(t, 0-1, qdrant.MaxSim(query, document, qdrant.Distance_Manhattan), 1e-6)
+	require.Zero(t, qdrant.MaxSim(query, nil, qdrant.Distance_Dot))
+}
+
+func TestRerankMaxSim(t *testing.T) {
+	point := func(id uint64, vectors [][]float32) *qdrant.RetrievedPoint {
+		return &qdrant.RetrievedPoint{
+			Id: qdrant.NewIDNum(id),
+			Vectors: &qdrant.VectorsOutput{VectorsOptions: &qdrant.VectorsOutput_Vectors{
+				Vectors: &qdrant.NamedVectorsOutput{Vectors: map[string]*qdrant.VectorOutput{
+					"colbert": {Vector: &qdrant.VectorOutput_MultiDense{
+						MultiDense: qdrant.NewVectorMulti(vectors).GetMultiDense(),
+					}},
+				}},
+			}},
+		}
+	}
+	points := []*qdrant.RetrievedPoint{
+		point(1, [][]float32{{0, 1}}),
+		point(2, [][]float32{{1, 0}, {0, 1}}),
+		point(3, [][]float32{{1, 0}}),
+	}
+
+	scored, err := qdrant.RerankMaxSim([][]float32{{1, 0}, {1, 0}, {0, 1}}, points, "colbert", qdrant.Distance_Dot)
+	require.NoError(t, err)
+	require.Len(t, scored, 3)
+	require.Equal(t, uint64(2), scored[0].GetId().GetNum())
+	require.InDelta(t, 3, scored[0].GetScore(), 1e-6)
+	require.Equal(t, uint64(3), scored[1].GetId().GetNum())
+	require.Equal(t, uint64(1), scored[2].GetId().GetNum())
+
+	_, err = qdrant.RerankMaxSim([][]float32{{1, 0}}, points, "other", qdrant.Distance_Dot)
+	require.Error(t, err)
+}
@@ -0,0 +1,167 @@
+package qdrant_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qdrant/go-client/qdrant"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNumericIDPartitions(t *testing.T) {
+	partitions := qdrant.NumericIDPartitions(99, 4)
+	require.Len(t, partitions, 4)
+	for i, partition := range partitions {
+		require.Equal(t, uint64(i*25), *partition.StartID)
+	}
+	require.Equal(t, uint64(25), *partitions[0].EndID)
+	require.Equal(t, uint64(75), *partitions[2].EndID)
+	require.Nil(t, partitions[3].EndID)
+
+	require.Len(t, qdrant.NumericIDPartitions(10, 0), 1)
+}
+
+func TestParallelScrollOrderByWithIDPartitions(t *testing.T) {
+	client := fakeQdrant(t, fakeServices{}, &qdrant.Config{})
+	var errs []error
+	for _, err := range client.ParallelScroll(context.Background(), &qdrant.ScrollPoints{
+		CollectionName: "collection",
+		OrderBy:        &qdrant.OrderBy{Key: "timestamp"},
+	}, &qdrant.ParallelScrollOptions{
+		Partitions: qdrant.NumericIDPartitions(100, 2),
+	}) {
+		errs = append(errs, err)
+	}
+	require.Len(t, errs, 1)
+	require.ErrorContains(t, errs[0], "ID partitions cannot be read with an OrderBy")
+}
+
+func TestParallelScroll(t *testing.T) {
+	collectionName := t.Name()
+	apiKey := "<PARALLEL_SCROLL_TEST>"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	container, err := standaloneQdrant(ctx, apiKey)
+	require.NoError(t, err)
+
+	err = container.Start(ctx)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		err := container.Terminate(ctx)
+		require.NoError(t, err)
+	})
+
+	host, err := container.Host(ctx)
+	require.NoError(t, err)
+
+	port, err := container.MappedPort(ctx, "6334/tcp")
+	require.NoError(t, err)
+
+	client, err := qdrant.NewClient(&qdrant.Config{
+		Host:     host,
+		Port:     int(port.Num()),
+		APIKey:   apiKey,
+		PoolSize: 3,
+	})
+	require.NoError(t, err)
+
+	err = client.CreateCollection(ctx, &qdrant.CreateCollection{
+		CollectionName: collectionName,
+		VectorsConfig: qdrant.NewVectorsConfig(&qdrant.VectorParams{
+			Size:     2,
+			Distance: qdrant.Distance_Dot,
+		}),
+	})
+	require.NoError(t, err)
+
+	const numPoints = 100
+	points := make([]*qdrant.PointStruct, 0, numPoints+1)
+	for i := range numPoints {
+		points = append(points, &qdrant.PointStruct{
+			Id:      qdrant.NewIDNum(uint64(i)),
+			Vectors: qdrant.NewVectors(1, 2),
+			Payload: qdrant.NewValueMap(map[string]any{"even": i%2 == 0}),
+		})
+	}
+	uuid := "5c56c793-69f3-4fbf-87e6-c4bf54c28c26"
+	points = append(points, &qdrant.PointStruct{
+		Id:      qdrant.NewIDUUID(uuid),
+		Vectors: qdrant.NewVectors(1, 2),
+		Payload: qdrant.NewValueMap(map[string]any{"even": true}),
+	})
+	_, err = client.Upsert(ctx, &qdrant.UpsertPoints{
+		CollectionName: collectionName,
+		Wait:           qdrant.PtrOf(true),
+		Points:         points,
+	})
+	require.NoError(t, err)
+
+	scrollIDs := func(request *qdrant.ScrollPoints, options *qdrant.ParallelScrollOptions) []string {
+		var ids []string
+		for point, err := range client.ParallelScroll(ctx, request, options) {
+			require.NoError(t, err)
+			ids = append(ids, qdrant.FormatPointID(point.GetId()))
+		}
+		return ids
+	}
+	request := &qdrant.ScrollPoints{
+		CollectionName: collectionName,
+		Limit:          qdrant.PtrOf(uint32(7)),
+	}
+
+	t.Run("NumericIDPartitions", func(t *testing.T) {
+		ids := scrollIDs(request, &qdrant.ParallelScrollOptions{
+			Partitions:  qdrant.NumericIDPartitions(numPoints, 4),
+			Parallelism: 2,
+		})
+		require.Len(t, ids, numPoints+1)
+		require.Contains(t, ids, uuid)
+		for i := range numPoints {
+			require.Contains(t, ids, qdrant.FormatPointID(qdrant.NewIDNum(uint64(i))))
+		}
+	})
+
+	t.Run("FilterPartitions", func(t *testing.T) {
+		filtered := &qdrant.ScrollPoints{
+			CollectionName: collectionName,
+			Filter:         &qdrant.Filter{Must: []*qdrant.Condition{qdrant.NewMatchBool("even", true)}},
+			Limit:          qdrant.PtrOf(uint32(7)),
+		}
+		ids := scrollIDs(filtered, &qdrant.ParallelScrollOptions{
+			Partitions: qdrant.FilterPartitions(
+				&qdrant.Filter{Must: []*qdrant.Condition{qdrant.NewHasID(qdrant.NewIDNum(0), qdrant.NewIDNum(1))}},
+				&qdrant.Filter{Must: []*qdrant.Condition{qdrant.NewHasID(qdrant.NewIDNum(2), qdrant.NewIDUUID(uuid))}},
+			),
+		})
+		require.ElementsMatch(t, []string{"0", "2", uuid}, ids)
+	})
+
+	t.Run("StopEarly", func(t *testing.T) {
+		count := 0
+		for _, err := range client.ParallelScroll(ctx, request, &qdrant.ParallelScrollOptions{
+			Partitions: qdrant.NumericIDPartitions(numPoints, 4),
+			Buffer:     1,
+		}) {
+			require.NoError(t, err)
+			count++
+			if count == 10 {
+				break
+			}
+		}
+		require.Equal(t, 10, count)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		var errs []error
+		for _, err := range client.ParallelScroll(ctx, &qdrant.ScrollPoints{
+			CollectionName: "missing_collection",
+		}, nil) {
+			errs = append(errs, err)
+		}
+		require.Len(t, errs, 1)
+		require.Error(t, errs[0])
+	})
+}
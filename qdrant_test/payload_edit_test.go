@@ -0,0 +1,150 @@
+package qdrant_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/qdrant/go-client/qdrant"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayloadEdits(t *testing.T) {
+	collectionName := t.Name()
+	apiKey := "<PAYLOAD_EDITS_TEST>"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	container, err := standaloneQdrant(ctx, apiKey)
+	require.NoError(t, err)
+
+	err = container.Start(ctx)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		err := container.Terminate(ctx)
+		require.NoError(t, err)
+	})
+
+	host, err := container.Host(ctx)
+	require.NoError(t, err)
+
+	port, err := container.MappedPort(ctx, "6334/tcp")
+	require.NoError(t, err)
+
+	client, err := qdrant.NewClient(&qdrant.Config{
+		Host:   host,
+		Port:   int(port.Num()),
+		APIKey: apiKey,
+	})
+	require.NoError(t, err)
+
+	err = client.CreateCollection(ctx, &qdrant.CreateCollection{
+		CollectionName: collectionName,
+		VectorsConfig: qdrant.NewVectorsConfig(&qdrant.VectorParams{
+			Size:     2,
+			Distance: qdrant.Distance_Dot,
+		}),
+	})
+	require.NoError(t, err)
+
+	_, err = client.Upsert(ctx, &qdrant.UpsertPoints{
+		CollectionName: collectionName,
+		Wait:           qdrant.PtrOf(true),
+		Points: []*qdrant.PointStruct{
+			{
+				Id:      qdrant.NewIDNum(1),
+				Vectors: qdrant.NewVectors(1, 2),
+				Payload: qdrant.NewValueMap(map[string]any{
+					"colour":  "red",
+					"views":   1,
+					"tags":    []any{"a"},
+					"address": map[string]any{"city": "Berlin", "zip": "10115"},
+				}),
+			},
+			{
+				Id:      qdrant.NewIDNum(2),
+				Vectors: qdrant.NewVectors(3, 4),
+				Payload: qdrant.NewValueMap(map[string]any{"views": "many"}),
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	payload := func(id uint64) map[string]*qdrant.Value {
+		points, err := client.Get(ctx, &qdrant.GetPoints{
+			CollectionName: collectionName,
+			Ids:            []*qdrant.PointId{qdrant.NewIDNum(id)},
+			WithPayload:    qdrant.NewWithPayload(true),
+		})
+		require.NoError(t, err)
+		require.Len(t, points, 1)
+		return points[0].GetPayload()
+	}
+	options := &qdrant.PayloadEditOptions{Wait: true, PageSize: 1}
+	only := func(id uint64) *qdrant.Filter {
+		return &qdrant.Filter{Must: []*qdrant.Condition{qdrant.NewHasID(qdrant.NewIDNum(id))}}
+	}
+
+	t.Run("PatchPayload", func(t *testing.T) {
+		report, err := client.PatchPayload(ctx, collectionName, only(1), qdrant.NewValueMap(map[string]any{
+			"address": map[string]any{"zip": nil, "country": "DE"},
+		}), options)
+		require.NoError(t, err)
+		require.Equal(t, &qdrant.PayloadEditReport{Scanned: 1, Updated: 1}, report)
+		require.Equal(t, qdrant.NewValueMap(map[string]any{"city": "Berlin", "country": "DE"}),
+			payload(1)["address"].GetStructValue().GetFields())
+	})
+
+	t.Run("AppendPayload", func(t *testing.T) {
+		_, err := client.AppendPayload(ctx, collectionName, nil, "tags",
+			[]*qdrant.Value{qdrant.NewValueString("b")}, options)
+		require.NoError(t, err)
+		require.Len(t, payload(1)["tags"].GetListValue().GetValues(), 2)
+		require.Len(t, payload(2)["tags"].GetListValue().GetValues(), 1)
+	})
+
+	t.Run("IncrementPayload", func(t *testing.T) {
+		var progress []qdrant.PayloadEditReport
+		report, err := client.IncrementPayload(ctx, collectionName, nil, "views", 2, &qdrant.PayloadEditOptions{
+			Wait:     true,
+			PageSize: 1,
+			Progress: func(report qdrant.PayloadEditReport) { progress = append(progress, report) },
+		})
+		require.NoError(t, err)
+		require.Equal(t, &qdrant.PayloadEditReport{Scanned: 2, Updated: 1, Skipped: 1}, report)
+		require.Len(t, progress, 2)
+		require.Equal(t, int64(3), payload(1)["views"].GetIntegerValue())
+		require.Equal(t, "many", payload(2)["views"].GetStringValue())
+	})
+
+	t.Run("RenamePayloadKey", func(t *testing.T) {
+		report, err := client.RenamePayloadKey(ctx, collectionName, nil, "colour", "style.color", options)
+		require.NoError(t, err)
+		require.Equal(t, &qdrant.PayloadEditReport{Scanned: 2, Updated: 1}, report)
+		require.NotContains(t, payload(1), "colour")
+		require.Equal(t, "red", payload(1)["style"].GetStructValue().GetFields()["color"].GetStringValue())
+
+		// Renaming again changes nothing.
+		report, err = client.RenamePayloadKey(ctx, collectionName, nil, "colour", "style.color", options)
+		require.NoError(t, err)
+		require.Zero(t, report.Updated)
+	})
+}
+
+func TestIncrementPayloadOverflow(t *testing.T) {
+	server := newMigrationPoints(3)
+	server.payloads[1]["n"] = qdrant.NewValueInt(math.MaxInt64 - 1<<62)
+	server.payloads[2]["n"] = qdrant.NewValueInt(math.MaxInt64 - 1<<62 + 1)
+	server.payloads[3]["n"] = qdrant.NewValueDouble(math.MaxInt64)
+	client := fakeQdrant(t, fakeServices{points: server}, &qdrant.Config{})
+
+	report, err := client.IncrementPayload(context.Background(), "collection", nil, "n", 1<<62, nil)
+	require.NoError(t, err)
+	// The integer that would overflow is skipped, and left as is.
+	require.Equal(t, &qdrant.PayloadEditReport{Scanned: 3, Updated: 2, Skipped: 1}, report)
+	require.Equal(t, int64(math.MaxInt64), server.payloads[1]["n"].GetIntegerValue())
+	require.Equal(t, int64(math.MaxInt64-1<<62+1), server.payloads[2]["n"].GetIntegerValue())
+	require.InDelta(t, float64(math.MaxInt64+1<<62), server.payloads[3]["n"].GetDoubleValue(), 1)
+}
@@ -0,0 +1,322 @@
+package qdrant_test
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/qdrant/go-client/qdrant"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMigratePayload(t *testing.T) {
+	collectionName := t.Name()
+	apiKey := "<MIGRATE_PAYLOAD_TEST>"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	container, err := standaloneQdrant(ctx, apiKey)
+	require.NoError(t, err)
+
+	err = container.Start(ctx)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		err := container.Terminate(ctx)
+		require.NoError(t, err)
+	})
+
+	host, err := container.Host(ctx)
+	require.NoError(t, err)
+
+	port, err := container.MappedPort(ctx, "6334/tcp")
+	require.NoError(t, err)
+
+	client, err := qdrant.NewClient(&qdrant.Config{
+		Host:   host,
+		Port:   int(port.Num()),
+		APIKey: apiKey,
+	})
+	require.NoError(t, err)
+
+	err = client.CreateCollection(ctx, &qdrant.CreateCollection{
+		CollectionName: collectionName,
+		VectorsConfig: qdrant.NewVectorsConfig(&qdrant.VectorParams{
+			Size:     2,
+			Distance: qdrant.Distance_Dot,
+		}),
+	})
+	require.NoError(t, err)
+
+	const numPoints = 10
+	points := make([]*qdrant.PointStruct, numPoints)
+	for i := range points {
+		points[i] = &qdrant.PointStruct{
+			Id:      qdrant.NewIDNum(uint64(i + 1)),
+			Vectors: qdrant.NewVectors(1, 2),
+			Payload: qdrant.NewValueMap(map[string]any{"price": float64(i) + 0.5, "name": "item"}),
+		}
+	}
+	_, err = client.Upsert(ctx, &qdrant.UpsertPoints{
+		CollectionName: collectionName,
+		Wait:           qdrant.PtrOf(true),
+		Points:         points,
+	})
+	require.NoError(t, err)
+
+	payloads := func() map[uint64]map[string]*qdrant.Value {
+		points, err := client.Scroll(ctx, &qdrant.ScrollPoints{
+			CollectionName: collectionName,
+			Limit:          qdrant.PtrOf(uint32(numPoints)),
+			WithPayload:    qdrant.NewWithPayload(true),
+		})
+		require.NoError(t, err)
+		result := make(map[uint64]map[string]*qdrant.Value, len(points))
+		for _, point := range points {
+			result[point.GetId().GetNum()] = point.GetPayload()
+		}
+		return result
+	}
+
+	t.Run("ResumeFromCheckpoint", func(t *testing.T) {
+		checkpoint := filepath.Join(t.TempDir(), "migration.json")
+		errInterrupted := errors.New("interrupted")
+		interrupt := true
+		migration := &qdrant.PayloadMigration{
+			CollectionName: collectionName,
+			Transform: func(id *qdrant.PointId, payload map[string]*qdrant.Value) error {
+				if interrupt && id.GetNum() == 6 {
+					return errInterrupted
+				}
+				if id.GetNum() == 2 {
+					return qdrant.ErrSkipPoint
+				}
+				price, ok := payload["price"]
+				if !ok {
+					return nil
+				}
+				payload["price_cents"] = qdrant.NewValueInt(int64(price.GetDoubleValue() * 100))
+				delete(payload, "price")
+				return nil
+			},
+			CheckpointFile: checkpoint,
+			PageSize:       2,
+			Parallelism:    1,
+			Wait:           true,
+		}
+
+		report, err := client.MigratePayload(ctx, migration)
+		require.ErrorIs(t, err, errInterrupted)
+		require.Equal(t, &qdrant.PayloadEditReport{Scanned: 4, Updated: 3, Skipped: 1}, report)
+		require.FileExists(t, checkpoint)
+		require.Contains(t, payloads()[5], "price")
+
+		interrupt = false
+		var progress []qdrant.PayloadEditReport
+		migration.Progress = func(report qdrant.PayloadEditReport) { progress = append(progress, report) }
+		report, err = client.MigratePayload(ctx, migration)
+		require.NoError(t, err)
+		require.Equal(t, &qdrant.PayloadEditReport{Scanned: numPoints, Updated: numPoints - 1, Skipped: 1}, report)
+		require.Len(t, progress, 3)
+		require.NoFileExists(t, checkpoint)
+
+		for id, payload := range payloads() {
+			if id == 2 {
+				require.Contains(t, payload, "price")
+				continue
+			}
+			require.NotContains(t, payload, "price")
+			require.Equal(t, int64(id-1)*100+50, payload["price_cents"].GetIntegerValue())
+			require.Equal(t, "item", payload["name"].GetStringValue())
+		}
+	})
+
+	t.Run("SetMode", func(t *testing.T) {
+		report, err := client.MigratePayload(ctx, &qdrant.PayloadMigration{
+			CollectionName: collectionName,
+			Filter: &qdrant.Filter{
+				Must: []*qdrant.Condition{qdrant.NewHasID(qdrant.NewIDNum(1), qdrant.NewIDNum(2))},
+			},
+			Transform: func(_ *qdrant.PointId, payload map[string]*qdrant.Value) error {
+				payload["name"] = qdrant.NewValueString("renamed")
+				delete(payload, "price")
+				return nil
+			},
+			Mode: qdrant.PayloadMigrationSet,
+			Wait: true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, &qdrant.PayloadEditReport{Scanned: 2, Updated: 2}, report)
+
+		all := payloads()
+		require.Equal(t, "renamed", all[1]["name"].GetStringValue())
+		require.Contains(t, all[1], "price_cents")
+		require.Equal(t, "renamed", all[2]["name"].GetStringValue())
+		require.NotContains(t, all[2], "price")
+		require.Equal(t, "item", all[3]["name"].GetStringValue())
+	})
+}
+
+func TestMigratePayloadCheckpointOfAnotherMigration(t *testing.T) {
+	server := newMigrationPoints(10)
+	client := fakeQdrant(t, fakeServices{points: server}, &qdrant.Config{})
+	checkpoint := filepath.Join(t.TempDir(), "checkpoint.json")
+	errStop := errors.New("stop")
+
+	migration := func(collectionName string, filter *qdrant.Filter, stopAt uint64) *qdrant.PayloadMigration {
+		return &qdrant.PayloadMigration{
+			CollectionName: collectionName,
+			Filter:         filter,
+			Transform: func(id *qdrant.PointId, payload map[string]*qdrant.Value) error {
+				if id.GetNum() == stopAt {
+					return errStop
+				}
+				payload["migrated"] = qdrant.NewValueBool(true)
+				return nil
+			},
+			PageSize:       2,
+			Parallelism:    1,
+			CheckpointFile: checkpoint,
+		}
+	}
+	filter := &qdrant.Filter{Must: []*qdrant.Condition{qdrant.NewMatchKeyword("color", "red")}}
+
+	_, err := client.MigratePayload(context.Background(), migration("collection", filter, 5))
+	require.ErrorIs(t, err, errStop)
+	require.FileExists(t, checkpoint)
+
+	_, err = client.MigratePayload(context.Background(), migration("other_collection", filter, 0))
+	require.ErrorContains(t, err, "another collection or filter")
+	_, err = client.MigratePayload(context.Background(), migration("collection", nil, 0))
+	require.ErrorContains(t, err, "another collection or filter")
+
+	report, err := client.MigratePayload(context.Background(), migration("collection", filter, 0))
+	require.NoError(t, err)
+	require.Equal(t, &qdrant.PayloadEditReport{Scanned: 10, Updated: 10}, report)
+	require.NoFileExists(t, checkpoint)
+}
+
+// migrationPoints serves Scroll over points with numeric IDs, ignoring the filter, and applies
+// the payload writes of UpdateBatch. The batches containing failID fail after failDelay until failures runs out.
+type migrationPoints struct {
+	qdrant.UnimplementedPointsServer
+	mu        sync.Mutex
+	payloads  map[uint64]map[string]*qdrant.Value
+	failID    uint64
+	failures  int
+	failDelay time.Duration
+}
+
+// newMigrationPoints returns a server with the points 1 to n, each with its ID in the payload as "n".
+func newMigrationPoints(n int) *migrationPoints {
+	payloads := make(map[uint64]map[string]*qdrant.Value, n)
+	for id := uint64(1); id <= uint64(n); id++ {
+		payloads[id] = map[string]*qdrant.Value{"n": qdrant.NewValueInt(int64(id))}
+	}
+	return &migrationPoints{payloads: payloads}
+}
+
+func (m *migrationPoints) Scroll(_ context.Context, request *qdrant.ScrollPoints) (*qdrant.ScrollResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ids := make([]uint64, 0, len(m.payloads))
+	for id := range m.payloads {
+		if id >= request.GetOffset().GetNum() {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	response := &qdrant.ScrollResponse{}
+	for i, id := range ids {
+		if i == int(request.GetLimit()) {
+			response.NextPageOffset = qdrant.NewIDNum(id)
+			break
+		}
+		response.Result = append(response.Result, &qdrant.RetrievedPoint{
+			Id:      qdrant.NewIDNum(id),
+			Payload: maps.Clone(m.payloads[id]),
+		})
+	}
+	return response, nil
+}
+
+func (m *migrationPoints) UpdateBatch(_ context.Context, request *qdrant.UpdateBatchPoints,
+) (*qdrant.UpdateBatchResponse, error) {
+	for _, operation := range request.GetOperations() {
+		overwrite := operation.GetOverwritePayload()
+		for _, id := range overwrite.GetPointsSelector().GetPoints().GetIds() {
+			if m.fail(id.GetNum()) {
+				time.Sleep(m.failDelay)
+				return nil, status.Error(codes.Unavailable, "write failed")
+			}
+		}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	results := make([]*qdrant.UpdateResult, len(request.GetOperations()))
+	for i, operation := range request.GetOperations() {
+		overwrite := operation.GetOverwritePayload()
+		for _, id := range overwrite.GetPointsSelector().GetPoints().GetIds() {
+			m.payloads[id.GetNum()] = maps.Clone(overwrite.GetPayload())
+		}
+		results[i] = &qdrant.UpdateResult{Status: qdrant.UpdateStatus_Completed}
+	}
+	return &qdrant.UpdateBatchResponse{Result: results}, nil
+}
+
+func TestMigratePayloadResumeAfterFailedWrite(t *testing.T) {
+	server := newMigrationPoints(20)
+	server.failID, server.failures = 9, 1
+	// The failing write waits for the writes of the next pages, so that they are applied but not checkpointed.
+	server.failDelay = 200 * time.Millisecond
+	client := fakeQdrant(t, fakeServices{points: server}, &qdrant.Config{})
+	checkpoint := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	var transformedAgain int
+	migration := &qdrant.PayloadMigration{
+		CollectionName: "collection",
+		Transform: func(_ *qdrant.PointId, payload map[string]*qdrant.Value) error {
+			if _, ok := payload["double"]; ok {
+				transformedAgain++
+			}
+			payload["double"] = qdrant.NewValueInt(2 * payload["n"].GetIntegerValue())
+			return nil
+		},
+		PageSize:       2,
+		Parallelism:    4,
+		CheckpointFile: checkpoint,
+	}
+	_, err := client.MigratePayload(context.Background(), migration)
+	require.Error(t, err)
+	require.FileExists(t, checkpoint)
+
+	report, err := client.MigratePayload(context.Background(), migration)
+	require.NoError(t, err)
+	// The pages written after the failed one are transformed again, and left unchanged.
+	require.Positive(t, transformedAgain)
+	require.Equal(t, uint64(20), report.Scanned)
+	require.Equal(t, uint64(20)-uint64(transformedAgain), report.Updated)
+	require.NoFileExists(t, checkpoint)
+	for id, payload := range server.payloads {
+		require.Equal(t, int64(2*id), payload["double"].GetIntegerValue())
+	}
+}
+
+// fail returns whether the write of id fails.
+func (m *migrationPoints) fail(id uint64) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if id != m.failID || m.failures == 0 {
+		return false
+	}
+	m.failures--
+	return true
+}
@@ -0,0 +1,92 @@
+package qdrant_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/qdrant/go-client/qdrant"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayloadSchemaInferrer(t *testing.T) {
+	inferrer := qdrant.NewPayloadSchemaInferrer(0)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 40 {
+		payload := map[string]any{
+			"tenant":      fmt.Sprintf("tenant-%d", i%4),
+			"created_at":  start.Add(time.Duration(i) * time.Hour).Format(time.RFC3339),
+			"price":       float64(i) / 2,
+			"description": "a rather long product description",
+			"tags":        []any{"new", "sale"},
+			"location":    map[string]any{"lat": 52.52, "lon": 13.40},
+			"items":       []any{map[string]any{"sku": int64(i)}},
+			"owner":       fmt.Sprintf("%08d-0000-4000-8000-000000000000", i),
+		}
+		if i%2 == 0 {
+			payload["price"] = int64(i)
+			payload["note"] = nil
+		}
+		inferrer.Add(qdrant.NewValueMap(payload))
+	}
+
+	report := inferrer.Report()
+	require.Equal(t, uint64(40), report.Points)
+	keys := make(map[string]*qdrant.PayloadKeyStats)
+	for _, stats := range report.Keys {
+		keys[stats.Key] = stats
+	}
+	require.Len(t, keys, 11)
+
+	note := keys["note"]
+	require.Equal(t, uint64(20), note.Nulls)
+	require.Equal(t, uint64(20), note.Missing)
+	require.True(t, note.Nullable())
+	_, ok := note.Type()
+	require.False(t, ok)
+
+	tags := keys["tags"]
+	require.True(t, tags.IsArray())
+	require.Equal(t, map[qdrant.PayloadValueType]uint64{qdrant.PayloadValueString: 80}, tags.Types)
+	require.Equal(t, 2, tags.Distinct)
+
+	price := keys["price"]
+	priceType, ok := price.Type()
+	require.True(t, ok)
+	require.Equal(t, qdrant.PayloadValueFloat, priceType)
+
+	sku := keys["items[].sku"]
+	require.Equal(t, uint64(40), sku.Present)
+	require.Equal(t, map[qdrant.PayloadValueType]uint64{qdrant.PayloadValueInteger: 40}, sku.Types)
+
+	require.Equal(t, map[qdrant.PayloadValueType]uint64{qdrant.PayloadValueGeo: 40}, keys["location"].Types)
+	require.Equal(t, map[qdrant.PayloadValueType]uint64{qdrant.PayloadValueUUID: 40}, keys["owner"].Types)
+	require.Equal(t, 4, keys["tenant"].Distinct)
+
+	recommendations := make(map[string]*qdrant.IndexRecommendation)
+	for _, recommendation := range report.RecommendIndexes() {
+		recommendations[recommendation.Key] = recommendation
+	}
+	require.Len(t, recommendations, 8)
+	require.Equal(t, qdrant.FieldType_FieldTypeKeyword, recommendations["tenant"].FieldType)
+	require.True(t, recommendations["tenant"].Params.GetKeywordIndexParams().GetIsTenant())
+	require.Equal(t, qdrant.FieldType_FieldTypeDatetime, recommendations["created_at"].FieldType)
+	require.True(t, recommendations["created_at"].Params.GetDatetimeIndexParams().GetIsPrincipal())
+	require.Equal(t, qdrant.FieldType_FieldTypeText, recommendations["description"].FieldType)
+	require.Equal(t, qdrant.FieldType_FieldTypeFloat, recommendations["price"].FieldType)
+	require.Equal(t, qdrant.FieldType_FieldTypeKeyword, recommendations["tags"].FieldType)
+	require.False(t, recommendations["tags"].Params.GetKeywordIndexParams().GetIsTenant())
+	require.Equal(t, qdrant.FieldType_FieldTypeInteger, recommendations["items[].sku"].FieldType)
+	require.Equal(t, qdrant.FieldType_FieldTypeGeo, recommendations["location"].FieldType)
+	require.Equal(t, qdrant.FieldType_FieldTypeUuid, recommendations["owner"].FieldType)
+	require.False(t, recommendations["owner"].Params.GetUuidIndexParams().GetIsTenant())
+
+	request := recommendations["tenant"].Request("products")
+	require.Equal(t, "products", request.GetCollectionName())
+	require.Equal(t, "tenant", request.GetFieldName())
+	require.Equal(t, qdrant.FieldType_FieldTypeKeyword, request.GetFieldType())
+
+	// Indexed keys are not recommended.
+	keys["tenant"].Index = &qdrant.PayloadSchemaInfo{DataType: qdrant.PayloadSchemaType_Keyword}
+	require.Len(t, report.RecommendIndexes(), 7)
+}
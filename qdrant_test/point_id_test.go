@@ -0,0 +1,107 @@
+package qdrant_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/qdrant/go-client/qdrant"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewIDFromKey(t *testing.T) {
+	// Same as uuid.uuid5(uuid.NAMESPACE_DNS, "python.org") in Python.
+	require.Equal(t, "886313e1-3b8a-5372-9b90-0c9aee199e5d", qdrant.NewIDFromKey(uuid.NameSpaceDNS, "python.org").GetUuid())
+	require.Equal(t, qdrant.NewIDFromKey(uuid.NameSpaceURL, "a"), qdrant.NewIDFromKey(uuid.NameSpaceURL, "a"))
+	require.NotEqual(t, qdrant.NewIDFromKey(uuid.NameSpaceURL, "a"), qdrant.NewIDFromKey(uuid.NameSpaceDNS, "a"))
+}
+
+func TestNewIDFromContent(t *testing.T) {
+	payload := func() map[string]*qdrant.Value {
+		return qdrant.NewValueMap(map[string]any{"a": 1, "b": "x", "c": []any{true, 2.5}})
+	}
+	id, err := qdrant.NewIDFromContent(uuid.NameSpaceOID, payload(), qdrant.NewVectors(1, 2))
+	require.NoError(t, err)
+	for range 10 {
+		other, err := qdrant.NewIDFromContent(uuid.NameSpaceOID, payload(), qdrant.NewVectors(1, 2))
+		require.NoError(t, err)
+		require.Equal(t, id.GetUuid(), other.GetUuid())
+	}
+	other, err := qdrant.NewIDFromContent(uuid.NameSpaceOID, payload(), qdrant.NewVectors(1, 3))
+	require.NoError(t, err)
+	require.NotEqual(t, id.GetUuid(), other.GetUuid())
+
+	_, err = qdrant.NewIDFromContent(uuid.NameSpaceOID, map[string]*qdrant.Value{"a": qdrant.NewValueString("\xff")}, nil)
+	require.Error(t, err)
+}
+
+func TestNewIDFromContentGolden(t *testing.T) {
+	// The content encoding must never change: the IDs of existing points would change with it.
+	// Same as the UUIDv5 of the encoding written by hand in Python.
+	payload := qdrant.NewValueMap(map[string]any{
+		"b": "x\"é\n",
+		"a": 1,
+		"c": []any{true, 2.5, nil},
+		"d": map[string]any{"z": 1.0, "y": "w"},
+	})
+	vectors := func(indices []uint32, values []float32) *qdrant.Vectors {
+		return qdrant.NewVectorsMap(map[string]*qdrant.Vector{
+			"dense":  qdrant.NewVectorDense([]float32{0.5, -1}),
+			"sparse": qdrant.NewVectorSparse(indices, values),
+		})
+	}
+	id, err := qdrant.NewIDFromContent(uuid.NameSpaceOID, payload, vectors([]uint32{3, 1}, []float32{0.25, 2}))
+	require.NoError(t, err)
+	require.Equal(t, "c5d86e6a-7605-5db0-9838-b337c7fe46c2", id.GetUuid())
+
+	// The order of the values of sparse vectors does not matter.
+	id, err = qdrant.NewIDFromContent(uuid.NameSpaceOID, payload, vectors([]uint32{1, 3}, []float32{2, 0.25}))
+	require.NoError(t, err)
+	require.Equal(t, "c5d86e6a-7605-5db0-9838-b337c7fe46c2", id.GetUuid())
+}
+
+func TestPointIDString(t *testing.T) {
+	for _, id := range []*qdrant.PointId{
+		qdrant.NewIDNum(0),
+		qdrant.NewIDNum(18446744073709551615),
+		qdrant.NewIDUUID("5c56c793-69f3-4fbf-87e6-c4bf54c28c26"),
+	} {
+		parsed, err := qdrant.ParsePointID(qdrant.FormatPointID(id))
+		require.NoError(t, err)
+		require.Equal(t, id.String(), parsed.String())
+	}
+	require.Empty(t, qdrant.FormatPointID(nil))
+
+	parsed, err := qdrant.ParsePointID("5C56C79369F34FBF87E6C4BF54C28C26")
+	require.NoError(t, err)
+	require.Equal(t, "5c56c793-69f3-4fbf-87e6-c4bf54c28c26", parsed.GetUuid())
+
+	for _, s := range []string{"", "-1", "1.5", "not-a-uuid"} {
+		_, err := qdrant.ParsePointID(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestAssignPointIDs(t *testing.T) {
+	points := []*qdrant.PointStruct{
+		{Payload: qdrant.NewValueMap(map[string]any{"sku": "A-1"})},
+		{Id: qdrant.NewIDNum(7), Payload: qdrant.NewValueMap(map[string]any{"sku": "A-2"})},
+		{Payload: qdrant.NewValueMap(map[string]any{"sku": "A-1"})},
+	}
+	sku := func(point *qdrant.PointStruct) (string, error) {
+		return point.GetPayload()["sku"].GetStringValue(), nil
+	}
+	require.NoError(t, qdrant.AssignPointIDs(points, qdrant.KeyPointIDs(uuid.NameSpaceURL, sku)))
+	require.Equal(t, qdrant.NewIDFromKey(uuid.NameSpaceURL, "A-1").GetUuid(), points[0].GetId().GetUuid())
+	require.Equal(t, uint64(7), points[1].GetId().GetNum())
+	require.Equal(t, points[0].GetId().GetUuid(), points[2].GetId().GetUuid())
+
+	points = []*qdrant.PointStruct{{}, {}}
+	require.ErrorContains(t, qdrant.AssignPointIDs(points, qdrant.KeyPointIDs(uuid.NameSpaceURL, sku)), "point 0")
+	failing := func(*qdrant.PointStruct) (*qdrant.PointId, error) { return nil, errors.New("boom") }
+	require.ErrorContains(t, qdrant.AssignPointIDs(points, failing), "boom")
+
+	points = []*qdrant.PointStruct{{Vectors: qdrant.NewVectors(1)}, {Vectors: qdrant.NewVectors(1)}}
+	require.NoError(t, qdrant.AssignPointIDs(points, qdrant.ContentPointIDs(uuid.NameSpaceOID)))
+	require.Equal(t, points[0].GetId().GetUuid(), points[1].GetId().GetUuid())
+}
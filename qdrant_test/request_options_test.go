@@ -0,0 +1,232 @@
+package qdrant_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/qdrant/go-client/qdrant"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestOptions(t *testing.T) {
+	collectionName := t.Name()
+	apiKey := "<REQUEST_OPTIONS_TEST>"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	container, err := distributedQdrant(ctx, apiKey)
+	require.NoError(t, err)
+
+	err = container.Start(ctx)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		err := container.Terminate(ctx)
+		require.NoError(t, err)
+	})
+
+	host, err := container.Host(ctx)
+	require.NoError(t, err)
+
+	port, err := container.MappedPort(ctx, "6334/tcp")
+	require.NoError(t, err)
+
+	// Every request of this client waits and is routed to shard "a" unless specified otherwise.
+	client, err := qdrant.NewClient(&qdrant.Config{
+		Host:   host,
+		Port:   int(port.Num()),
+		APIKey: apiKey,
+		RequestOptions: []qdrant.RequestOption{
+			qdrant.WithWait(true),
+			qdrant.WithShardKeys(qdrant.NewShardKey("a")),
+		},
+	})
+	require.NoError(t, err)
+
+	err = client.CreateCollection(ctx, &qdrant.CreateCollection{
+		CollectionName: collectionName,
+		VectorsConfig: qdrant.NewVectorsConfig(&qdrant.VectorParams{
+			Size:     4,
+			Distance: qdrant.Distance_Cosine,
+		}),
+		ShardingMethod: qdrant.ShardingMethod_Custom.Enum(),
+	})
+	require.NoError(t, err)
+
+	for _, key := range []string{"a", "b"} {
+		err = client.CreateShardKey(ctx, collectionName, &qdrant.CreateShardKey{
+			ShardKey: qdrant.NewShardKey(key),
+		})
+		require.NoError(t, err)
+	}
+
+	countIn := func(t *testing.T, key string) uint64 {
+		t.Helper()
+		count, err := client.Count(ctx, &qdrant.CountPoints{
+			CollectionName:   collectionName,
+			ShardKeySelector: &qdrant.ShardKeySelector{ShardKeys: []*qdrant.ShardKey{qdrant.NewShardKey(key)}},
+			Exact:            qdrant.PtrOf(true),
+		})
+		require.NoError(t, err)
+		return count
+	}
+
+	t.Run("ConfigDefaults", func(t *testing.T) {
+		request := &qdrant.UpsertPoints{
+			CollectionName: collectionName,
+			Points: []*qdrant.PointStruct{
+				{Id: qdrant.NewIDNum(1), Vectors: qdrant.NewVectors(0.1, 0.2, 0.3, 0.4)},
+			},
+		}
+		_, err := client.Upsert(ctx, request)
+		require.NoError(t, err)
+		// The caller's request is left untouched.
+		require.Nil(t, request.GetShardKeySelector())
+		require.Nil(t, request.Wait)
+
+		require.Equal(t, uint64(1), countIn(t, "a"))
+		require.Equal(t, uint64(0), countIn(t, "b"))
+	})
+
+	t.Run("ContextOptionsOverrideDefaults", func(t *testing.T) {
+		optsCtx := qdrant.WithRequestOptions(ctx,
+			qdrant.WithShardKeys(qdrant.NewShardKey("b")),
+			qdrant.WithOrdering(qdrant.WriteOrderingType_Strong),
+		)
+		_, err := client.Upsert(optsCtx, &qdrant.UpsertPoints{
+			CollectionName: collectionName,
+			Points: []*qdrant.PointStruct{
+				{Id: qdrant.NewIDNum(2), Vectors: qdrant.NewVectors(0.1, 0.2, 0.3, 0.4)},
+			},
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, uint64(1), countIn(t, "a"))
+		require.Equal(t, uint64(1), countIn(t, "b"))
+	})
+
+	t.Run("ReadOptions", func(t *testing.T) {
+		optsCtx := qdrant.WithRequestOptions(ctx,
+			qdrant.WithReadConsistency(qdrant.ReadConsistencyType_Majority),
+			qdrant.WithShardKeys(qdrant.NewShardKey("b")),
+			qdrant.WithServerTimeout(10*time.Second),
+		)
+		points, err := client.Query(optsCtx, &qdrant.QueryPoints{
+			CollectionName: collectionName,
+		})
+		require.NoError(t, err)
+		require.Len(t, points, 1)
+		require.Equal(t, uint64(2), points[0].GetId().GetNum())
+	})
+
+	err = client.DeleteCollection(ctx, collectionName)
+	require.NoError(t, err)
+}
+
+func TestDeadlinePropagation(t *testing.T) {
+	collectionName := t.Name()
+	apiKey := "<DEADLINE_PROPAGATION_TEST>"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	container, err := standaloneQdrant(ctx, apiKey)
+	require.NoError(t, err)
+
+	err = container.Start(ctx)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		err := container.Terminate(ctx)
+		require.NoError(t, err)
+	})
+
+	host, err := container.Host(ctx)
+	require.NoError(t, err)
+
+	port, err := container.MappedPort(ctx, "6334/tcp")
+	require.NoError(t, err)
+
+	client, err := qdrant.NewClient(&qdrant.Config{
+		Host:   host,
+		Port:   int(port.Num()),
+		APIKey: apiKey,
+	})
+	require.NoError(t, err)
+
+	err = client.CreateCollection(ctx, &qdrant.CreateCollection{
+		CollectionName: collectionName,
+		VectorsConfig: qdrant.NewVectorsConfig(&qdrant.VectorParams{
+			Size:     4,
+			Distance: qdrant.Distance_Cosine,
+		}),
+		// Strict mode rejects requests with a timeout above max_timeout,
+		// which makes the propagated timeout observable.
+		StrictModeConfig: &qdrant.StrictModeConfig{
+			Enabled:    qdrant.PtrOf(true),
+			MaxTimeout: qdrant.PtrOf(uint32(5)),
+		},
+	})
+	require.NoError(t, err)
+
+	query := &qdrant.QueryPoints{
+		CollectionName: collectionName,
+		Query:          qdrant.NewQuery(0.1, 0.2, 0.3, 0.4),
+	}
+
+	t.Run("WithoutDeadline", func(t *testing.T) {
+		_, err := client.Query(ctx, query)
+		require.NoError(t, err)
+	})
+
+	t.Run("ShortDeadline", func(t *testing.T) {
+		deadlineCtx, deadlineCancel := context.WithTimeout(ctx, 3*time.Second)
+		defer deadlineCancel()
+		_, err := client.Query(deadlineCtx, query)
+		require.NoError(t, err)
+	})
+
+	t.Run("LongDeadline", func(t *testing.T) {
+		deadlineCtx, deadlineCancel := context.WithTimeout(ctx, time.Minute)
+		defer deadlineCancel()
+		_, err := client.Query(deadlineCtx, query)
+		require.Error(t, err)
+	})
+
+	t.Run("ExplicitTimeoutTakesPrecedence", func(t *testing.T) {
+		deadlineCtx, deadlineCancel := context.WithTimeout(ctx, time.Minute)
+		defer deadlineCancel()
+		optsCtx := qdrant.WithRequestOptions(deadlineCtx, qdrant.WithServerTimeout(2*time.Second))
+		_, err := client.Query(optsCtx, query)
+		require.NoError(t, err)
+	})
+
+	t.Run("RequestOptOut", func(t *testing.T) {
+		deadlineCtx, deadlineCancel := context.WithTimeout(ctx, time.Minute)
+		defer deadlineCancel()
+		optsCtx := qdrant.WithRequestOptions(deadlineCtx, qdrant.WithoutDeadlinePropagation())
+		_, err := client.Query(optsCtx, query)
+		require.NoError(t, err)
+	})
+
+	t.Run("ConfigOptOut", func(t *testing.T) {
+		optOutClient, err := qdrant.NewClient(&qdrant.Config{
+			Host:                       host,
+			Port:                       int(port.Num()),
+			APIKey:                     apiKey,
+			DisableDeadlinePropagation: true,
+		})
+		require.NoError(t, err)
+		defer optOutClient.Close()
+
+		deadlineCtx, deadlineCancel := context.WithTimeout(ctx, time.Minute)
+		defer deadlineCancel()
+		_, err = optOutClient.Query(deadlineCtx, query)
+		require.NoError(t, err)
+	})
+
+	err = client.DeleteCollection(ctx, collectionName)
+	require.NoError(t, err)
+}
@@ -0,0 +1,159 @@
+package qdrant_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/qdrant/go-client/qdrant"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRequestSplitting(t *testing.T) {
+	collectionName := t.Name()
+	apiKey := "<REQUEST_SPLITTING_TEST>"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	container, err := standaloneQdrant(ctx, apiKey)
+	require.NoError(t, err)
+
+	err = container.Start(ctx)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		err := container.Terminate(ctx)
+		require.NoError(t, err)
+	})
+
+	host, err := container.Host(ctx)
+	require.NoError(t, err)
+
+	port, err := container.MappedPort(ctx, "6334/tcp")
+	require.NoError(t, err)
+
+	client, err := qdrant.NewClient(&qdrant.Config{
+		Host:           host,
+		Port:           int(port.Num()),
+		APIKey:         apiKey,
+		MaxRequestSize: 512,
+	})
+	require.NoError(t, err)
+
+	err = client.CreateCollection(ctx, &qdrant.CreateCollection{
+		CollectionName: collectionName,
+		VectorsConfig: qdrant.NewVectorsConfig(&qdrant.VectorParams{
+			Size:     8,
+			Distance: qdrant.Distance_Dot,
+		}),
+	})
+	require.NoError(t, err)
+
+	const count = 100
+	points := make([]*qdrant.PointStruct, count)
+	ids := make([]*qdrant.PointId, count)
+	for i := range points {
+		ids[i] = qdrant.NewIDNum(uint64(i))
+		points[i] = &qdrant.PointStruct{
+			Id:      ids[i],
+			Vectors: qdrant.NewVectors(float32(i), 1, 2, 3, 4, 5, 6, 7),
+		}
+	}
+
+	result, err := client.Upsert(ctx, &qdrant.UpsertPoints{
+		CollectionName: collectionName,
+		Wait:           qdrant.PtrOf(true),
+		Points:         points,
+	})
+	require.NoError(t, err)
+	require.Equal(t, qdrant.UpdateStatus_Completed, result.GetStatus())
+
+	_, err = client.SetPayload(ctx, &qdrant.SetPayloadPoints{
+		CollectionName: collectionName,
+		Wait:           qdrant.PtrOf(true),
+		Payload:        qdrant.NewValueMap(map[string]any{"seen": true}),
+		PointsSelector: qdrant.NewPointsSelectorIDs(ids),
+	})
+	require.NoError(t, err)
+
+	retrieved, err := client.Get(ctx, &qdrant.GetPoints{
+		CollectionName: collectionName,
+		Ids:            ids,
+		WithPayload:    qdrant.NewWithPayload(true),
+	})
+	require.NoError(t, err)
+	require.Len(t, retrieved, count)
+	for _, point := range retrieved {
+		require.True(t, point.GetPayload()["seen"].GetBoolValue())
+	}
+
+	queries := make([]*qdrant.QueryPoints, 20)
+	for i := range queries {
+		queries[i] = &qdrant.QueryPoints{
+			CollectionName: collectionName,
+			Query:          qdrant.NewQuery(1, 1, 1, 1, 1, 1, 1, 1),
+			Filter:         &qdrant.Filter{Must: []*qdrant.Condition{qdrant.NewHasID(ids[i])}},
+			Limit:          qdrant.PtrOf(uint64(1)),
+		}
+	}
+	results, err := client.QueryBatch(ctx, &qdrant.QueryBatchPoints{
+		CollectionName: collectionName,
+		QueryPoints:    queries,
+	})
+	require.NoError(t, err)
+	// The results are in the order of the queries, across the split requests.
+	require.Len(t, results, len(queries))
+	for i, result := range results {
+		require.Equal(t, ids[i].GetNum(), result.GetResult()[0].GetId().GetNum())
+	}
+}
+
+func TestRequestSplittingEmbeddings(t *testing.T) {
+	const maxRequestSize = 4096
+	points := &fakePoints{}
+	embedder := qdrant.EmbedderFunc(func(_ context.Context, _ string, inputs []*qdrant.EmbeddingInput,
+	) ([]*qdrant.Vector, error) {
+		vectors := make([]*qdrant.Vector, len(inputs))
+		for i := range vectors {
+			vectors[i] = qdrant.NewVectorDense(make([]float32, 256))
+		}
+		return vectors, nil
+	})
+	client := fakeQdrant(t, fakeServices{points: points}, &qdrant.Config{
+		Embedders:      map[string]qdrant.Embedder{"model": embedder},
+		MaxRequestSize: maxRequestSize,
+	})
+
+	// The documents are small, and only the embedded requests exceed the maximum size.
+	upsert := &qdrant.UpsertPoints{CollectionName: "collection"}
+	for i := range 20 {
+		upsert.Points = append(upsert.Points, &qdrant.PointStruct{
+			Id: qdrant.NewIDNum(uint64(i)),
+			Vectors: qdrant.NewVectorsDocument(&qdrant.Document{
+				Text:  fmt.Sprintf("document %d", i),
+				Model: "model",
+			}),
+		})
+	}
+	require.Less(t, proto.Size(upsert), maxRequestSize)
+
+	result, err := client.Upsert(context.Background(), upsert)
+	require.NoError(t, err)
+
+	require.Greater(t, len(points.upserts), 1)
+	var ids []uint64
+	for _, request := range points.upserts {
+		require.LessOrEqual(t, proto.Size(request), maxRequestSize)
+		for _, point := range request.GetPoints() {
+			require.Len(t, point.GetVectors().GetVector().GetDense().GetData(), 256)
+			ids = append(ids, point.GetId().GetNum())
+		}
+	}
+	for i, id := range ids {
+		require.Equal(t, uint64(i), id)
+	}
+	require.Len(t, ids, 20)
+	require.Equal(t, uint64(len(points.upserts)), result.GetOperationId())
+}
@@ -0,0 +1,261 @@
+package qdrant_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qdrant/go-client/qdrant"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestFilterFromJSON(t *testing.T) {
+	filter, err := qdrant.FilterFromJSON([]byte(`{
+		"must": [
+			{"key": "city", "match": {"value": "London"}},
+			{"key": "count", "match": {"value": 3}},
+			{"key": "price", "range": {"gte": 10, "lt": 20.5}},
+			{"key": "created_at", "range": {"gt": "2024-01-01T00:00:00Z"}},
+			{"key": "tags", "match": {"any": ["a", "b"]}},
+			{"is_empty": {"key": "reviews"}},
+			{"has_id": [1, "5c56c793-69f3-4fbf-87e6-c4bf54c28c26"]},
+			{"nested": {"key": "diet", "filter": {"must": {"key": "food", "match": {"value": "meat"}}}}},
+			{"should": [{"key": "color", "match": {"text": "red"}}, {"has_vector": "image"}]}
+		],
+		"must_not": [{"key": "location", "geo_radius": {"center": {"lat": 52.52, "lon": 13.405}, "radius": 1000}}],
+		"min_should": {"conditions": [{"is_null": {"key": "discount"}}], "min_count": 1}
+	}`))
+	require.NoError(t, err)
+
+	expected := &qdrant.Filter{
+		Must: []*qdrant.Condition{
+			qdrant.NewMatch("city", "London"),
+			qdrant.NewMatchInt("count", 3),
+			qdrant.NewRange("price", &qdrant.Range{Gte: qdrant.PtrOf(10.0), Lt: qdrant.PtrOf(20.5)}),
+			qdrant.NewDatetimeRange("created_at", &qdrant.DatetimeRange{
+				Gt: timestamppb.New(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+			}),
+			qdrant.NewMatchKeywords("tags", "a", "b"),
+			qdrant.NewIsEmpty("reviews"),
+			qdrant.NewHasID(qdrant.NewIDNum(1), qdrant.NewID("5c56c793-69f3-4fbf-87e6-c4bf54c28c26")),
+			qdrant.NewNestedFilter("diet", &qdrant.Filter{
+				Must: []*qdrant.Condition{qdrant.NewMatch("food", "meat")},
+			}),
+			qdrant.NewFilterAsCondition(&qdrant.Filter{
+				Should: []*qdrant.Condition{qdrant.NewMatchText("color", "red"), qdrant.NewHasVector("image")},
+			}),
+		},
+		MustNot: []*qdrant.Condition{
+			qdrant.NewGeoRadius("location", 52.52, 13.405, 1000),
+		},
+		MinShould: &qdrant.MinShould{
+			Conditions: []*qdrant.Condition{qdrant.NewIsNull("discount")},
+			MinCount:   1,
+		},
+	}
+	require.True(t, proto.Equal(expected, filter), "got %v", filter)
+
+	data, err := qdrant.FilterToJSON(filter)
+	require.NoError(t, err)
+	roundTripped, err := qdrant.FilterFromJSON(data)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(filter, roundTripped), "encoded as %s", data)
+
+	data, err = qdrant.FilterToJSON(&qdrant.Filter{
+		Must: []*qdrant.Condition{qdrant.NewMatch("city", "London")},
+	})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"must": [{"key": "city", "match": {"value": "London"}}]}`, string(data))
+}
+
+func TestQueryPointsFromJSON(t *testing.T) {
+	request, err := qdrant.QueryPointsFromJSON("books", []byte(`{
+		"prefetch": [
+			{"query": [0.1, 0.2], "using": "dense", "limit": 100},
+			{"query": {"indices": [1, 42], "values": [0.5, 0.7]}, "using": "bm25", "limit": 100}
+		],
+		"query": {"fusion": "rrf"},
+		"filter": {"must": [{"key": "city", "match": {"value": "London"}}]},
+		"params": {"hnsw_ef": 128, "exact": false},
+		"limit": 10,
+		"with_payload": ["title"],
+		"with_vector": false,
+		"shard_key": "eu"
+	}`))
+	require.NoError(t, err)
+
+	expected := &qdrant.QueryPoints{
+		CollectionName: "books",
+		Prefetch: []*qdrant.PrefetchQuery{
+			{Query: qdrant.NewQueryDense([]float32{0.1, 0.2}), Using: qdrant.PtrOf("dense"), Limit: qdrant.PtrOf(uint64(100))},
+			{
+				Query: qdrant.NewQuerySparse([]uint32{1, 42}, []float32{0.5, 0.7}),
+				Using: qdrant.PtrOf("bm25"),
+				Limit: qdrant.PtrOf(uint64(100)),
+			},
+		},
+		Query:            qdrant.NewQueryFusion(qdrant.Fusion_RRF),
+		Filter:           &qdrant.Filter{Must: []*qdrant.Condition{qdrant.NewMatch("city", "London")}},
+		Params:           &qdrant.SearchParams{HnswEf: qdrant.PtrOf(uint64(128)), Exact: qdrant.PtrOf(false)},
+		Limit:            qdrant.PtrOf(uint64(10)),
+		WithPayload:      qdrant.NewWithPayloadInclude("title"),
+		WithVectors:      qdrant.NewWithVectors(false),
+		ShardKeySelector: &qdrant.ShardKeySelector{ShardKeys: []*qdrant.ShardKey{qdrant.NewShardKey("eu")}},
+	}
+	require.True(t, proto.Equal(expected, request), "got %v", request)
+
+	data, err := qdrant.QueryPointsToJSON(request)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "collection_name")
+	roundTripped, err := qdrant.QueryPointsFromJSON("books", data)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(request, roundTripped), "encoded as %s", data)
+
+	queries := map[string]*qdrant.Query{
+		`42`:                  qdrant.NewQueryID(qdrant.NewIDNum(42)),
+		`[[0.1, 0.2], [0.3]]`: qdrant.NewQueryMulti([][]float32{{0.1, 0.2}, {0.3}}),
+		`{"text": "space", "model": "bm25"}`: qdrant.NewQueryDocument(&qdrant.Document{
+			Text: "space", Model: "bm25",
+		}),
+		`{"recommend": {"positive": [1], "negative": [[0.5]], "strategy": "best_score"}}`: qdrant.NewQueryRecommend(
+			&qdrant.RecommendInput{
+				Positive: []*qdrant.VectorInput{qdrant.NewVectorInputID(qdrant.NewIDNum(1))},
+				Negative: []*qdrant.VectorInput{qdrant.NewVectorInputDense([]float32{0.5})},
+				Strategy: qdrant.RecommendStrategy_BestScore.Enum(),
+			}),
+		`{"order_by": {"key": "price", "direction": "desc", "start_from": 10}}`: qdrant.NewQueryOrderBy(&qdrant.OrderBy{
+			Key: "price", Direction: qdrant.Direction_Desc.Enum(), StartFrom: qdrant.NewStartFromInt(10),
+		}),
+		`{"order_by": "price"}`: qdrant.NewQueryOrderBy(&qdrant.OrderBy{Key: "price"}),
+		`{"context": [{"positive": 1, "negative": 2}]}`: qdrant.NewQueryContext(&qdrant.ContextInput{
+			Pairs: []*qdrant.ContextInputPair{{
+				Positive: qdrant.NewVectorInputID(qdrant.NewIDNum(1)),
+				Negative: qdrant.NewVectorInputID(qdrant.NewIDNum(2)),
+			}},
+		}),
+		`{"nearest": [0.1], "mmr": {"diversity": 0.5}}`: qdrant.NewQueryMMR(
+			qdrant.NewVectorInputDense([]float32{0.1}), &qdrant.Mmr{Diversity: qdrant.PtrOf(float32(0.5))}),
+		`{"rrf": {"k": 60}}`:   qdrant.NewQueryRRF(&qdrant.Rrf{K: qdrant.PtrOf(uint32(60))}),
+		`{"sample": "random"}`: qdrant.NewQuerySample(qdrant.Sample_Random),
+	}
+	for body, query := range queries {
+		t.Run(body, func(t *testing.T) {
+			request, err := qdrant.QueryPointsFromJSON("books", []byte(`{"query": `+body+`}`))
+			require.NoError(t, err)
+			require.True(t, proto.Equal(query, request.GetQuery()), "got %v", request.GetQuery())
+
+			data, err := qdrant.QueryPointsToJSON(request)
+			require.NoError(t, err)
+			roundTripped, err := qdrant.QueryPointsFromJSON("books", data)
+			require.NoError(t, err)
+			require.True(t, proto.Equal(request, roundTripped), "encoded as %s", data)
+		})
+	}
+}
+
+func TestCreateCollectionFromJSON(t *testing.T) {
+	request, err := qdrant.CreateCollectionFromJSON("books", []byte(`{
+		"vectors": {
+			"dense": {"size": 384, "distance": "Cosine", "on_disk": true, "datatype": "float16"},
+			"colbert": {"size": 128, "distance": "Dot", "multivector_config": {"comparator": "max_sim"}}
+		},
+		"sparse_vectors": {"bm25": {"modifier": "idf", "index": {"on_disk": false}}},
+		"shard_number": 2,
+		"sharding_method": "custom",
+		"replication_factor": 2,
+		"hnsw_config": {"m": 32, "ef_construct": 200},
+		"optimizers_config": {"indexing_threshold": 10000, "max_optimization_threads": "auto"},
+		"quantization_config": {"scalar": {"type": "int8", "quantile": 0.99, "always_ram": true}},
+		"metadata": {"owner": "search", "version": 2}
+	}`))
+	require.NoError(t, err)
+
+	expected := &qdrant.CreateCollection{
+		CollectionName: "books",
+		VectorsConfig: qdrant.NewVectorsConfigMap(map[string]*qdrant.VectorParams{
+			"dense": {
+				Size: 384, Distance: qdrant.Distance_Cosine, OnDisk: qdrant.PtrOf(true),
+				Datatype: qdrant.Datatype_Float16.Enum(),
+			},
+			"colbert": {
+				Size: 128, Distance: qdrant.Distance_Dot,
+				MultivectorConfig: &qdrant.MultiVectorConfig{Comparator: qdrant.MultiVectorComparator_MaxSim},
+			},
+		}),
+		SparseVectorsConfig: qdrant.NewSparseVectorsConfig(map[string]*qdrant.SparseVectorParams{
+			"bm25": {Modifier: qdrant.Modifier_Idf.Enum(), Index: &qdrant.SparseIndexConfig{OnDisk: qdrant.PtrOf(false)}},
+		}),
+		ShardNumber:       qdrant.PtrOf(uint32(2)),
+		ShardingMethod:    qdrant.ShardingMethod_Custom.Enum(),
+		ReplicationFactor: qdrant.PtrOf(uint32(2)),
+		HnswConfig:        &qdrant.HnswConfigDiff{M: qdrant.PtrOf(uint64(32)), EfConstruct: qdrant.PtrOf(uint64(200))},
+		OptimizersConfig: &qdrant.OptimizersConfigDiff{
+			IndexingThreshold:      qdrant.PtrOf(uint64(10000)),
+			MaxOptimizationThreads: qdrant.NewMaxOptimizationThreadsSetting(qdrant.MaxOptimizationThreads_Auto),
+		},
+		QuantizationConfig: qdrant.NewQuantizationScalar(&qdrant.ScalarQuantization{
+			Type: qdrant.QuantizationType_Int8, Quantile: qdrant.PtrOf(float32(0.99)), AlwaysRam: qdrant.PtrOf(true),
+		}),
+		Metadata: qdrant.NewValueMap(map[string]any{"owner": "search", "version": 2}),
+	}
+	require.True(t, proto.Equal(expected, request), "got %v", request)
+
+	data, err := qdrant.CreateCollectionToJSON(request)
+	require.NoError(t, err)
+	roundTripped, err := qdrant.CreateCollectionFromJSON("books", data)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(request, roundTripped), "encoded as %s", data)
+
+	data, err = qdrant.CreateCollectionToJSON(&qdrant.CreateCollection{
+		CollectionName: "books",
+		VectorsConfig: qdrant.NewVectorsConfig(&qdrant.VectorParams{
+			Size: 4, Distance: qdrant.Distance_Euclid,
+		}),
+	})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"vectors": {"size": 4, "distance": "Euclid"}}`, string(data))
+
+	// Enums set to their zero value, such as the MaxSim comparator, are required by REST.
+	data, err = qdrant.CreateCollectionToJSON(&qdrant.CreateCollection{
+		CollectionName: "books",
+		VectorsConfig: qdrant.NewVectorsConfig(&qdrant.VectorParams{
+			Size: 4, Distance: qdrant.Distance_Dot,
+			MultivectorConfig: &qdrant.MultiVectorConfig{Comparator: qdrant.MultiVectorComparator_MaxSim},
+		}),
+	})
+	require.NoError(t, err)
+	require.JSONEq(t,
+		`{"vectors": {"size": 4, "distance": "Dot", "multivector_config": {"comparator": "max_sim"}}}`, string(data))
+
+	// REST fields without a proto equivalent are accepted when null.
+	request, err = qdrant.CreateCollectionFromJSON("books", []byte(`{
+		"vectors": {"size": 4, "distance": "Dot"},
+		"init_from": null
+	}`))
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), request.GetVectorsConfig().GetParams().GetSize())
+}
+
+func TestRESTJSONErrors(t *testing.T) {
+	_, err := qdrant.FilterFromJSON([]byte(`{"must": [{"key": "city", "match": {"valeu": "x"}}]}`))
+	require.ErrorContains(t, err, "must[0].match.valeu")
+
+	_, err = qdrant.FilterFromJSON([]byte(`{"must": [{"key": "city", "range": {"gt": true}}]}`))
+	require.ErrorContains(t, err, "must[0].range.gt")
+
+	_, err = qdrant.QueryPointsFromJSON("books", []byte(`{"query": [0.1], "limit": -1}`))
+	require.ErrorContains(t, err, "limit")
+
+	_, err = qdrant.QueryPointsFromJSON("books", []byte(`{"query": {"formula": {}}}`))
+	require.ErrorContains(t, err, "not supported")
+
+	_, err = qdrant.CreateCollectionFromJSON("books", []byte(`{"vectors": {"size": 4, "distance": "Cosinus"}}`))
+	require.ErrorContains(t, err, "vectors.distance")
+
+	_, err = qdrant.CreateCollectionFromJSON("books", []byte(`{"init_from": {"collection": "other"}}`))
+	require.ErrorContains(t, err, "init_from: not supported")
+
+	_, err = qdrant.FilterFromJSON([]byte(`{"must": []} {}`))
+	require.Error(t, err)
+}
@@ -0,0 +1,140 @@
+package qdrant_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/qdrant/go-client/qdrant"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShutdown(t *testing.T) {
+	collectionName := t.Name()
+	apiKey := "<SHUTDOWN_TEST>"
+	pageLimit := uint32(2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	container, err := standaloneQdrant(ctx, apiKey)
+	require.NoError(t, err)
+
+	err = container.Start(ctx)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		err := container.Terminate(ctx)
+		require.NoError(t, err)
+	})
+
+	host, err := container.Host(ctx)
+	require.NoError(t, err)
+
+	port, err := container.MappedPort(ctx, "6334/tcp")
+	require.NoError(t, err)
+
+	newClient := func(t *testing.T) *qdrant.Client {
+		t.Helper()
+		client, err := qdrant.NewClient(&qdrant.Config{
+			Host:   host,
+			Port:   int(port.Num()),
+			APIKey: apiKey,
+		})
+		require.NoError(t, err)
+		return client
+	}
+
+	setupClient := newClient(t)
+	err = setupClient.CreateCollection(ctx, &qdrant.CreateCollection{
+		CollectionName: collectionName,
+		VectorsConfig: qdrant.NewVectorsConfig(&qdrant.VectorParams{
+			Size:     4,
+			Distance: qdrant.Distance_Cosine,
+		}),
+	})
+	require.NoError(t, err)
+
+	points := make([]*qdrant.PointStruct, 10)
+	for i := range points {
+		points[i] = &qdrant.PointStruct{
+			Id:      qdrant.NewIDNum(uint64(i)),
+			Vectors: qdrant.NewVectors(0.1, 0.2, 0.3, 0.4),
+		}
+	}
+	wait := true
+	_, err = setupClient.Upsert(ctx, &qdrant.UpsertPoints{
+		CollectionName: collectionName,
+		Points:         points,
+		Wait:           &wait,
+	})
+	require.NoError(t, err)
+
+	t.Run("RejectsNewRequests", func(t *testing.T) {
+		client := newClient(t)
+
+		aborted, err := client.Shutdown(ctx)
+		require.NoError(t, err)
+		require.Zero(t, aborted)
+
+		_, err = client.HealthCheck(ctx)
+		require.ErrorIs(t, err, qdrant.ErrClientClosed)
+
+		_, err = client.ScrollAll(ctx, &qdrant.ScrollPoints{
+			CollectionName: collectionName,
+		}).Next()
+		require.ErrorIs(t, err, qdrant.ErrClientClosed)
+	})
+
+	t.Run("WaitsForScrollIterator", func(t *testing.T) {
+		client := newClient(t)
+
+		iter := client.ScrollAll(ctx, &qdrant.ScrollPoints{
+			CollectionName: collectionName,
+			Limit:          &pageLimit,
+		})
+		_, err := iter.Next()
+		require.NoError(t, err)
+
+		shutdownCtx, shutdownCancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer shutdownCancel()
+		aborted, err := client.Shutdown(shutdownCtx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Equal(t, 1, aborted)
+	})
+
+	t.Run("DrainsScrollIterator", func(t *testing.T) {
+		client := newClient(t)
+
+		iter := client.ScrollAll(ctx, &qdrant.ScrollPoints{
+			CollectionName: collectionName,
+			Limit:          &pageLimit,
+		})
+		_, err := iter.Next()
+		require.NoError(t, err)
+
+		done := make(chan struct{})
+		var aborted int
+		var shutdownErr error
+		go func() {
+			defer close(done)
+			aborted, shutdownErr = client.Shutdown(ctx)
+		}()
+
+		// The iterator keeps working while the client is shutting down.
+		for {
+			_, err := iter.Next()
+			if err != nil {
+				require.ErrorIs(t, err, io.EOF)
+				break
+			}
+		}
+		<-done
+		require.NoError(t, shutdownErr)
+		require.Zero(t, aborted)
+	})
+
+	err = setupClient.DeleteCollection(ctx, collectionName)
+	require.NoError(t, err)
+}
@@ -0,0 +1,102 @@
+package qdrant_test
+
+import (
+	"testing"
+
+	"github.com/qdrant/go-client/qdrant"
+	"github.com/qdrant/go-client/qdrant/sparse"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSparseHashTerm(t *testing.T) {
+	// Same as abs(mmh3.hash(term)) in Python.
+	require.Equal(t, uint32(613153351), sparse.HashTerm("hello"))
+	require.Equal(t, uint32(156908512), sparse.HashTerm("foo"))
+	require.Equal(t, uint32(0), sparse.HashTerm(""))
+}
+
+func TestSparseStemmer(t *testing.T) {
+	stemmer, err := sparse.NewSnowballStemmer("english")
+	require.NoError(t, err)
+	for word, stem := range map[string]string{
+		"running":     "run",
+		"caresses":    "caress",
+		"ponies":      "poni",
+		"ties":        "tie",
+		"cats":        "cat",
+		"gas":         "gas",
+		"agreed":      "agre",
+		"feed":        "feed",
+		"hoped":       "hope",
+		"controlling": "control",
+		"cried":       "cri",
+		"happiness":   "happi",
+		"generously":  "generous",
+		"abilities":   "abil",
+		"connection":  "connect",
+		"relational":  "relat",
+		"national":    "nation",
+		"hopeful":     "hope",
+		"skies":       "sky",
+		"succeeding":  "succeed",
+		"by":          "by",
+	} {
+		require.Equal(t, stem, stemmer.Stem(word), word)
+	}
+
+	_, err = sparse.NewSnowballStemmer("klingon")
+	require.Error(t, err)
+}
+
+func TestSparseTokenizer(t *testing.T) {
+	tokenizer, err := sparse.NewTokenizer(nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"the", "quick", "brown", "fox", "2024"}, tokenizer.Tokenize("The quick-brown FOX, 2024!"))
+
+	tokenizer, err = sparse.NewTokenizer(&qdrant.TextIndexParams{
+		Tokenizer:    qdrant.TokenizerType_Word,
+		MinTokenLen:  qdrant.PtrOf(uint64(2)),
+		MaxTokenLen:  qdrant.PtrOf(uint64(10)),
+		AsciiFolding: qdrant.PtrOf(true),
+		Stopwords:    &qdrant.StopwordsSet{Languages: []string{"english"}, Custom: []string{"Jumps"}},
+		Stemmer:      qdrant.NewStemmingAlgorithmSnowball(&qdrant.SnowballParams{Language: "english"}),
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"cafe", "running", "dogs"}, tokenizer.WithStemmer(nil).Tokenize("A Café running jumps over the dogs"))
+	require.Equal(t, []string{"cafe", "run", "dog"}, tokenizer.Tokenize("A Café running jumps over the dogs x extraordinarily"))
+
+	tokenizer, err = sparse.NewTokenizer(&qdrant.TextIndexParams{
+		Tokenizer: qdrant.TokenizerType_Whitespace,
+		Lowercase: qdrant.PtrOf(false),
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Hello,", "World!"}, tokenizer.Tokenize("Hello, World!"))
+
+	_, err = sparse.NewTokenizer(&qdrant.TextIndexParams{Tokenizer: qdrant.TokenizerType_Prefix})
+	require.Error(t, err)
+	_, err = sparse.NewTokenizer(&qdrant.TextIndexParams{Stopwords: &qdrant.StopwordsSet{Languages: []string{"klingon"}}})
+	require.Error(t, err)
+}
+
+func TestSparseBM25(t *testing.T) {
+	tokenizer, err := sparse.NewTokenizer(nil)
+	require.NoError(t, err)
+	encoder := sparse.NewBM25(tokenizer, &sparse.BM25Options{AvgDocLen: 4})
+
+	indices, values := encoder.EncodeDocument("fox fox dog cat")
+	require.Len(t, indices, 3)
+	require.IsIncreasing(t, indices)
+	weights := make(map[uint32]float32)
+	for i, index := range indices {
+		weights[index] = values[i]
+	}
+	// With a document of average length, the weight is tf * (k + 1) / (tf + k).
+	require.InDelta(t, 2*2.2/3.2, weights[sparse.HashTerm("fox")], 1e-6)
+	require.InDelta(t, 2.2/2.2, weights[sparse.HashTerm("dog")], 1e-6)
+
+	indices, values = encoder.EncodeQuery("dog dog fox")
+	require.ElementsMatch(t, []uint32{sparse.HashTerm("dog"), sparse.HashTerm("fox")}, indices)
+	require.Equal(t, []float32{1, 1}, values)
+
+	require.Equal(t, qdrant.Modifier_Idf, sparse.VectorParams().GetModifier())
+}
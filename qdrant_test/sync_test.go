@@ -0,0 +1,116 @@
+package qdrant_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/qdrant/go-client/qdrant"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncPoints(t *testing.T) {
+	collectionName := t.Name()
+	apiKey := "<SYNC_POINTS_TEST>"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	container, err := standaloneQdrant(ctx, apiKey)
+	require.NoError(t, err)
+
+	err = container.Start(ctx)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		err := container.Terminate(ctx)
+		require.NoError(t, err)
+	})
+
+	host, err := container.Host(ctx)
+	require.NoError(t, err)
+
+	port, err := container.MappedPort(ctx, "6334/tcp")
+	require.NoError(t, err)
+
+	client, err := qdrant.NewClient(&qdrant.Config{
+		Host:   host,
+		Port:   int(port.Num()),
+		APIKey: apiKey,
+	})
+	require.NoError(t, err)
+
+	err = client.CreateCollection(ctx, &qdrant.CreateCollection{
+		CollectionName: collectionName,
+		VectorsConfig: qdrant.NewVectorsConfig(&qdrant.VectorParams{
+			Size:     2,
+			Distance: qdrant.Distance_Dot,
+		}),
+	})
+	require.NoError(t, err)
+
+	point := func(id uint64, title string) *qdrant.PointStruct {
+		return &qdrant.PointStruct{
+			Id:      qdrant.NewIDNum(id),
+			Vectors: qdrant.NewVectors(float32(id), 1),
+			Payload: qdrant.NewValueMap(map[string]any{"title": title}),
+		}
+	}
+	options := &qdrant.SyncOptions{
+		HashKey:   "_hash",
+		Delete:    true,
+		BatchSize: 2,
+		Wait:      true,
+	}
+
+	source := []*qdrant.PointStruct{point(1, "a"), point(2, "b"), point(3, "c")}
+	report, err := client.SyncPoints(ctx, collectionName, slices.Values(source), options)
+	require.NoError(t, err)
+	require.Equal(t, &qdrant.SyncReport{Inserted: 3}, report)
+	// The source points are not modified.
+	require.NotContains(t, source[0].GetPayload(), "_hash")
+
+	report, err = client.SyncPoints(ctx, collectionName, slices.Values(source), options)
+	require.NoError(t, err)
+	require.Equal(t, &qdrant.SyncReport{Unchanged: 3}, report)
+
+	source = []*qdrant.PointStruct{point(1, "a"), point(3, "C"), point(4, "d")}
+	report, err = client.SyncPoints(ctx, collectionName, slices.Values(source), options)
+	require.NoError(t, err)
+	require.Equal(t, &qdrant.SyncReport{Inserted: 1, Updated: 1, Unchanged: 1, Deleted: 1}, report)
+
+	points, err := client.Get(ctx, &qdrant.GetPoints{
+		CollectionName: collectionName,
+		Ids:            []*qdrant.PointId{qdrant.NewIDNum(2), qdrant.NewIDNum(3)},
+		WithPayload:    qdrant.NewWithPayload(true),
+	})
+	require.NoError(t, err)
+	require.Len(t, points, 1)
+	require.Equal(t, "C", points[0].GetPayload()["title"].GetStringValue())
+
+	_, err = client.SyncPoints(ctx, collectionName, slices.Values([]*qdrant.PointStruct{{}}), nil)
+	require.Error(t, err)
+}
+
+func TestSyncPointsDuplicateIDs(t *testing.T) {
+	points := &fakePoints{}
+	client := fakeQdrant(t, fakeServices{points: points}, &qdrant.Config{})
+
+	source := []*qdrant.PointStruct{
+		{Id: qdrant.NewIDNum(1), Vectors: qdrant.NewVectors(1, 2), Payload: qdrant.NewValueMap(map[string]any{"v": 1})},
+		{Id: qdrant.NewIDNum(2), Vectors: qdrant.NewVectors(1, 2)},
+		{Id: qdrant.NewIDNum(1), Vectors: qdrant.NewVectors(1, 2), Payload: qdrant.NewValueMap(map[string]any{"v": 2})},
+	}
+	report, err := client.SyncPoints(context.Background(), "collection", slices.Values(source), &qdrant.SyncOptions{
+		HashKey: "hash",
+	})
+	require.NoError(t, err)
+	require.Equal(t, &qdrant.SyncReport{Inserted: 2}, report)
+
+	require.Len(t, points.upserts, 1)
+	upserted := points.upserts[0].GetPoints()
+	require.Len(t, upserted, 2)
+	require.Equal(t, uint64(1), upserted[0].GetId().GetNum())
+	require.Equal(t, int64(2), upserted[0].GetPayload()["v"].GetIntegerValue())
+	require.Equal(t, uint64(2), upserted[1].GetId().GetNum())
+}
@@ -0,0 +1,228 @@
+package qdrant_test
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"log/slog"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qdrant/go-client/qdrant"
+	"github.com/stretchr/testify/require"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	der  []byte
+}
+
+func newTestCert(t *testing.T, serial int64, name string, parent *testCert, isCA bool) *testCert {
+	t.Helper()
+	return newTestCertExpiring(t, serial, name, parent, isCA, time.Now().Add(90*24*time.Hour))
+}
+
+func newTestCertExpiring(t *testing.T, serial int64, name string, parent *testCert, isCA bool,
+	notAfter time.Time,
+) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: name},
+		DNSNames:              []string{name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	signer, signerKey := template, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCert{cert: cert, key: key, der: der}
+}
+
+func (c *testCert) write(t *testing.T, certPath, keyPath string, modTime time.Time) {
+	t.Helper()
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.der})
+	require.NoError(t, os.WriteFile(certPath, certPEM, 0o600))
+	require.NoError(t, os.Chtimes(certPath, modTime, modTime))
+	if keyPath == "" {
+		return
+	}
+	keyDER, err := x509.MarshalECPrivateKey(c.key)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	require.NoError(t, os.WriteFile(keyPath, keyPEM, 0o600))
+	require.NoError(t, os.Chtimes(keyPath, modTime, modTime))
+}
+
+func (c *testCert) tlsCertificate() tls.Certificate {
+	return tls.Certificate{Certificate: [][]byte{c.der}, PrivateKey: c.key, Leaf: c.cert}
+}
+
+// handshake runs a TLS handshake over an in-memory connection and returns
+// the serial number of the client certificate seen by the server.
+func handshake(t *testing.T, clientConfig *tls.Config, server *testCert, ca *testCert) (*big.Int, error) {
+	t.Helper()
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	serverConfig := &tls.Config{
+		MinVersion:   tls.VersionTLS13,
+		Certificates: []tls.Certificate{server.tlsCertificate()},
+		ClientCAs:    roots,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	defer serverConn.Close()
+
+	serialCh := make(chan *big.Int, 1)
+	go func() {
+		srv := tls.Server(serverConn, serverConfig)
+		if err := srv.Handshake(); err != nil {
+			serialCh <- nil
+			return
+		}
+		serialCh <- srv.ConnectionState().PeerCertificates[0].SerialNumber
+	}()
+
+	err := tls.Client(clientConn, clientConfig).Handshake()
+	if err != nil {
+		serverConn.Close()
+		<-serialCh
+		return nil, err
+	}
+	return <-serialCh, nil
+}
+
+func TestMTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	caPath := filepath.Join(dir, "ca.pem")
+	certPath := filepath.Join(dir, "client.pem")
+	keyPath := filepath.Join(dir, "client-key.pem")
+	serverName := "qdrant.internal"
+	now := time.Now()
+
+	ca := newTestCert(t, 1, "test-ca", nil, true)
+	server := newTestCert(t, 2, serverName, ca, false)
+	ca.write(t, caPath, "", now)
+	newTestCert(t, 10, "client", ca, false).write(t, certPath, keyPath, now)
+
+	mtls := &qdrant.MTLSConfig{
+		CAFile:     caPath,
+		CertFile:   certPath,
+		KeyFile:    keyPath,
+		ServerName: serverName,
+	}
+	clientConfig, err := mtls.ClientTLSConfig(nil)
+	require.NoError(t, err)
+	require.Equal(t, serverName, clientConfig.ServerName)
+
+	t.Run("Handshake", func(t *testing.T) {
+		serial, err := handshake(t, clientConfig, server, ca)
+		require.NoError(t, err)
+		require.Equal(t, int64(10), serial.Int64())
+	})
+
+	t.Run("ReloadClientCertificate", func(t *testing.T) {
+		newTestCert(t, 11, "client", ca, false).write(t, certPath, keyPath, now.Add(time.Minute))
+
+		serial, err := handshake(t, clientConfig, server, ca)
+		require.NoError(t, err)
+		require.Equal(t, int64(11), serial.Int64())
+	})
+
+	t.Run("ReloadCA", func(t *testing.T) {
+		otherCA := newTestCert(t, 20, "other-ca", nil, true)
+		otherCA.write(t, caPath, "", now.Add(2*time.Minute))
+
+		_, err := handshake(t, clientConfig, server, ca)
+		require.Error(t, err)
+
+		ca.write(t, caPath, "", now.Add(3*time.Minute))
+		_, err = handshake(t, clientConfig, server, ca)
+		require.NoError(t, err)
+	})
+
+	t.Run("WrongServerName", func(t *testing.T) {
+		wrongName, err := (&qdrant.MTLSConfig{
+			CAFile:     caPath,
+			CertFile:   certPath,
+			KeyFile:    keyPath,
+			ServerName: "other.internal",
+		}).ClientTLSConfig(nil)
+		require.NoError(t, err)
+
+		_, err = handshake(t, wrongName, server, ca)
+		require.Error(t, err)
+	})
+
+	t.Run("MissingKeyFile", func(t *testing.T) {
+		_, err := (&qdrant.MTLSConfig{CertFile: certPath}).ClientTLSConfig(nil)
+		require.Error(t, err)
+	})
+
+	t.Run("MissingCAFile", func(t *testing.T) {
+		_, err := (&qdrant.MTLSConfig{CAFile: filepath.Join(dir, "missing.pem")}).ClientTLSConfig(nil)
+		require.Error(t, err)
+	})
+}
+
+func TestMTLSConfigExpiryWarning(t *testing.T) {
+	var buf bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+	t.Cleanup(func() {
+		slog.SetDefault(defaultLogger)
+	})
+
+	dir := t.TempDir()
+	caPath := filepath.Join(dir, "ca.pem")
+	certPath := filepath.Join(dir, "client.pem")
+	keyPath := filepath.Join(dir, "client-key.pem")
+	serverName := "qdrant.internal"
+	now := time.Now()
+
+	ca := newTestCert(t, 1, "test-ca", nil, true)
+	server := newTestCert(t, 2, serverName, ca, false)
+	ca.write(t, caPath, "", now)
+	newTestCertExpiring(t, 10, "client", ca, false, now.Add(3*time.Second)).write(t, certPath, keyPath, now)
+
+	clientConfig, err := (&qdrant.MTLSConfig{
+		CAFile:        caPath,
+		CertFile:      certPath,
+		KeyFile:       keyPath,
+		ServerName:    serverName,
+		ExpiryWarning: 2 * time.Second,
+	}).ClientTLSConfig(nil)
+	require.NoError(t, err)
+	require.Empty(t, buf.String())
+
+	// The certificate enters the warning period without being reloaded.
+	time.Sleep(1500 * time.Millisecond)
+	for range 2 {
+		_, err = handshake(t, clientConfig, server, ca)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 1, strings.Count(buf.String(), "TLS certificate is about to expire."))
+}
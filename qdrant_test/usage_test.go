@@ -0,0 +1,142 @@
+package qdrant_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qdrant/go-client/qdrant"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsageCollectorRecord(t *testing.T) {
+	collector := qdrant.NewUsageCollector()
+	queryKey := qdrant.UsageKey{Collection: "books", Operation: "Query", Tenant: "search"}
+	upsertKey := qdrant.UsageKey{Collection: "books", Operation: "Upsert"}
+
+	collector.Record(queryKey, &qdrant.Usage{
+		Hardware: &qdrant.HardwareUsage{Cpu: 3, VectorIoRead: 100},
+		Inference: &qdrant.InferenceUsage{Models: map[string]*qdrant.ModelUsage{
+			"bm25": {Tokens: 7},
+		}},
+	})
+	collector.Record(queryKey, &qdrant.Usage{
+		Hardware: &qdrant.HardwareUsage{Cpu: 2, PayloadIoRead: 10},
+		Inference: &qdrant.InferenceUsage{Models: map[string]*qdrant.ModelUsage{
+			"bm25": {Tokens: 3},
+		}},
+	})
+	collector.Record(upsertKey, nil)
+
+	snapshot := collector.Snapshot()
+	require.Len(t, snapshot, 2)
+	require.Equal(t, qdrant.UsageTotals{
+		Requests:        2,
+		CPU:             5,
+		PayloadIORead:   10,
+		VectorIORead:    100,
+		InferenceTokens: map[string]uint64{"bm25": 10},
+	}, snapshot[queryKey])
+	require.Equal(t, qdrant.UsageTotals{Requests: 1}, snapshot[upsertKey])
+
+	// Snapshots are independent of the collector.
+	snapshot[queryKey].InferenceTokens["bm25"] = 0
+	require.Equal(t, uint64(10), collector.Snapshot()[queryKey].InferenceTokens["bm25"])
+
+	total := collector.Total()
+	require.Equal(t, uint64(3), total.Requests)
+	require.Equal(t, uint64(5), total.CPU)
+
+	reset := collector.Reset()
+	require.Len(t, reset, 2)
+	require.Empty(t, collector.Snapshot())
+}
+
+func TestUsageCollector(t *testing.T) {
+	collectionName := t.Name()
+	apiKey := "<USAGE_COLLECTOR_TEST>"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	container, err := standaloneQdrant(ctx, apiKey)
+	require.NoError(t, err)
+
+	err = container.Start(ctx)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		err := container.Terminate(ctx)
+		require.NoError(t, err)
+	})
+
+	host, err := container.Host(ctx)
+	require.NoError(t, err)
+
+	port, err := container.MappedPort(ctx, "6334/tcp")
+	require.NoError(t, err)
+
+	clientUsage := qdrant.NewUsageCollector()
+	client, err := qdrant.NewClient(&qdrant.Config{
+		Host:           host,
+		Port:           int(port.Num()),
+		APIKey:         apiKey,
+		UsageCollector: clientUsage,
+	})
+	require.NoError(t, err)
+
+	err = client.CreateCollection(ctx, &qdrant.CreateCollection{
+		CollectionName: collectionName,
+		VectorsConfig: qdrant.NewVectorsConfig(&qdrant.VectorParams{
+			Size:     4,
+			Distance: qdrant.Distance_Cosine,
+		}),
+	})
+	require.NoError(t, err)
+
+	_, err = client.Upsert(ctx, &qdrant.UpsertPoints{
+		CollectionName: collectionName,
+		Wait:           qdrant.PtrOf(true),
+		Points: []*qdrant.PointStruct{
+			{Id: qdrant.NewIDNum(1), Vectors: qdrant.NewVectors(0.1, 0.2, 0.3, 0.4)},
+		},
+	})
+	require.NoError(t, err)
+
+	contextUsage := qdrant.NewUsageCollector()
+	tenantCtx := qdrant.WithUsageTenant(qdrant.WithUsageCollector(ctx, contextUsage), "team-a")
+	for range 2 {
+		_, err = client.Query(tenantCtx, &qdrant.QueryPoints{
+			CollectionName: collectionName,
+			Query:          qdrant.NewQuery(0.1, 0.2, 0.3, 0.4),
+		})
+		require.NoError(t, err)
+	}
+
+	queryKey := qdrant.UsageKey{Collection: collectionName, Operation: "Query", Tenant: "team-a"}
+	upsertKey := qdrant.UsageKey{Collection: collectionName, Operation: "Upsert"}
+
+	clientSnapshot := clientUsage.Snapshot()
+	require.Equal(t, uint64(1), clientSnapshot[upsertKey].Requests)
+	require.Equal(t, uint64(2), clientSnapshot[queryKey].Requests)
+
+	contextSnapshot := contextUsage.Snapshot()
+	require.Len(t, contextSnapshot, 1)
+	require.Equal(t, uint64(2), contextSnapshot[queryKey].Requests)
+
+	err = client.DeleteCollection(ctx, collectionName)
+	require.NoError(t, err)
+}
+
+func TestUsageCollectorAttachedTwice(t *testing.T) {
+	usage := qdrant.NewUsageCollector()
+	client := fakeQdrant(t, fakeServices{points: &fakePoints{}}, &qdrant.Config{UsageCollector: usage})
+
+	ctx := qdrant.WithUsageCollector(context.Background(), usage)
+	for range 2 {
+		_, err := client.GetPointsClient().Search(ctx, &qdrant.SearchPoints{CollectionName: "collection"})
+		require.NoError(t, err)
+	}
+
+	key := qdrant.UsageKey{Collection: "collection", Operation: "Search"}
+	require.Equal(t, map[qdrant.UsageKey]qdrant.UsageTotals{key: {Requests: 2}}, usage.Snapshot())
+}
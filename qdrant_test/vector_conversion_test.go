@@ -0,0 +1,132 @@
+package qdrant_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/qdrant/go-client/qdrant"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeVector(t *testing.T) {
+	vector := []float32{3, 4}
+	require.InDeltaSlice(t, []float32{0.6, 0.8}, qdrant.NormalizeVector(vector), 1e-6)
+	require.Equal(t, []float32{3, 4}, vector)
+	require.Equal(t, []float32{0, 0}, qdrant.NormalizeVector([]float32{0, 0}))
+
+	multi := qdrant.NormalizeMultiVector([][]float32{{0, 2}, {1, 0}})
+	require.Equal(t, [][]float32{{0, 1}, {1, 0}}, multi)
+}
+
+func TestQuantizeUint8(t *testing.T) {
+	quantized := qdrant.QuantizeUint8([]float32{-0.5, 0, 0.5, 0.999, 2}, 255)
+	require.Equal(t, []float32{0, 0, 128, 255, 255}, quantized)
+	require.InDeltaSlice(t, []float32{0, 0, 0.5, 1, 1}, qdrant.DequantizeUint8(quantized, 255), 1.0/255)
+}
+
+func TestFloat16(t *testing.T) {
+	for value, bits := range map[float32]uint16{
+		0:                     0x0000,
+		1:                     0x3c00,
+		-2:                    0xc000,
+		0.5:                   0x3800,
+		65504:                 0x7bff,
+		65520:                 0x7c00,
+		float32(math.Inf(-1)): 0xfc00,
+		5.960464477539063e-8:  0x0001,
+		6.103515625e-5:        0x0400,
+		1.0009765625:          0x3c01,
+	} {
+		require.Equal(t, bits, qdrant.Float32ToFloat16(value), value)
+		if !math.IsInf(float64(value), 0) && value != 65520 {
+			require.Equal(t, value, qdrant.Float16ToFloat32(bits), value)
+		}
+	}
+	// Ties round to even.
+	require.Equal(t, uint16(0x3c00), qdrant.Float32ToFloat16(1+1.0/2048))
+	require.Equal(t, uint16(0x3c02), qdrant.Float32ToFloat16(1+3.0/2048))
+	require.True(t, math.IsNaN(float64(qdrant.Float16ToFloat32(qdrant.Float32ToFloat16(float32(math.NaN()))))))
+
+	vector := []float32{0.1, -1.5, 1000.3}
+	require.Equal(t, qdrant.RoundFloat16(vector), qdrant.DecodeFloat16(qdrant.EncodeFloat16(vector)))
+	require.InDeltaSlice(t, vector, qdrant.RoundFloat16(vector), 0.5)
+}
+
+func TestNormalizeCosineVectors(t *testing.T) {
+	collectionName := t.Name()
+	apiKey := "<NORMALIZE_COSINE_VECTORS_TEST>"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	container, err := standaloneQdrant(ctx, apiKey)
+	require.NoError(t, err)
+
+	err = container.Start(ctx)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		err := container.Terminate(ctx)
+		require.NoError(t, err)
+	})
+
+	host, err := container.Host(ctx)
+	require.NoError(t, err)
+
+	port, err := container.MappedPort(ctx, "6334/tcp")
+	require.NoError(t, err)
+
+	client, err := qdrant.NewClient(&qdrant.Config{
+		Host:                   host,
+		Port:                   int(port.Num()),
+		APIKey:                 apiKey,
+		NormalizeCosineVectors: true,
+	})
+	require.NoError(t, err)
+
+	err = client.CreateCollection(ctx, &qdrant.CreateCollection{
+		CollectionName: collectionName,
+		VectorsConfig: qdrant.NewVectorsConfigMap(map[string]*qdrant.VectorParams{
+			"cosine": {Size: 2, Distance: qdrant.Distance_Cosine},
+			"dot":    {Size: 2, Distance: qdrant.Distance_Dot},
+		}),
+	})
+	require.NoError(t, err)
+
+	request := &qdrant.UpsertPoints{
+		CollectionName: collectionName,
+		Wait:           qdrant.PtrOf(true),
+		Points: []*qdrant.PointStruct{{
+			Id: qdrant.NewIDNum(1),
+			Vectors: qdrant.NewVectorsMap(map[string]*qdrant.Vector{
+				"cosine": qdrant.NewVectorDense([]float32{3, 4}),
+				"dot":    qdrant.NewVectorDense([]float32{3, 4}),
+			}),
+		}},
+	}
+	_, err = client.Upsert(ctx, request)
+	require.NoError(t, err)
+	// The caller's request is not modified.
+	require.Equal(t, []float32{3, 4}, request.GetPoints()[0].GetVectors().GetVectors().GetVectors()["cosine"].GetDense().GetData())
+
+	points, err := client.Get(ctx, &qdrant.GetPoints{
+		CollectionName: collectionName,
+		Ids:            []*qdrant.PointId{qdrant.NewIDNum(1)},
+		WithVectors:    qdrant.NewWithVectors(true),
+	})
+	require.NoError(t, err)
+	require.Len(t, points, 1)
+	vectors := points[0].GetVectors().GetVectors().GetVectors()
+	require.InDeltaSlice(t, []float32{0.6, 0.8}, vectors["cosine"].GetDenseVector().GetData(), 1e-6)
+	require.Equal(t, []float32{3, 4}, vectors["dot"].GetDenseVector().GetData())
+
+	scored, err := client.Query(ctx, &qdrant.QueryPoints{
+		CollectionName: collectionName,
+		Query:          qdrant.NewQuery(6, 8),
+		Using:          qdrant.PtrOf("dot"),
+	})
+	require.NoError(t, err)
+	require.Len(t, scored, 1)
+	require.InDelta(t, 50, scored[0].GetScore(), 1e-3)
+}
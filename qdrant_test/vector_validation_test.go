@@ -0,0 +1,145 @@
+package qdrant_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qdrant/go-client/qdrant"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateVectors(t *testing.T) {
+	collectionName := t.Name()
+	apiKey := "<VALIDATE_VECTORS_TEST>"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	container, err := standaloneQdrant(ctx, apiKey)
+	require.NoError(t, err)
+
+	err = container.Start(ctx)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		err := container.Terminate(ctx)
+		require.NoError(t, err)
+	})
+
+	host, err := container.Host(ctx)
+	require.NoError(t, err)
+
+	port, err := container.MappedPort(ctx, "6334/tcp")
+	require.NoError(t, err)
+
+	client, err := qdrant.NewClient(&qdrant.Config{
+		Host:            host,
+		Port:            int(port.Num()),
+		APIKey:          apiKey,
+		ValidateVectors: true,
+	})
+	require.NoError(t, err)
+
+	err = client.CreateCollection(ctx, &qdrant.CreateCollection{
+		CollectionName: collectionName,
+		VectorsConfig: qdrant.NewVectorsConfigMap(map[string]*qdrant.VectorParams{
+			"image": {
+				Size:     3,
+				Distance: qdrant.Distance_Dot,
+				Datatype: qdrant.Datatype_Uint8.Enum(),
+			},
+			"colbert": {
+				Size:     2,
+				Distance: qdrant.Distance_Cosine,
+				MultivectorConfig: &qdrant.MultiVectorConfig{
+					Comparator: qdrant.MultiVectorComparator_MaxSim,
+				},
+			},
+		}),
+		SparseVectorsConfig: qdrant.NewSparseVectorsConfig(map[string]*qdrant.SparseVectorParams{
+			"bm25": {},
+		}),
+	})
+	require.NoError(t, err)
+
+	point := func(id uint64, vectors map[string]*qdrant.Vector) *qdrant.PointStruct {
+		return &qdrant.PointStruct{Id: qdrant.NewIDNum(id), Vectors: qdrant.NewVectorsMap(vectors)}
+	}
+	points := []*qdrant.PointStruct{
+		point(1, map[string]*qdrant.Vector{
+			"image":   qdrant.NewVectorDense([]float32{1, 2, 3}),
+			"colbert": qdrant.NewVectorMulti([][]float32{{1, 2}, {3, 4}}),
+			"bm25":    qdrant.NewVectorSparse([]uint32{1, 5}, []float32{0.5, 0.2}),
+		}),
+		point(2, map[string]*qdrant.Vector{"image": qdrant.NewVectorDense([]float32{1, 2})}),
+		point(3, map[string]*qdrant.Vector{"colbert": qdrant.NewVectorMulti([][]float32{{1, 2}, {3}})}),
+		point(4, map[string]*qdrant.Vector{"bm25": qdrant.NewVectorSparse([]uint32{1, 5}, []float32{0.5})}),
+		point(5, map[string]*qdrant.Vector{"unknown": qdrant.NewVectorDense([]float32{1})}),
+		point(6, map[string]*qdrant.Vector{"image": qdrant.NewVectorDense([]float32{1, 2, 300})}),
+		point(7, map[string]*qdrant.Vector{"colbert": qdrant.NewVectorDense([]float32{1, 2})}),
+	}
+
+	t.Run("ValidatePoints", func(t *testing.T) {
+		err := client.ValidatePoints(ctx, collectionName, points)
+		var validationErr *qdrant.VectorValidationError
+		require.ErrorAs(t, err, &validationErr)
+		require.Equal(t, []int{1, 2, 3, 4, 5, 6}, validationErr.Indices(0))
+		require.Equal(t, "image", validationErr.Errors[0].Vector)
+		require.Equal(t, uint64(2), validationErr.Errors[0].ID.GetNum())
+
+		require.NoError(t, client.ValidatePoints(ctx, collectionName, points[:1]))
+	})
+
+	t.Run("Upsert", func(t *testing.T) {
+		_, err := client.Upsert(ctx, &qdrant.UpsertPoints{
+			CollectionName: collectionName,
+			Wait:           qdrant.PtrOf(true),
+			Points:         points,
+		})
+		var validationErr *qdrant.VectorValidationError
+		require.ErrorAs(t, err, &validationErr)
+		require.Len(t, validationErr.Errors, 6)
+
+		_, err = client.Upsert(ctx, &qdrant.UpsertPoints{
+			CollectionName: collectionName,
+			Wait:           qdrant.PtrOf(true),
+			Points:         points[:1],
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("UpdateVectors", func(t *testing.T) {
+		_, err := client.UpdateVectors(ctx, &qdrant.UpdatePointVectors{
+			CollectionName: collectionName,
+			Points: []*qdrant.PointVectors{
+				{Id: qdrant.NewIDNum(1), Vectors: qdrant.NewVectorsMap(map[string]*qdrant.Vector{
+					"image": qdrant.NewVectorDense([]float32{4, 5, 6}),
+				})},
+				{Id: qdrant.NewIDNum(1), Vectors: qdrant.NewVectorsMap(map[string]*qdrant.Vector{
+					"image": qdrant.NewVectorDense([]float32{4, 5, 6, 7}),
+				})},
+			},
+		})
+		var validationErr *qdrant.VectorValidationError
+		require.ErrorAs(t, err, &validationErr)
+		require.Equal(t, []int{1}, validationErr.Indices(0))
+	})
+
+	t.Run("UpdateBatch", func(t *testing.T) {
+		_, err := client.UpdateBatch(ctx, &qdrant.UpdateBatchPoints{
+			CollectionName: collectionName,
+			Operations: []*qdrant.PointsUpdateOperation{
+				qdrant.NewPointsUpdateUpsert(&qdrant.PointsUpdateOperation_PointStructList{
+					Points: points[:2],
+				}),
+				qdrant.NewPointsUpdateUpsert(&qdrant.PointsUpdateOperation_PointStructList{
+					Points: points[2:4],
+				}),
+			},
+		})
+		var validationErr *qdrant.VectorValidationError
+		require.ErrorAs(t, err, &validationErr)
+		require.Equal(t, []int{1}, validationErr.Indices(0))
+		require.Equal(t, []int{0, 1}, validationErr.Indices(1))
+	})
+}